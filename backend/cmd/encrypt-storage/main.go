@@ -0,0 +1,62 @@
+// Comando encrypt-storage cifra os arquivos gravados antes da criptografia em
+// repouso ser ativada. Usa o mesmo armazenamento e chave do servidor
+// (STORAGE_DRIVER, STORAGE_ENCRYPTION_KEY/STORAGE_ENCRYPTION_KEY_FILE) e pode
+// ser executado de novo com segurança: arquivos já cifrados são ignorados.
+package main
+
+import (
+	"context"
+	"log"
+
+	"cloud-reader/backend/internal/shared/storage"
+	"cloud-reader/backend/internal/wire"
+)
+
+// prefixes são as áreas do armazenamento com arquivos de livros
+// (blobs endereçados por hash, o layout antigo por usuário e as capas)
+var prefixes = []string{"blobs/", "books/", "covers/"}
+
+func main() {
+	cfg := wire.InitializeConfig()
+
+	masterKey, err := storage.LoadMasterKey(cfg)
+	if err != nil {
+		log.Fatal("Erro ao carregar chave de criptografia:", err)
+	}
+	if masterKey == nil {
+		log.Fatal("Configure STORAGE_ENCRYPTION_KEY ou STORAGE_ENCRYPTION_KEY_FILE antes de migrar")
+	}
+
+	store, err := wire.InitializeStorage(cfg)
+	if err != nil {
+		log.Fatal("Erro ao inicializar armazenamento:", err)
+	}
+
+	ctx := context.Background()
+	var encrypted, skipped, failed int
+	for _, prefix := range prefixes {
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			log.Fatalf("Erro ao listar %s: %v", prefix, err)
+		}
+
+		for _, object := range objects {
+			done, err := storage.EncryptInPlace(ctx, store, object.Key)
+			switch {
+			case err != nil:
+				failed++
+				log.Printf("Erro ao cifrar %s: %v", object.Key, err)
+			case done:
+				encrypted++
+				log.Printf("Cifrado: %s", object.Key)
+			default:
+				skipped++
+			}
+		}
+	}
+
+	log.Printf("Migração concluída: %d cifrados, %d já cifrados, %d com erro", encrypted, skipped, failed)
+	if failed > 0 {
+		log.Fatal("Alguns arquivos não foram cifrados; execute o comando novamente")
+	}
+}
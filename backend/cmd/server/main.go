@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
+	adminHttp "cloud-reader/backend/internal/admin/infrastructure/http"
 	authDomain "cloud-reader/backend/internal/auth/domain"
 	authHttp "cloud-reader/backend/internal/auth/infrastructure/http"
 	bookDomain "cloud-reader/backend/internal/books/domain"
 	bookHttp "cloud-reader/backend/internal/books/infrastructure/http"
+	bookRepository "cloud-reader/backend/internal/books/infrastructure/repository"
+	exportDomain "cloud-reader/backend/internal/export/domain"
+	exportHttp "cloud-reader/backend/internal/export/infrastructure/http"
+	exportRepository "cloud-reader/backend/internal/export/infrastructure/repository"
 	"cloud-reader/backend/internal/shared/database"
 	"cloud-reader/backend/internal/shared/middleware"
 	"cloud-reader/backend/internal/wire"
@@ -28,15 +34,45 @@ func main() {
 	defer database.Close()
 
 	// Executa migrations automáticas (cria tabelas se não existirem)
-	if err := db.AutoMigrate(&authDomain.User{}, &bookDomain.Book{}); err != nil {
+	if err := db.AutoMigrate(
+		&authDomain.User{},
+		&authDomain.Session{},
+		&authDomain.RefreshToken{},
+		&authDomain.UserToken{},
+		&authDomain.RecoveryCode{},
+		&authDomain.LoginAttempt{},
+		&authDomain.APIKey{},
+		&authDomain.UserIdentity{},
+		&authDomain.OIDCLoginState{},
+		&bookDomain.Book{},
+		&bookDomain.BookMetadata{},
+		&bookDomain.Blob{},
+		&bookDomain.Upload{},
+		&bookDomain.UserStorage{},
+		&exportDomain.ExportJob{},
+	); err != nil {
 		log.Printf("Aviso: Erro ao executar migrations: %v", err)
 	} else {
 		log.Println("Migrations executadas com sucesso")
 	}
 
+	// Índice único de conteúdo por usuário (parcial, criado fora do AutoMigrate)
+	if err := bookRepository.EnsureContentIndex(db); err != nil {
+		log.Printf("Aviso: Erro ao criar índice de conteúdo dos livros: %v", err)
+	}
+	if err := exportRepository.EnsureActiveIndex(db); err != nil {
+		log.Printf("Aviso: Erro ao criar índice de exportações ativas: %v", err)
+	}
+
 	// Configura o router do Gin
 	r := gin.Default()
 
+	// O IP do cliente (usado nos limites de login) só vem de X-Forwarded-For
+	// quando a conexão parte de um proxy listado em TRUSTED_PROXIES
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("TRUSTED_PROXIES inválido:", err)
+	}
+
 	// Middleware CORS
 	r.Use(middleware.CORSMiddleware())
 
@@ -50,12 +86,61 @@ func main() {
 		api.GET("/", welcome)
 
 		// Registra rotas de autenticação
-		authHandler := wire.InitializeAuthHandler(db)
-		authHttp.RegisterRoutes(api, authHandler)
+		authService, err := wire.InitializeAuthService(db, cfg)
+		if err != nil {
+			log.Fatal("Erro ao inicializar autenticação:", err)
+		}
+		// Middleware de autenticação das rotas protegidas
+		authMiddleware := wire.InitializeAuthMiddleware(authService, cfg)
+
+		authHandler := wire.InitializeAuthHandler(authService)
+		authHttp.RegisterRoutes(api, authHandler, authMiddleware)
+
+		// Login via OpenID Connect (opcional)
+		if cfg.OIDCEnabled() {
+			oidcHandler := wire.InitializeOIDCHandler(db, cfg, authService)
+			authHttp.RegisterOIDCRoutes(api, oidcHandler)
+		}
+
+		// Armazenamento dos arquivos (disco local ou S3)
+		store, err := wire.InitializeStorage(cfg)
+		if err != nil {
+			log.Fatal("Erro ao inicializar armazenamento:", err)
+		}
 
 		// Registra rotas de livros
-		bookHandler := wire.InitializeBookHandler(db)
-		bookHttp.RegisterRoutes(api, bookHandler)
+		bookService := wire.InitializeBookService(db, cfg, store)
+		bookHandler := wire.InitializeBookHandler(bookService)
+		bookHttp.RegisterRoutes(api, bookHandler, authMiddleware)
+
+		// Registra rotas de upload resumível (tus)
+		uploadService := wire.InitializeUploadService(db, cfg, bookService)
+		uploadService.Start(context.Background())
+		uploadHandler := wire.InitializeUploadHandler(uploadService, cfg)
+		bookHttp.RegisterUploadRoutes(api, uploadHandler, authMiddleware)
+
+		// Verificação periódica de arquivos órfãos e ausentes (STORAGE_CHECK_INTERVAL)
+		wire.InitializeStorageCheckService(db, cfg, store).Start(context.Background())
+
+		// Registra rotas de exportação de dados da conta
+		exportService := wire.InitializeExportService(db, cfg, authService, bookService)
+		exportService.Start(context.Background())
+		exportHandler := wire.InitializeExportHandler(exportService)
+		exportHttp.RegisterRoutes(api, exportHandler, authMiddleware)
+
+		// Remoção dos dados de todos os módulos ao excluir uma conta
+		userDataRemover := wire.InitializeUserDataRemover(bookService, uploadService, exportService)
+
+		// Registra rotas da conta do usuário (/me)
+		accountHandler := wire.InitializeAccountHandler(authService, userDataRemover)
+		authHttp.RegisterAccountRoutes(api, accountHandler, authMiddleware)
+
+		// Registra rotas de administração
+		if err := authService.EnsureAdmins(context.Background()); err != nil {
+			log.Printf("Aviso: erro ao promover administradores: %v", err)
+		}
+		adminHandler := wire.InitializeAdminHandler(authService, bookService, userDataRemover)
+		adminHttp.RegisterRoutes(api, adminHandler, authMiddleware)
 	}
 
 	// Inicia o servidor
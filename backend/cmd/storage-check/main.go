@@ -0,0 +1,54 @@
+// Comando storage-check compara o armazenamento de arquivos com as tabelas de
+// livros e blobs e imprime o relatório em JSON: arquivos órfãos, livros sem
+// arquivo e contagens de referência divergentes. Com -delete, remove os
+// arquivos órfãos mais antigos que -grace.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"cloud-reader/backend/internal/shared/database"
+	"cloud-reader/backend/internal/wire"
+)
+
+func main() {
+	cfg := wire.InitializeConfig()
+
+	deleteOrphans := flag.Bool("delete", false, "remove os arquivos órfãos mais antigos que -grace")
+	flag.DurationVar(&cfg.StorageOrphanGrace, "grace", cfg.StorageOrphanGrace, "idade mínima de um arquivo órfão para ser removido")
+	flag.Parse()
+
+	db, err := wire.InitializeDatabase(cfg)
+	if err != nil {
+		log.Fatal("Erro ao conectar ao banco de dados:", err)
+	}
+	defer database.Close()
+
+	store, err := wire.InitializeStorage(cfg)
+	if err != nil {
+		log.Fatal("Erro ao inicializar armazenamento:", err)
+	}
+
+	checker := wire.InitializeStorageCheckService(db, cfg, store)
+	report, err := checker.Check(context.Background(), *deleteOrphans)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(report); encodeErr != nil {
+			log.Fatal("Erro ao gerar relatório:", encodeErr)
+		}
+	}
+	if err != nil {
+		log.Fatal("Erro ao verificar armazenamento:", err)
+	}
+
+	// Sai com código 1 se houver divergências, para uso em scripts e monitoramento
+	if len(report.MissingFiles) > 0 || len(report.RefCountMismatches) > 0 ||
+		len(report.OrphanFiles) > report.DeletedFiles {
+		os.Exit(1)
+	}
+}
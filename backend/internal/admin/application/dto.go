@@ -0,0 +1,39 @@
+package application
+
+import (
+	authApplication "cloud-reader/backend/internal/auth/application"
+	bookApplication "cloud-reader/backend/internal/books/application"
+)
+
+// UserResponse representa um usuário com o espaço ocupado pelos seus livros e a cota
+type UserResponse struct {
+	authApplication.AdminUserResponse
+	Books        int64 `json:"books"`
+	StorageBytes int64 `json:"storage_bytes"`
+	bookApplication.UserQuotaResponse
+}
+
+// ListUsersResponse representa a resposta da listagem de usuários
+type ListUsersResponse struct {
+	Users    []UserResponse `json:"users"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
+
+// StorageReportResponse representa o uso de armazenamento do servidor
+type StorageReportResponse struct {
+	Users      []UserStorageResponse `json:"users"`
+	TotalBooks int64                 `json:"total_books"`
+	TotalBytes int64                 `json:"total_bytes"`
+}
+
+// UserStorageResponse representa o espaço ocupado por um usuário no relatório
+type UserStorageResponse struct {
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Books  int64  `json:"books"`
+	Bytes  int64  `json:"bytes"`
+	bookApplication.UserQuotaResponse
+}
@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	authApplication "cloud-reader/backend/internal/auth/application"
+	authDomain "cloud-reader/backend/internal/auth/domain"
+	bookApplication "cloud-reader/backend/internal/books/application"
+)
+
+// AdminService define os casos de uso de administração do servidor.
+// Combina os módulos de autenticação e de livros.
+type AdminService struct {
+	authService *authApplication.AuthService
+	bookService *bookApplication.BookService
+	dataRemover authDomain.UserDataRemover
+}
+
+// NewAdminService cria uma nova instância do AdminService
+func NewAdminService(
+	authService *authApplication.AuthService,
+	bookService *bookApplication.BookService,
+	dataRemover authDomain.UserDataRemover,
+) *AdminService {
+	return &AdminService{
+		authService: authService,
+		bookService: bookService,
+		dataRemover: dataRemover,
+	}
+}
+
+// ListUsers lista os usuários com o espaço ocupado por cada um
+func (s *AdminService) ListUsers(ctx context.Context, req *authApplication.ListUsersRequest) (*ListUsersResponse, error) {
+	list, err := s.authService.ListUsers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, len(list.Users))
+	for i, user := range list.Users {
+		userIDs[i] = user.ID
+	}
+
+	usage, err := s.usageByUser(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := s.bookService.GetUserQuotas(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]UserResponse, len(list.Users))
+	for i, user := range list.Users {
+		users[i] = UserResponse{
+			AdminUserResponse: user,
+			Books:             usage[user.ID].Books,
+			StorageBytes:      usage[user.ID].Bytes,
+			UserQuotaResponse: quotas[user.ID],
+		}
+	}
+
+	return &ListUsersResponse{
+		Users:    users,
+		Total:    list.Total,
+		Page:     list.Page,
+		PageSize: list.PageSize,
+	}, nil
+}
+
+// GetUser retorna os dados de um usuário com o espaço ocupado
+func (s *AdminService) GetUser(ctx context.Context, id uint) (*UserResponse, error) {
+	user, err := s.authService.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.usageByUser(ctx, []uint{id})
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := s.bookService.GetUserQuotas(ctx, []uint{id})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserResponse{
+		AdminUserResponse: *user,
+		Books:             usage[id].Books,
+		StorageBytes:      usage[id].Bytes,
+		UserQuotaResponse: quotas[id],
+	}, nil
+}
+
+// SetUserDisabled desativa ou reativa a conta de um usuário
+func (s *AdminService) SetUserDisabled(ctx context.Context, actorID, id uint, disabled bool) error {
+	return s.authService.SetUserDisabled(ctx, actorID, id, disabled)
+}
+
+// UpdateUserRole altera o papel de um usuário
+func (s *AdminService) UpdateUserRole(ctx context.Context, actorID, id uint, req *authApplication.UpdateRoleRequest) error {
+	return s.authService.UpdateUserRole(ctx, actorID, id, req)
+}
+
+// ResetPassword redefine a senha de um usuário ou envia um link de redefinição
+func (s *AdminService) ResetPassword(ctx context.Context, id uint, req *authApplication.AdminResetPasswordRequest) error {
+	return s.authService.AdminResetPassword(ctx, id, req)
+}
+
+// SetUserQuota define a cota de armazenamento de um usuário (null volta para a padrão)
+func (s *AdminService) SetUserQuota(ctx context.Context, id uint, req *bookApplication.SetQuotaRequest) error {
+	if _, err := s.authService.GetUser(ctx, id); err != nil {
+		return err
+	}
+	return s.bookService.SetUserQuota(ctx, id, req)
+}
+
+// DeleteUser remove um usuário e os dados dos demais módulos (livros, arquivos,
+// uploads e exportações)
+func (s *AdminService) DeleteUser(ctx context.Context, actorID, id uint) error {
+	// Valida antes de remover os arquivos, que não podem ser recuperados
+	if actorID == id {
+		return errors.New("não é possível alterar a própria conta")
+	}
+	if _, err := s.authService.GetUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.dataRemover.DeleteUserData(ctx, id); err != nil {
+		return err
+	}
+
+	return s.authService.DeleteUser(ctx, actorID, id)
+}
+
+// StorageReport retorna o uso de armazenamento de todos os usuários
+func (s *AdminService) StorageReport(ctx context.Context) (*StorageReportResponse, error) {
+	usage, err := s.bookService.GetStorageUsage(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, len(usage))
+	for i, u := range usage {
+		userIDs[i] = u.UserID
+	}
+	quotas, err := s.bookService.GetUserQuotas(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StorageReportResponse{
+		Users: make([]UserStorageResponse, 0, len(usage)),
+	}
+	for _, u := range usage {
+		entry := UserStorageResponse{
+			UserID:            u.UserID,
+			Books:             u.Books,
+			Bytes:             u.Bytes,
+			UserQuotaResponse: quotas[u.UserID],
+		}
+		// Livros de usuários já removidos aparecem apenas com o ID
+		if user, err := s.authService.GetUser(ctx, u.UserID); err == nil {
+			entry.Name = user.Name
+			entry.Email = user.Email
+		}
+		report.Users = append(report.Users, entry)
+		report.TotalBooks += u.Books
+		report.TotalBytes += u.Bytes
+	}
+
+	return report, nil
+}
+
+// usageByUser retorna o espaço ocupado indexado pelo ID do usuário
+func (s *AdminService) usageByUser(ctx context.Context, userIDs []uint) (map[uint]bookApplication.StorageUsageResponse, error) {
+	result := make(map[uint]bookApplication.StorageUsageResponse, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	usage, err := s.bookService.GetStorageUsage(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range usage {
+		result[u.UserID] = u
+	}
+	return result, nil
+}
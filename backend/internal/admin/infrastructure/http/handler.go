@@ -0,0 +1,229 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	adminApplication "cloud-reader/backend/internal/admin/application"
+	authApplication "cloud-reader/backend/internal/auth/application"
+	bookApplication "cloud-reader/backend/internal/books/application"
+	"cloud-reader/backend/internal/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler gerencia os handlers HTTP de administração
+type AdminHandler struct {
+	adminService *adminApplication.AdminService
+}
+
+// NewAdminHandler cria uma nova instância do AdminHandler
+func NewAdminHandler(adminService *adminApplication.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ListUsers lista os usuários (filtros: q, page, page_size)
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var req authApplication.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "parâmetros inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.adminService.ListUsers(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetUser retorna os dados e o uso de armazenamento de um usuário
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.adminService.GetUser(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DisableUser desativa a conta de um usuário
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// EnableUser reativa a conta de um usuário
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *AdminHandler) setUserDisabled(c *gin.Context, disabled bool) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.adminService.SetUserDisabled(c.Request.Context(), actorID, id, disabled); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateUserRole altera o papel de um usuário
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.GetUserID(c)
+
+	var req authApplication.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.adminService.UpdateUserRole(c.Request.Context(), actorID, id, &req); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetUserQuota define a cota de armazenamento de um usuário
+func (h *AdminHandler) SetUserQuota(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req bookApplication.SetQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.adminService.SetUserQuota(c.Request.Context(), id, &req); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResetPassword redefine a senha de um usuário ou envia um link de redefinição
+func (h *AdminHandler) ResetPassword(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	// O corpo é opcional: sem senha, o link de redefinição é enviado por email
+	var req authApplication.AdminResetPasswordRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "dados inválidos",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.adminService.ResetPassword(c.Request.Context(), id, &req); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUser remove um usuário com seus livros e arquivos
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.GetUserID(c)
+
+	if err := h.adminService.DeleteUser(c.Request.Context(), actorID, id); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// StorageReport retorna o uso de armazenamento por usuário
+func (h *AdminHandler) StorageReport(c *gin.Context) {
+	resp, err := h.adminService.StorageReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseUserID lê o ID do usuário da URL, respondendo 400 se inválido
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// errorStatus mapeia os erros dos casos de uso de administração para o status HTTP
+func errorStatus(err error) int {
+	switch err.Error() {
+	case "usuário não encontrado":
+		return http.StatusNotFound
+	case "não é possível alterar a própria conta", "papel inválido":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registra as rotas de administração no router.
+// Exigem sessão (não aceitam chave de API) de um usuário com papel admin.
+func RegisterRoutes(router *gin.RouterGroup, handler *AdminHandler, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin", authMiddleware, middleware.RequireSession(), middleware.RequireRole(domain.RoleAdmin))
+	{
+		admin.GET("/users", handler.ListUsers)
+		admin.GET("/users/:id", handler.GetUser)
+		admin.POST("/users/:id/disable", handler.DisableUser)
+		admin.POST("/users/:id/enable", handler.EnableUser)
+		admin.PUT("/users/:id/role", handler.UpdateUserRole)
+		admin.PUT("/users/:id/quota", handler.SetUserQuota)
+		admin.POST("/users/:id/password-reset", handler.ResetPassword)
+		admin.DELETE("/users/:id", handler.DeleteUser)
+		admin.GET("/storage", handler.StorageReport)
+	}
+}
@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/pkg/password"
+)
+
+// AccountService define os casos de uso da conta do próprio usuário (/me).
+// A exclusão da conta remove também os dados de outros módulos via dataRemover.
+type AccountService struct {
+	auth        *AuthService
+	dataRemover domain.UserDataRemover
+}
+
+// NewAccountService cria uma nova instância do AccountService
+func NewAccountService(auth *AuthService, dataRemover domain.UserDataRemover) *AccountService {
+	return &AccountService{
+		auth:        auth,
+		dataRemover: dataRemover,
+	}
+}
+
+// GetProfile retorna os dados da conta
+func (s *AccountService) GetProfile(ctx context.Context, userID uint) (*ProfileResponse, error) {
+	return s.auth.GetProfile(ctx, userID)
+}
+
+// UpdateProfile atualiza nome e/ou email. Um novo email precisa ser verificado novamente.
+func (s *AccountService) UpdateProfile(ctx context.Context, userID uint, req *UpdateProfileRequest) (*ProfileResponse, error) {
+	user, err := s.auth.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+	}
+
+	emailChanged := false
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if !strings.EqualFold(email, user.Email) {
+			if !password.Verify(req.CurrentPassword, user.Password) {
+				return nil, errors.New("senha incorreta")
+			}
+
+			exists, err := s.auth.userRepo.ExistsByEmail(ctx, email)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, errors.New("email já está em uso")
+			}
+
+			user.EmailVerifiedAt = nil
+			emailChanged = true
+		}
+		user.Email = email
+	}
+
+	if err := s.auth.userRepo.UpdateProfile(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if emailChanged {
+		// Links de redefinição enviados ao email antigo deixam de valer
+		if err := s.auth.userTokenRepo.InvalidateByUserID(ctx, user.ID, domain.TokenPurposePasswordReset); err != nil {
+			log.Printf("Erro ao invalidar tokens do usuário %d: %v", user.ID, err)
+		}
+		s.auth.sendVerificationEmail(user)
+	}
+
+	return toProfileResponse(user), nil
+}
+
+// ChangePassword troca a senha e revoga as demais sessões do usuário
+func (s *AccountService) ChangePassword(ctx context.Context, userID uint, currentSessionID string, req *ChangePasswordRequest) error {
+	user, err := s.auth.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	if !password.Verify(req.CurrentPassword, user.Password) {
+		return errors.New("senha incorreta")
+	}
+
+	hashedPassword, err := password.Hash(req.NewPassword)
+	if err != nil {
+		return errors.New("erro ao processar senha")
+	}
+
+	if err := s.auth.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.auth.sessionRepo.RevokeAllByUserID(ctx, user.ID, currentSessionID, "password_changed"); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// DeleteAccount exclui a conta (soft delete), revoga as sessões e remove os
+// livros e arquivos do usuário
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uint, req *DeleteAccountRequest) error {
+	user, err := s.auth.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	if !password.Verify(req.Password, user.Password) {
+		return errors.New("senha incorreta")
+	}
+
+	if err := s.dataRemover.DeleteUserData(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.auth.userRepo.SoftDelete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.auth.sessionRepo.RevokeAllByUserID(ctx, user.ID, "", "account_deleted"); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// GetProfile retorna os dados da conta de um usuário
+func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*ProfileResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	return toProfileResponse(user), nil
+}
+
+// toProfileResponse converte o usuário para a resposta de perfil
+func toProfileResponse(user *domain.User) *ProfileResponse {
+	return &ProfileResponse{
+		UserResponse:     toUserResponse(user),
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
+		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
+	}
+}
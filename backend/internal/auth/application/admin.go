@@ -0,0 +1,191 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/pkg/password"
+)
+
+const (
+	// defaultUsersPageSize é o tamanho padrão da página na listagem de usuários
+	defaultUsersPageSize = 50
+
+	// maxUsersPageSize é o tamanho máximo da página na listagem de usuários
+	maxUsersPageSize = 200
+)
+
+// EnsureAdmins promove a administrador os usuários configurados em ADMIN_EMAILS
+// que já verificaram o email
+func (s *AuthService) EnsureAdmins(ctx context.Context) error {
+	for email := range s.adminEmails {
+		user, err := s.userRepo.FindByEmail(ctx, email)
+		if err != nil {
+			// O usuário será promovido ao verificar o email
+			continue
+		}
+		if err := s.promoteAdmin(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promoteAdmin promove a administrador um usuário listado em ADMIN_EMAILS.
+// Só a verificação do email comprova a posse do endereço: antes dela a conta
+// continua com o papel user.
+func (s *AuthService) promoteAdmin(ctx context.Context, user *domain.User) error {
+	if user.IsAdmin() || !user.IsEmailVerified() || !s.adminEmails[strings.ToLower(user.Email)] {
+		return nil
+	}
+	if err := s.userRepo.UpdateRole(ctx, user.ID, domain.RoleAdmin); err != nil {
+		return err
+	}
+	user.Role = domain.RoleAdmin
+	log.Printf("Usuário %s promovido a administrador", user.Email)
+	return nil
+}
+
+// ListUsers lista os usuários cadastrados
+func (s *AuthService) ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = defaultUsersPageSize
+	}
+	if pageSize > maxUsersPageSize {
+		pageSize = maxUsersPageSize
+	}
+
+	users, total, err := s.userRepo.List(ctx, domain.UserListFilter{
+		Query:  strings.TrimSpace(req.Query),
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]AdminUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = toAdminUserResponse(user)
+	}
+
+	return &ListUsersResponse{
+		Users:    responses,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetUser retorna os dados de um usuário
+func (s *AuthService) GetUser(ctx context.Context, id uint) (*AdminUserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	resp := toAdminUserResponse(user)
+	return &resp, nil
+}
+
+// SetUserDisabled desativa ou reativa a conta de um usuário.
+// Ao desativar, todas as sessões são revogadas.
+func (s *AuthService) SetUserDisabled(ctx context.Context, actorID, id uint, disabled bool) error {
+	if actorID == id {
+		return errors.New("não é possível alterar a própria conta")
+	}
+
+	var disabledAt *time.Time
+	if disabled {
+		now := time.Now()
+		disabledAt = &now
+	}
+
+	if err := s.userRepo.SetDisabled(ctx, id, disabledAt); err != nil {
+		return err
+	}
+
+	if disabled {
+		if err := s.sessionRepo.RevokeAllByUserID(ctx, id, "", "account_disabled"); err != nil {
+			log.Printf("Erro ao revogar sessões do usuário %d: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateUserRole altera o papel de um usuário
+func (s *AuthService) UpdateUserRole(ctx context.Context, actorID, id uint, req *UpdateRoleRequest) error {
+	if actorID == id {
+		return errors.New("não é possível alterar a própria conta")
+	}
+	if !domain.IsValidRole(req.Role) {
+		return errors.New("papel inválido")
+	}
+	return s.userRepo.UpdateRole(ctx, id, req.Role)
+}
+
+// AdminResetPassword redefine a senha de um usuário. Com senha informada ela é
+// aplicada diretamente e as sessões são revogadas; sem senha, um link de
+// redefinição é enviado ao usuário.
+func (s *AuthService) AdminResetPassword(ctx context.Context, id uint, req *AdminResetPasswordRequest) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	if req.Password == "" {
+		return s.sendPasswordResetEmail(ctx, user)
+	}
+
+	hashedPassword, err := password.Hash(req.Password)
+	if err != nil {
+		return errors.New("erro ao processar senha")
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllByUserID(ctx, user.ID, "", "password_reset"); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// DeleteUser remove definitivamente um usuário e seus dados de autenticação.
+// Os livros do usuário devem ser removidos pelo chamador.
+func (s *AuthService) DeleteUser(ctx context.Context, actorID, id uint) error {
+	if actorID == id {
+		return errors.New("não é possível alterar a própria conta")
+	}
+	return s.userRepo.Delete(ctx, id)
+}
+
+// toAdminUserResponse converte o usuário para a resposta da API de administração
+func toAdminUserResponse(user *domain.User) AdminUserResponse {
+	resp := AdminUserResponse{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		Role:             user.Role,
+		EmailVerified:    user.IsEmailVerified(),
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
+		Disabled:         user.IsDisabled(),
+		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
+	}
+	if user.DisabledAt != nil {
+		resp.DisabledAt = user.DisabledAt.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/config"
+)
+
+// withAdminEmails configura ADMIN_EMAILS no serviço de teste
+func withAdminEmails(emails ...string) func(cfg *config.Config) {
+	return func(cfg *config.Config) {
+		cfg.AdminEmails = emails
+	}
+}
+
+// roleOf retorna o papel atual do usuário no repositório
+func roleOf(t *testing.T, users *fakeUserRepo, id uint) string {
+	t.Helper()
+	user, err := users.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.Role
+}
+
+func TestAdminEmailPromotedAfterVerification(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, withAdminEmails("Admin@Example.com"))
+
+	registered, err := service.Register(ctx, &RegisterRequest{Name: "Admin", Email: "admin@example.com", Password: "senha-segura-123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Registrar o email listado não comprova sua posse
+	if role := roleOf(t, users, registered.ID); role != domain.RoleUser {
+		t.Fatalf("papel após o registro = %q, esperado user", role)
+	}
+	if err := service.EnsureAdmins(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if role := roleOf(t, users, registered.ID); role != domain.RoleUser {
+		t.Fatalf("papel sem email verificado após EnsureAdmins = %q, esperado user", role)
+	}
+
+	token, err := service.issueUserToken(ctx, registered.ID, domain.TokenPurposeEmailVerification, service.emailVerificationTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.VerifyEmail(ctx, &VerifyEmailRequest{Token: token}); err != nil {
+		t.Fatal(err)
+	}
+	if role := roleOf(t, users, registered.ID); role != domain.RoleAdmin {
+		t.Errorf("papel após verificar o email = %q, esperado admin", role)
+	}
+}
+
+func TestEnsureAdmins(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, withAdminEmails("verificado@example.com", "pendente@example.com", "ausente@example.com"))
+	verified := addUser(t, users, "verificado@example.com", "senha", true)
+	pending := addUser(t, users, "pendente@example.com", "senha", false)
+	other := addUser(t, users, "outro@example.com", "senha", true)
+
+	if err := service.EnsureAdmins(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		user *domain.User
+		want string
+	}{
+		{verified, domain.RoleAdmin},
+		{pending, domain.RoleUser},
+		{other, domain.RoleUser},
+	} {
+		if role := roleOf(t, users, tt.user.ID); role != tt.want {
+			t.Errorf("papel de %s = %q, esperado %q", tt.user.Email, role, tt.want)
+		}
+	}
+}
+
+func TestOIDCAdminPromotion(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, withAdminEmails("admin@example.com", "outro-admin@example.com"))
+	oidc := NewOIDCService(service, &fakeIdentityRepo{}, nil, nil, &config.Config{OIDCAutoProvision: true})
+
+	// Conta criada com email não verificado pelo provedor continua user
+	unverified, err := oidc.resolveUser(ctx, &domain.OIDCClaims{Issuer: "idp", Subject: "1", Email: "admin@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unverified.Role != domain.RoleUser || roleOf(t, users, unverified.ID) != domain.RoleUser {
+		t.Errorf("conta com email não verificado promovida a %q", unverified.Role)
+	}
+
+	// Email verificado pelo provedor promove a conta listada
+	verified, err := oidc.resolveUser(ctx, &domain.OIDCClaims{Issuer: "idp", Subject: "2", Email: "outro-admin@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Role != domain.RoleAdmin || roleOf(t, users, verified.ID) != domain.RoleAdmin {
+		t.Errorf("conta com email verificado com papel %q, esperado admin", verified.Role)
+	}
+}
+
+func TestAdminCannotChangeOwnAccount(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	admin := addUser(t, users, "admin@example.com", "senha", true)
+	users.UpdateRole(ctx, admin.ID, domain.RoleAdmin)
+	user := addUser(t, users, "ana@example.com", "senha", true)
+
+	if err := service.UpdateUserRole(ctx, admin.ID, admin.ID, &UpdateRoleRequest{Role: domain.RoleUser}); err == nil {
+		t.Error("administrador alterou o próprio papel")
+	}
+	if err := service.SetUserDisabled(ctx, admin.ID, admin.ID, true); err == nil {
+		t.Error("administrador desativou a própria conta")
+	}
+	if err := service.DeleteUser(ctx, admin.ID, admin.ID); err == nil {
+		t.Error("administrador removeu a própria conta")
+	}
+	if role := roleOf(t, users, admin.ID); role != domain.RoleAdmin {
+		t.Errorf("papel do administrador = %q", role)
+	}
+
+	if err := service.UpdateUserRole(ctx, admin.ID, user.ID, &UpdateRoleRequest{Role: "superuser"}); err == nil {
+		t.Error("papel inválido aceito")
+	}
+	if err := service.UpdateUserRole(ctx, admin.ID, user.ID, &UpdateRoleRequest{Role: domain.RoleAdmin}); err != nil {
+		t.Fatal(err)
+	}
+	if role := roleOf(t, users, user.ID); role != domain.RoleAdmin {
+		t.Errorf("papel após a promoção = %q, esperado admin", role)
+	}
+}
@@ -0,0 +1,150 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/pkg/securetoken"
+)
+
+const (
+	// apiKeyBytes é a entropia (em bytes) das chaves de API
+	apiKeyBytes = 32
+
+	// apiKeyDisplayPrefixLen é o tamanho do prefixo exibido na listagem
+	apiKeyDisplayPrefixLen = 8
+
+	// apiKeyTouchInterval é o intervalo mínimo entre atualizações de last_used_at
+	apiKeyTouchInterval = time.Minute
+)
+
+// CreateAPIKey cria uma nova chave de API para o usuário. O valor da chave só é
+// retornado nesta resposta.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID uint, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := securetoken.Generate(apiKeyBytes)
+	if err != nil {
+		return nil, errors.New("erro ao gerar chave de API")
+	}
+	plain := domain.APIKeyPrefix + secret
+
+	key := &domain.APIKey{
+		UserID:  userID,
+		Name:    strings.TrimSpace(req.Name),
+		Prefix:  plain[:len(domain.APIKeyPrefix)+apiKeyDisplayPrefixLen],
+		KeyHash: securetoken.Hash(plain),
+		Scopes:  strings.Join(scopes, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, errors.New("erro ao criar chave de API")
+	}
+
+	return &CreateAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            plain,
+	}, nil
+}
+
+// ListAPIKeys lista as chaves de API ativas do usuário
+func (s *AuthService) ListAPIKeys(ctx context.Context, userID uint) (*ListAPIKeysResponse, error) {
+	keys, err := s.apiKeyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyResponses := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		keyResponses[i] = toAPIKeyResponse(key)
+	}
+
+	return &ListAPIKeysResponse{
+		APIKeys: keyResponses,
+		Total:   len(keyResponses),
+	}, nil
+}
+
+// RevokeAPIKey revoga uma chave de API do usuário
+func (s *AuthService) RevokeAPIKey(ctx context.Context, userID uint, id uint) error {
+	return s.apiKeyRepo.Revoke(ctx, id, userID)
+}
+
+// authenticateAPIKey valida uma chave de API e registra seu uso
+func (s *AuthService) authenticateAPIKey(ctx context.Context, plain string) (*AuthenticatedUser, error) {
+	key, err := s.apiKeyRepo.FindByHash(ctx, securetoken.Hash(plain))
+	if err != nil {
+		return nil, errors.New("chave de API inválida")
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, errors.New("chave de API inválida")
+	}
+
+	user, err := s.activeUser(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now, apiKeyTouchInterval); err != nil {
+		log.Printf("Erro ao atualizar último uso da chave de API %d: %v", key.ID, err)
+	}
+
+	return &AuthenticatedUser{
+		UserID:   key.UserID,
+		APIKeyID: key.ID,
+		Role:     user.Role,
+		Scopes:   key.ScopeList(),
+	}, nil
+}
+
+// normalizeScopes valida, remove duplicados e ordena os escopos
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !domain.IsValidScope(scope) {
+			return nil, errors.New("escopo inválido: " + scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// toAPIKeyResponse converte a chave de API do domínio para a resposta
+func toAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.ScopeList(),
+		CreatedAt: key.CreatedAt.Format(time.RFC3339),
+	}
+	if key.LastUsedAt != nil {
+		lastUsedAt := key.LastUsedAt.Format(time.RFC3339)
+		resp.LastUsedAt = &lastUsedAt
+	}
+	if key.ExpiresAt != nil {
+		expiresAt := key.ExpiresAt.Format(time.RFC3339)
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+)
+
+func TestNormalizeScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "ordenados", scopes: []string{"progress:write", "books:read"}, want: []string{"books:read", "progress:write"}},
+		{name: "duplicados e espaços", scopes: []string{" books:read", "books:read ", "books:write"}, want: []string{"books:read", "books:write"}},
+		{name: "escopo desconhecido", scopes: []string{"books:read", "admin"}, wantErr: true},
+		{name: "escopo vazio", scopes: []string{""}, wantErr: true},
+		{name: "maiúsculas", scopes: []string{"BOOKS:READ"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeScopes(tt.scopes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeScopes(%q) erro = %v, esperado erro: %v", tt.scopes, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeScopes(%q) = %q, esperado %q", tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	user := addUser(t, users, "ana@example.com", "senha-da-ana", true)
+
+	created, err := service.CreateAPIKey(ctx, user.ID, &CreateAPIKeyRequest{
+		Name:   " leitor ",
+		Scopes: []string{"books:read", "progress:write", "books:read"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(created.Key, domain.APIKeyPrefix) || !strings.HasPrefix(created.Key, created.Prefix) {
+		t.Errorf("chave %q com prefixo %q", created.Key, created.Prefix)
+	}
+	if created.Name != "leitor" {
+		t.Errorf("nome = %q", created.Name)
+	}
+
+	// A chave autentica com os escopos restritos, nunca com acesso total
+	authenticated, err := service.Authenticate(ctx, created.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authenticated.UserID != user.ID || authenticated.APIKeyID != created.ID || authenticated.SessionID != "" {
+		t.Errorf("identidade = %+v", authenticated)
+	}
+	if want := []string{"books:read", "progress:write"}; !reflect.DeepEqual(authenticated.Scopes, want) {
+		t.Errorf("escopos = %q, esperado %q", authenticated.Scopes, want)
+	}
+
+	if _, err := service.CreateAPIKey(ctx, user.ID, &CreateAPIKeyRequest{Name: "x", Scopes: []string{"books:delete"}}); err == nil {
+		t.Error("chave criada com escopo desconhecido")
+	}
+}
+
+func TestAuthenticateAPIKeyRejected(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	user := addUser(t, users, "ana@example.com", "senha-da-ana", true)
+	apiKeys := service.apiKeyRepo.(*fakeAPIKeyRepo)
+
+	create := func() *CreateAPIKeyResponse {
+		t.Helper()
+		created, err := service.CreateAPIKey(ctx, user.ID, &CreateAPIKeyRequest{Name: "k", Scopes: []string{"books:read"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+
+	revoked := create()
+	if err := service.RevokeAPIKey(ctx, user.ID, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(ctx, revoked.Key); err == nil {
+		t.Error("chave revogada aceita")
+	}
+
+	expired := create()
+	past := time.Now().Add(-time.Minute)
+	apiKeys.keys[expired.ID-1].ExpiresAt = &past
+	if _, err := service.Authenticate(ctx, expired.Key); err == nil {
+		t.Error("chave expirada aceita")
+	}
+
+	if _, err := service.Authenticate(ctx, domain.APIKeyPrefix+"desconhecida"); err == nil {
+		t.Error("chave desconhecida aceita")
+	}
+
+	active := create()
+	users.users[user.ID].DisabledAt = &past
+	if _, err := service.Authenticate(ctx, active.Key); err == nil {
+		t.Error("chave de conta desativada aceita")
+	}
+}
@@ -14,22 +14,243 @@ type RegisterResponse struct {
 	Email string `json:"email"`
 }
 
+// ClientInfo identifica o cliente (dispositivo) que abriu a sessão
+type ClientInfo struct {
+	DeviceName string `json:"device_name,omitempty" binding:"max=100"`
+	UserAgent  string `json:"-"`
+	IPAddress  string `json:"-"`
+}
+
 // LoginRequest representa a requisição de login
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	ClientInfo
 }
 
-// LoginResponse representa a resposta de login
+// LoginResponse representa a resposta de login e de renovação de tokens.
+// Quando a autenticação em dois fatores está ativa, o login retorna apenas o desafio
+// (TwoFactorRequired e ChallengeToken), que deve ser concluído em /auth/login/2fa.
 type LoginResponse struct {
-	User  UserResponse `json:"user"`
-	Token string       `json:"token,omitempty"` // Para uso futuro com JWT
+	User             *UserResponse `json:"user,omitempty"`
+	Token            string        `json:"token,omitempty"`
+	TokenType        string        `json:"token_type,omitempty"`
+	ExpiresAt        string        `json:"expires_at,omitempty"`
+	RefreshToken     string        `json:"refresh_token,omitempty"`
+	RefreshExpiresAt string        `json:"refresh_expires_at,omitempty"`
+	SessionID        string        `json:"session_id,omitempty"`
+
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt string `json:"challenge_expires_at,omitempty"`
+}
+
+// TwoFactorLoginRequest representa o segundo passo do login com 2FA.
+// Deve ser informado o código TOTP ou um código de recuperação.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode   string `json:"recovery_code"`
+	ClientInfo
+}
+
+// OIDCExchangeRequest troca o código recebido no callback OIDC por uma sessão
+type OIDCExchangeRequest struct {
+	Code string `json:"code" binding:"required"`
+	ClientInfo
+}
+
+// OIDCConfigResponse informa ao frontend o provedor OIDC configurado
+type OIDCConfigResponse struct {
+	Enabled      bool   `json:"enabled"`
+	ProviderName string `json:"provider_name"`
+}
+
+// TwoFactorEnrollResponse representa a resposta do início da configuração do 2FA
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorCodeRequest representa uma requisição confirmada por um código TOTP
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorDisableRequest representa a requisição de desativação do 2FA
+type TwoFactorDisableRequest struct {
+	Password     string `json:"password" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// RecoveryCodesResponse representa os códigos de recuperação gerados (exibidos uma única vez)
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorStatusResponse representa o estado do 2FA do usuário
+type TwoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
+
+// RefreshRequest representa a requisição de renovação de tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	ClientInfo
+}
+
+// LogoutRequest representa a requisição de logout
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// SessionResponse representa uma sessão ativa na resposta
+type SessionResponse struct {
+	ID         string `json:"id"`
+	DeviceName string `json:"device_name"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+	Current    bool   `json:"current"`
+}
+
+// ListSessionsResponse representa a resposta com a lista de sessões
+type ListSessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+	Total    int               `json:"total"`
 }
 
 // UserResponse representa os dados do usuário na resposta
 type UserResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role"`
+}
+
+// ForgotPasswordRequest representa a requisição de recuperação de senha
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest representa a requisição de redefinição de senha
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// VerifyEmailRequest representa a requisição de verificação de email
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+
+// AuthenticatedUser representa a identidade autenticada de uma requisição
+type AuthenticatedUser struct {
+	UserID    uint
+	SessionID string   // Preenchido quando autenticado por token de acesso
+	APIKeyID  uint     // Preenchido quando autenticado por chave de API
+	Role      string   // Papel do usuário (user ou admin)
+	Scopes    []string // nil = acesso total (sessão); chaves de API têm escopos restritos
+}
+
+// CreateAPIKeyRequest representa a requisição de criação de chave de API
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=books:read books:write progress:write"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`
+}
+
+// APIKeyResponse representa uma chave de API na resposta (sem o valor da chave)
+type APIKeyResponse struct {
+	ID         uint     `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  string   `json:"created_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	ExpiresAt  *string  `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse representa a resposta de criação de chave de API.
+// O valor da chave só é retornado nesta resposta.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ListAPIKeysResponse representa a resposta com a lista de chaves de API
+type ListAPIKeysResponse struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
+	Total   int              `json:"total"`
+}
+
+// ListUsersRequest representa os filtros da listagem de usuários (administração)
+type ListUsersRequest struct {
+	Query    string `form:"q"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
 }
 
+// AdminUserResponse representa um usuário na API de administração
+type AdminUserResponse struct {
+	ID               uint   `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	Role             string `json:"role"`
+	EmailVerified    bool   `json:"email_verified"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	Disabled         bool   `json:"disabled"`
+	DisabledAt       string `json:"disabled_at,omitempty"`
+	CreatedAt        string `json:"created_at"`
+}
+
+// ListUsersResponse representa a resposta da listagem de usuários
+type ListUsersResponse struct {
+	Users    []AdminUserResponse `json:"users"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// UpdateRoleRequest representa a alteração do papel de um usuário
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// AdminResetPasswordRequest representa a redefinição de senha por um administrador.
+// Sem senha, um link de redefinição é enviado ao usuário.
+type AdminResetPasswordRequest struct {
+	Password string `json:"password" binding:"omitempty,min=6"`
+}
+
+// ProfileResponse representa os dados da conta do usuário autenticado
+type ProfileResponse struct {
+	UserResponse
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	CreatedAt        string `json:"created_at"`
+}
+
+// UpdateProfileRequest representa a atualização do perfil. Campos omitidos não
+// são alterados; a troca de email exige a senha atual.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+// ChangePasswordRequest representa a troca de senha pelo próprio usuário
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// DeleteAccountRequest representa a confirmação da exclusão da conta
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,451 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/auth/infrastructure/attempts"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/internal/shared/mailer"
+	"cloud-reader/backend/pkg/password"
+)
+
+var errNotFound = errors.New("registro não encontrado")
+
+// newTestAuthService cria um AuthService sobre repositórios em memória
+func newTestAuthService(t *testing.T, configure func(cfg *config.Config)) (*AuthService, *fakeUserRepo) {
+	t.Helper()
+	cfg := &config.Config{
+		LoginBaseDelay:        time.Minute,
+		LoginMaxDelay:         time.Hour,
+		LoginMaxFailures:      3,
+		LoginIPMaxFailures:    10,
+		LoginLockoutDuration:  time.Hour,
+		LoginFailureWindow:    time.Hour,
+		RefreshTokenTTL:       time.Hour,
+		PasswordResetTTL:      time.Hour,
+		EmailVerificationTTL:  time.Hour,
+		TwoFactorChallengeTTL: time.Minute,
+		TwoFactorIssuer:       "Cloud Reader",
+		AppBaseURL:            "http://localhost:3000",
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	users := &fakeUserRepo{users: make(map[uint]*domain.User)}
+	service := NewAuthService(
+		users,
+		&fakeSessionRepo{sessions: make(map[string]*domain.Session)},
+		&fakeUserTokenRepo{},
+		&fakeRecoveryCodeRepo{},
+		&fakeAPIKeyRepo{},
+		fakeTokenService{},
+		discardMailer{},
+		attempts.NewMemoryTracker(cfg.LoginFailureWindow),
+		cfg,
+	)
+	return service, users
+}
+
+// addUser cadastra um usuário com a senha informada
+func addUser(t *testing.T, users *fakeUserRepo, email, plain string, verified bool) *domain.User {
+	t.Helper()
+	hashed, err := password.Hash(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{Name: "Teste", Email: email, Password: hashed, Role: domain.RoleUser}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+type fakeUserRepo struct {
+	mu     sync.Mutex
+	users  map[uint]*domain.User
+	nextID uint
+}
+
+// get retorna uma cópia do usuário, como faria o banco
+func (r *fakeUserRepo) get(id uint) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.users {
+		if strings.EqualFold(existing.Email, user.Email) {
+			return errors.New("email duplicado")
+		}
+	}
+	r.nextID++
+	user.ID = r.nextID
+	user.CreatedAt = time.Now()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return r.get(id)
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id uint) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.get(id)
+}
+
+func (r *fakeUserRepo) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	_, err := r.FindByEmail(ctx, email)
+	return err == nil, nil
+}
+
+// update aplica a alteração ao usuário armazenado
+func (r *fakeUserRepo) update(id uint, change func(user *domain.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return errNotFound
+	}
+	change(user)
+	return nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	return r.update(id, func(user *domain.User) { user.Password = hashedPassword })
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(ctx context.Context, id uint, verifiedAt time.Time) error {
+	return r.update(id, func(user *domain.User) { user.EmailVerifiedAt = &verifiedAt })
+}
+
+func (r *fakeUserRepo) UpdateTwoFactor(ctx context.Context, id uint, secret string, enabledAt *time.Time) error {
+	return r.update(id, func(user *domain.User) {
+		user.TwoFactorSecret = secret
+		user.TwoFactorEnabledAt = enabledAt
+	})
+}
+
+func (r *fakeUserRepo) AdvanceTwoFactorStep(ctx context.Context, id uint, step int64) (bool, error) {
+	advanced := false
+	err := r.update(id, func(user *domain.User) {
+		if step > user.TwoFactorLastStep {
+			user.TwoFactorLastStep = step
+			advanced = true
+		}
+	})
+	return advanced, err
+}
+
+func (r *fakeUserRepo) List(ctx context.Context, filter domain.UserListFilter) ([]*domain.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []*domain.User
+	for id := uint(1); id <= r.nextID; id++ {
+		if user, err := r.get(id); err == nil {
+			users = append(users, user)
+		}
+	}
+	return users, int64(len(users)), nil
+}
+
+func (r *fakeUserRepo) UpdateRole(ctx context.Context, id uint, role string) error {
+	return r.update(id, func(user *domain.User) { user.Role = role })
+}
+
+func (r *fakeUserRepo) SetDisabled(ctx context.Context, id uint, disabledAt *time.Time) error {
+	return r.update(id, func(user *domain.User) { user.DisabledAt = disabledAt })
+}
+
+func (r *fakeUserRepo) UpdateProfile(ctx context.Context, user *domain.User) error {
+	return r.update(user.ID, func(stored *domain.User) {
+		stored.Name = user.Name
+		stored.Email = user.Email
+		stored.EmailVerifiedAt = user.EmailVerifiedAt
+	})
+}
+
+func (r *fakeUserRepo) SoftDelete(ctx context.Context, id uint) error {
+	return r.Delete(ctx, id)
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*domain.Session
+	tokens   []*domain.RefreshToken
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *domain.Session, token *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *session
+	r.sessions[session.ID] = &copied
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeSessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) FindActiveByUserID(ctx context.Context, userID uint) ([]*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []*domain.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive(time.Now()) {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeSessionRepo) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeSessionRepo) Rotate(ctx context.Context, currentTokenID uint, next *domain.RefreshToken, lastUsedAt time.Time) (bool, error) {
+	return false, errors.New("não implementado")
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, id string, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[id]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+		session.RevokeReason = reason
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllByUserID(ctx context.Context, userID uint, exceptID string, reason string) error {
+	r.mu.Lock()
+	var ids []string
+	for id, session := range r.sessions {
+		if session.UserID == userID && id != exceptID {
+			ids = append(ids, id)
+		}
+	}
+	r.mu.Unlock()
+	for _, id := range ids {
+		r.Revoke(ctx, id, reason)
+	}
+	return nil
+}
+
+type fakeUserTokenRepo struct {
+	mu     sync.Mutex
+	tokens []*domain.UserToken
+}
+
+func (r *fakeUserTokenRepo) Create(ctx context.Context, token *domain.UserToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token.ID = uint(len(r.tokens) + 1)
+	copied := *token
+	r.tokens = append(r.tokens, &copied)
+	return nil
+}
+
+func (r *fakeUserTokenRepo) FindByHash(ctx context.Context, purpose string, tokenHash string) (*domain.UserToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeUserTokenRepo) MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeUserTokenRepo) InvalidateByUserID(ctx context.Context, userID uint, purpose string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeRecoveryCodeRepo struct{}
+
+func (fakeRecoveryCodeRepo) ReplaceForUser(ctx context.Context, userID uint, codes []*domain.RecoveryCode) error {
+	return nil
+}
+
+func (fakeRecoveryCodeRepo) Consume(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (fakeRecoveryCodeRepo) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	return 0, nil
+}
+
+func (fakeRecoveryCodeRepo) DeleteByUserID(ctx context.Context, userID uint) error {
+	return nil
+}
+
+type fakeAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys []*domain.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key.ID = uint(len(r.keys) + 1)
+	key.CreatedAt = time.Now()
+	copied := *key
+	r.keys = append(r.keys, &copied)
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) FindByUserID(ctx context.Context, userID uint) ([]*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []*domain.APIKey
+	for _, key := range r.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			copied := *key
+			keys = append(keys, &copied)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, id uint, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range r.keys {
+		if key.ID == id && key.UserID == userID {
+			now := time.Now()
+			key.RevokedAt = &now
+			return nil
+		}
+	}
+	return errNotFound
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(ctx context.Context, id uint, now time.Time, minInterval time.Duration) error {
+	return nil
+}
+
+// fakeTokenService emite tokens de acesso legíveis ("user:sessão")
+type fakeTokenService struct{}
+
+func (fakeTokenService) GenerateAccessToken(user *domain.User, sessionID string) (string, time.Time, error) {
+	return user.Email + ":" + sessionID, time.Now().Add(time.Minute), nil
+}
+
+func (fakeTokenService) ValidateAccessToken(token string) (*domain.AccessTokenClaims, error) {
+	return nil, errors.New("token inválido")
+}
+
+// discardMailer descarta os emails
+type discardMailer struct{}
+
+func (discardMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	return nil
+}
+
+type fakeIdentityRepo struct {
+	mu         sync.Mutex
+	identities []domain.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			copied := identity
+			return &copied, nil
+		}
+	}
+	return nil, errNotFound
+}
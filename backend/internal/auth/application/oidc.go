@@ -0,0 +1,263 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/pkg/password"
+	"cloud-reader/backend/pkg/securetoken"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcStateTTL é o tempo máximo entre o início do login e o retorno do provedor
+	oidcStateTTL = 10 * time.Minute
+
+	// oidcExchangeTTL é a validade do código entregue ao frontend após o callback
+	oidcExchangeTTL = time.Minute
+)
+
+// OIDCService define os casos de uso do login via OpenID Connect.
+// A sessão final é criada pelo AuthService, então 2FA e sessões funcionam
+// da mesma forma que no login com email e senha.
+type OIDCService struct {
+	auth         *AuthService
+	identityRepo domain.UserIdentityRepository
+	stateRepo    domain.OIDCStateRepository
+	provider     domain.OIDCProvider
+
+	autoProvision        bool
+	requireVerifiedEmail bool
+	providerName         string
+}
+
+// NewOIDCService cria uma nova instância do OIDCService
+func NewOIDCService(
+	auth *AuthService,
+	identityRepo domain.UserIdentityRepository,
+	stateRepo domain.OIDCStateRepository,
+	provider domain.OIDCProvider,
+	cfg *config.Config,
+) *OIDCService {
+	return &OIDCService{
+		auth:                 auth,
+		identityRepo:         identityRepo,
+		stateRepo:            stateRepo,
+		provider:             provider,
+		autoProvision:        cfg.OIDCAutoProvision,
+		requireVerifiedEmail: cfg.OIDCRequireVerifiedEmail,
+		providerName:         cfg.OIDCProviderName,
+	}
+}
+
+// GetConfig retorna as informações públicas do provedor para o frontend
+func (s *OIDCService) GetConfig() *OIDCConfigResponse {
+	return &OIDCConfigResponse{
+		Enabled:      true,
+		ProviderName: s.providerName,
+	}
+}
+
+// FailureURL retorna a página de login do frontend com o motivo da falha
+func (s *OIDCService) FailureURL(reason string) string {
+	return s.auth.appBaseURL + "/login?" + url.Values{"oidc_error": {reason}}.Encode()
+}
+
+// StartLogin inicia o fluxo authorization code + PKCE e retorna a URL do provedor
+func (s *OIDCService) StartLogin(ctx context.Context, redirectPath string) (string, error) {
+	state, err := securetoken.Generate(userTokenBytes)
+	if err != nil {
+		return "", errors.New("erro ao iniciar login OIDC")
+	}
+	nonce, err := securetoken.Generate(userTokenBytes)
+	if err != nil {
+		return "", errors.New("erro ao iniciar login OIDC")
+	}
+	codeVerifier := oauth2.GenerateVerifier()
+
+	now := time.Now()
+	if err := s.stateRepo.Create(ctx, &domain.OIDCLoginState{
+		StateHash:    securetoken.Hash(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		RedirectPath: sanitizeRedirectPath(redirectPath),
+		ExpiresAt:    now.Add(oidcStateTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	// Aproveita para limpar logins abandonados
+	if err := s.stateRepo.DeleteExpired(ctx, now); err != nil {
+		log.Printf("erro ao remover states OIDC expirados: %v", err)
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		log.Printf("erro ao montar URL de autorização OIDC: %v", err)
+		return "", errors.New("provedor OIDC indisponível")
+	}
+
+	return authURL, nil
+}
+
+// Callback trata o retorno do provedor: valida o state, troca o código, vincula
+// ou cria o usuário e retorna a URL do frontend com um código de uso único
+func (s *OIDCService) Callback(ctx context.Context, code, state string) (string, error) {
+	if code == "" || state == "" {
+		return "", errors.New("login OIDC inválido ou expirado")
+	}
+
+	loginState, err := s.stateRepo.Consume(ctx, securetoken.Hash(state))
+	if err != nil || time.Now().After(loginState.ExpiresAt) {
+		return "", errors.New("login OIDC inválido ou expirado")
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("erro no callback OIDC: %v", err)
+		return "", errors.New("falha na autenticação com o provedor")
+	}
+
+	user, err := s.resolveUser(ctx, claims)
+	if err != nil {
+		return "", err
+	}
+
+	exchangeCode, err := s.auth.issueUserToken(ctx, user.ID, domain.TokenPurposeOIDCExchange, oidcExchangeTTL)
+	if err != nil {
+		return "", errors.New("erro ao concluir login OIDC")
+	}
+
+	query := url.Values{}
+	query.Set("code", exchangeCode)
+	if loginState.RedirectPath != "" {
+		query.Set("redirect", loginState.RedirectPath)
+	}
+	return s.auth.appBaseURL + "/auth/oidc/callback?" + query.Encode(), nil
+}
+
+// Exchange troca o código de uso único entregue ao frontend por uma sessão
+func (s *OIDCService) Exchange(ctx context.Context, req *OIDCExchangeRequest) (*LoginResponse, error) {
+	token, err := s.auth.consumeUserToken(ctx, domain.TokenPurposeOIDCExchange, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, errors.New("token inválido ou expirado")
+	}
+
+	// O provedor não substitui o segundo fator configurado na conta
+	if user.IsTwoFactorEnabled() {
+		return s.auth.startTwoFactorChallenge(ctx, user)
+	}
+
+	return s.auth.startSession(ctx, user, req.ClientInfo)
+}
+
+// resolveUser encontra o usuário vinculado à identidade, vincula uma conta
+// existente pelo email verificado ou cria uma nova conta
+func (s *OIDCService) resolveUser(ctx context.Context, claims *domain.OIDCClaims) (*domain.User, error) {
+	if identity, err := s.identityRepo.FindByIssuerSubject(ctx, claims.Issuer, claims.Subject); err == nil {
+		return s.auth.userRepo.FindByID(ctx, identity.UserID)
+	}
+
+	if claims.Email == "" {
+		return nil, errors.New("o provedor não informou o email")
+	}
+	if s.requireVerifiedEmail && !claims.EmailVerified {
+		return nil, errors.New("email não verificado pelo provedor")
+	}
+
+	user, err := s.auth.userRepo.FindByEmail(ctx, claims.Email)
+	if err != nil {
+		if !s.autoProvision {
+			return nil, errors.New("nenhuma conta vinculada a este email")
+		}
+		if user, err = s.provisionUser(ctx, claims); err != nil {
+			return nil, err
+		}
+	} else if !claims.EmailVerified {
+		// Vincular uma conta existente sempre exige email verificado
+		return nil, errors.New("email não verificado pelo provedor")
+	} else if !user.IsEmailVerified() {
+		// O provedor comprovou a posse do email
+		now := time.Now()
+		if err := s.auth.userRepo.MarkEmailVerified(ctx, user.ID, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+		if err := s.auth.promoteAdmin(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.identityRepo.Create(ctx, &domain.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// provisionUser cria uma conta para a identidade externa. A senha é aleatória e
+// descartada; o usuário pode definir uma pelo fluxo de redefinição de senha.
+func (s *OIDCService) provisionUser(ctx context.Context, claims *domain.OIDCClaims) (*domain.User, error) {
+	randomPassword, err := securetoken.Generate(userTokenBytes)
+	if err != nil {
+		return nil, errors.New("erro ao criar conta")
+	}
+	hashedPassword, err := password.Hash(randomPassword)
+	if err != nil {
+		return nil, errors.New("erro ao processar senha")
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	var verifiedAt *time.Time
+	if claims.EmailVerified {
+		now := time.Now()
+		verifiedAt = &now
+	}
+
+	user := &domain.User{
+		Name:            name,
+		Email:           claims.Email,
+		Password:        hashedPassword,
+		Role:            domain.RoleUser,
+		EmailVerifiedAt: verifiedAt,
+	}
+	if err := s.auth.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Sem email verificado pelo provedor, a promoção fica para a verificação
+	if err := s.auth.promoteAdmin(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// sanitizeRedirectPath aceita apenas caminhos relativos do próprio frontend
+func sanitizeRedirectPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.Contains(path, "\\") {
+		return ""
+	}
+	return path
+}
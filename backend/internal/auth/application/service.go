@@ -3,20 +3,83 @@ package application
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/internal/shared/mailer"
 	"cloud-reader/backend/pkg/password"
 )
 
 // AuthService define os casos de uso de autenticação
 type AuthService struct {
-	userRepo domain.UserRepository
+	userRepo      domain.UserRepository
+	sessionRepo      domain.SessionRepository
+	userTokenRepo    domain.UserTokenRepository
+	recoveryCodeRepo domain.RecoveryCodeRepository
+	apiKeyRepo       domain.APIKeyRepository
+	tokenService     domain.TokenService
+	mailer           mailer.Mailer
+	loginTracker     domain.LoginAttemptTracker
+
+	accountThrottle domain.LoginThrottlePolicy
+	ipThrottle      domain.LoginThrottlePolicy
+
+	refreshTokenTTL       time.Duration
+	passwordResetTTL      time.Duration
+	emailVerificationTTL  time.Duration
+	twoFactorChallengeTTL time.Duration
+	twoFactorIssuer       string
+	appBaseURL            string
+	adminEmails           map[string]bool
 }
 
 // NewAuthService cria uma nova instância do AuthService
-func NewAuthService(userRepo domain.UserRepository) *AuthService {
+func NewAuthService(
+	userRepo domain.UserRepository,
+	sessionRepo domain.SessionRepository,
+	userTokenRepo domain.UserTokenRepository,
+	recoveryCodeRepo domain.RecoveryCodeRepository,
+	apiKeyRepo domain.APIKeyRepository,
+	tokenService domain.TokenService,
+	mailer mailer.Mailer,
+	loginTracker domain.LoginAttemptTracker,
+	cfg *config.Config,
+) *AuthService {
+	accountThrottle := domain.LoginThrottlePolicy{
+		BaseDelay:       cfg.LoginBaseDelay,
+		MaxDelay:        cfg.LoginMaxDelay,
+		MaxFailures:     cfg.LoginMaxFailures,
+		LockoutDuration: cfg.LoginLockoutDuration,
+		Window:          cfg.LoginFailureWindow,
+	}
+	ipThrottle := accountThrottle
+	ipThrottle.MaxFailures = cfg.LoginIPMaxFailures
+
+	adminEmails := make(map[string]bool, len(cfg.AdminEmails))
+	for _, email := range cfg.AdminEmails {
+		adminEmails[strings.ToLower(email)] = true
+	}
+
 	return &AuthService{
-		userRepo: userRepo,
+		userRepo:              userRepo,
+		sessionRepo:           sessionRepo,
+		userTokenRepo:         userTokenRepo,
+		recoveryCodeRepo:      recoveryCodeRepo,
+		apiKeyRepo:            apiKeyRepo,
+		tokenService:          tokenService,
+		mailer:                mailer,
+		loginTracker:          loginTracker,
+		accountThrottle:       accountThrottle,
+		ipThrottle:            ipThrottle,
+		refreshTokenTTL:       cfg.RefreshTokenTTL,
+		passwordResetTTL:      cfg.PasswordResetTTL,
+		emailVerificationTTL:  cfg.EmailVerificationTTL,
+		twoFactorChallengeTTL: cfg.TwoFactorChallengeTTL,
+		twoFactorIssuer:       cfg.TwoFactorIssuer,
+		appBaseURL:            strings.TrimRight(cfg.AppBaseURL, "/"),
+		adminEmails:           adminEmails,
 	}
 }
 
@@ -42,12 +105,16 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Regi
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: hashedPassword,
+		Role:     domain.RoleUser,
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
+	// Envia o link de verificação de email
+	s.sendVerificationEmail(user)
+
 	return &RegisterResponse{
 		ID:    user.ID,
 		Name:  user.Name,
@@ -57,27 +124,93 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Regi
 
 // Login autentica um usuário
 func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	// Aplica o backoff por conta e por IP antes de qualquer verificação de senha
+	keys := s.loginThrottleKeys(req.Email, req.IPAddress)
+	reservations, err := s.reserveLoginAttempt(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
 	// Busca o usuário pelo email
 	user, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
+		// Comparação fictícia para que o tempo de resposta não revele se o email existe
+		password.VerifyDummy(req.Password)
+		s.loginAttemptFailed(keys, reservations)
 		return nil, errors.New("credenciais inválidas")
 	}
 
 	// Verifica a senha
 	if !password.Verify(req.Password, user.Password) {
+		s.loginAttemptFailed(keys, reservations)
 		return nil, errors.New("credenciais inválidas")
 	}
 
-	// TODO: Gerar token JWT no futuro
-	token := ""
+	// Senha correta: zera o histórico da conta e desfaz a reserva no IP (as
+	// falhas anteriores do IP expiram sozinhas)
+	s.resetLoginAttempts(ctx, keys[0])
+	s.releaseLoginAttempt(ctx, reservations[1:]...)
+
+	// Com 2FA ativo, a sessão só é criada após o segundo passo
+	if user.IsTwoFactorEnabled() {
+		return s.startTwoFactorChallenge(ctx, user)
+	}
+
+	// Abre uma nova sessão para o dispositivo
+	return s.startSession(ctx, user, req.ClientInfo)
+}
+
+// Authenticate valida a credencial Bearer (token de acesso ou chave de API) e
+// retorna a identidade autenticada. Tokens vinculados a uma sessão revogada ou
+// expirada são rejeitados.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*AuthenticatedUser, error) {
+	if strings.HasPrefix(token, domain.APIKeyPrefix) {
+		return s.authenticateAPIKey(ctx, token)
+	}
+
+	claims, err := s.tokenService.ValidateAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
 
-	return &LoginResponse{
-		User: UserResponse{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-		},
-		Token: token,
+	if claims.SessionID != "" {
+		session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
+		if err != nil || session.UserID != claims.UserID || !session.IsActive(time.Now()) {
+			return nil, errors.New("sessão inválida")
+		}
+	}
+
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthenticatedUser{
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Role:      user.Role,
 	}, nil
 }
 
+// activeUser carrega o usuário da credencial, rejeitando contas removidas ou desativadas
+func (s *AuthService) activeUser(ctx context.Context, userID uint) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	if user.IsDisabled() {
+		return nil, errors.New("conta desativada")
+	}
+	return user, nil
+}
+
+// toUserResponse converte o usuário do domínio para a resposta
+func toUserResponse(user *domain.User) UserResponse {
+	return UserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		Role:          user.Role,
+	}
+}
@@ -0,0 +1,189 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/pkg/securetoken"
+
+	"github.com/google/uuid"
+)
+
+// refreshTokenBytes é a entropia (em bytes) dos refresh tokens
+const refreshTokenBytes = 32
+
+// Refresh troca um refresh token válido por um novo par de tokens (rotação).
+// O reuso de um token já rotacionado revoga toda a sessão.
+func (s *AuthService) Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error) {
+	current, err := s.sessionRepo.FindRefreshTokenByHash(ctx, securetoken.Hash(req.RefreshToken))
+	if err != nil {
+		return nil, errors.New("refresh token inválido")
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, current.SessionID)
+	if err != nil {
+		return nil, errors.New("refresh token inválido")
+	}
+
+	now := time.Now()
+
+	// Token já usado: alguém está reaproveitando um token antigo
+	if current.UsedAt != nil {
+		s.revokeReusedSession(ctx, session)
+		return nil, errors.New("refresh token inválido")
+	}
+
+	if !session.IsActive(now) || now.After(current.ExpiresAt) {
+		return nil, errors.New("refresh token inválido")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, session.UserID)
+	if err != nil || user.IsDisabled() {
+		return nil, errors.New("refresh token inválido")
+	}
+
+	refreshToken, next, err := s.newRefreshToken(session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.sessionRepo.Rotate(ctx, current.ID, next, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Outra requisição usou o mesmo token ao mesmo tempo
+		s.revokeReusedSession(ctx, session)
+		return nil, errors.New("refresh token inválido")
+	}
+
+	return s.buildLoginResponse(user, session.ID, refreshToken, next.ExpiresAt)
+}
+
+// Logout revoga a sessão associada ao refresh token.
+// A operação é idempotente: tokens desconhecidos não geram erro.
+func (s *AuthService) Logout(ctx context.Context, req *LogoutRequest) error {
+	token, err := s.sessionRepo.FindRefreshTokenByHash(ctx, securetoken.Hash(req.RefreshToken))
+	if err != nil {
+		return nil
+	}
+	return s.sessionRepo.Revoke(ctx, token.SessionID, "logout")
+}
+
+// ListSessions lista as sessões ativas do usuário
+func (s *AuthService) ListSessions(ctx context.Context, userID uint, currentSessionID string) (*ListSessionsResponse, error) {
+	sessions, err := s.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionResponses := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = SessionResponse{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastUsedAt: session.LastUsedAt.Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+			Current:    session.ID == currentSessionID,
+		}
+	}
+
+	return &ListSessionsResponse{
+		Sessions: sessionResponses,
+		Total:    len(sessionResponses),
+	}, nil
+}
+
+// RevokeSession revoga uma sessão do usuário (ex.: dispositivo perdido)
+func (s *AuthService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return errors.New("sessão não encontrada")
+	}
+	return s.sessionRepo.Revoke(ctx, session.ID, "revoked")
+}
+
+// startSession cria uma nova sessão para o usuário e emite o primeiro par de tokens
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, client ClientInfo) (*LoginResponse, error) {
+	if user.IsDisabled() {
+		return nil, errors.New("conta desativada")
+	}
+
+	now := time.Now()
+	sessionID := uuid.New().String()
+
+	refreshToken, token, err := s.newRefreshToken(sessionID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		DeviceName: client.DeviceName,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  client.IPAddress,
+		LastUsedAt: now,
+		ExpiresAt:  token.ExpiresAt,
+	}
+
+	if err := s.sessionRepo.Create(ctx, session, token); err != nil {
+		return nil, errors.New("erro ao criar sessão")
+	}
+
+	return s.buildLoginResponse(user, session.ID, refreshToken, token.ExpiresAt)
+}
+
+// newRefreshToken gera um refresh token opaco e o registro com seu hash
+func (s *AuthService) newRefreshToken(sessionID string, now time.Time) (string, *domain.RefreshToken, error) {
+	refreshToken, err := securetoken.Generate(refreshTokenBytes)
+	if err != nil {
+		return "", nil, errors.New("erro ao gerar refresh token")
+	}
+	return refreshToken, &domain.RefreshToken{
+		SessionID: sessionID,
+		TokenHash: securetoken.Hash(refreshToken),
+		ExpiresAt: now.Add(s.refreshTokenTTL),
+	}, nil
+}
+
+// buildLoginResponse gera o token de acesso e monta a resposta
+func (s *AuthService) buildLoginResponse(user *domain.User, sessionID, refreshToken string, refreshExpiresAt time.Time) (*LoginResponse, error) {
+	token, expiresAt, err := s.tokenService.GenerateAccessToken(user, sessionID)
+	if err != nil {
+		return nil, errors.New("erro ao gerar token")
+	}
+
+	userResponse := toUserResponse(user)
+	return &LoginResponse{
+		User:             &userResponse,
+		Token:            token,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt.Format(time.RFC3339),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt.Format(time.RFC3339),
+		SessionID:        sessionID,
+	}, nil
+}
+
+// revokeReusedSession revoga a sessão inteira quando um refresh token é reutilizado
+func (s *AuthService) revokeReusedSession(ctx context.Context, session *domain.Session) {
+	log.Printf("Aviso: reuso de refresh token detectado na sessão %s (usuário %d), sessão revogada", session.ID, session.UserID)
+	if err := s.sessionRepo.Revoke(ctx, session.ID, "reuse_detected"); err != nil {
+		log.Printf("Erro ao revogar sessão %s: %v", session.ID, err)
+	}
+}
+
+// truncate limita o tamanho de uma string
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
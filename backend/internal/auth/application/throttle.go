@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+)
+
+// TooManyAttemptsError indica que a conta ou o IP está em backoff ou bloqueado
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+// Error implementa a interface error
+func (e *TooManyAttemptsError) Error() string {
+	return "muitas tentativas de login, tente novamente mais tarde"
+}
+
+// throttleKey associa uma chave do registro de tentativas à sua política
+type throttleKey struct {
+	key    string
+	policy domain.LoginThrottlePolicy
+}
+
+// loginThrottleKeys retorna as chaves de conta e de IP do login.
+// A chave da conta é sempre a primeira.
+func (s *AuthService) loginThrottleKeys(email, ip string) []throttleKey {
+	keys := []throttleKey{
+		{key: "email:" + strings.ToLower(strings.TrimSpace(email)), policy: s.accountThrottle},
+	}
+	if ip != "" {
+		keys = append(keys, throttleKey{key: "ip:" + ip, policy: s.ipThrottle})
+	}
+	return keys
+}
+
+// twoFactorThrottleKey retorna a chave do segundo passo do login do usuário
+func (s *AuthService) twoFactorThrottleKey(userID uint) throttleKey {
+	return throttleKey{key: fmt.Sprintf("2fa:%d", userID), policy: s.accountThrottle}
+}
+
+// reserveLoginAttempt reserva a tentativa em cada chave antes da verificação
+// da senha: a tentativa já conta como falha, então requisições simultâneas
+// para a mesma conta ou IP esperam o backoff em vez de rodar o bcrypt juntas.
+// Se alguma chave estiver em espera, as reservas feitas são desfeitas e
+// retorna TooManyAttemptsError.
+func (s *AuthService) reserveLoginAttempt(ctx context.Context, keys ...throttleKey) ([]*domain.LoginReservation, error) {
+	now := time.Now()
+	reservations := make([]*domain.LoginReservation, 0, len(keys))
+
+	for _, k := range keys {
+		reservation, wait, err := s.loginTracker.Reserve(ctx, k.key, now, k.policy)
+		if err != nil {
+			s.releaseLoginAttempt(ctx, reservations...)
+			return nil, fmt.Errorf("erro ao verificar tentativas de login: %w", err)
+		}
+		if reservation == nil {
+			s.releaseLoginAttempt(ctx, reservations...)
+			return nil, &TooManyAttemptsError{RetryAfter: wait}
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, nil
+}
+
+// loginAttemptFailed mantém as reservas como falhas e avisa quando uma chave
+// atinge o bloqueio
+func (s *AuthService) loginAttemptFailed(keys []throttleKey, reservations []*domain.LoginReservation) {
+	for i, reservation := range reservations {
+		policy := keys[i].policy
+		if policy.MaxFailures > 0 && reservation.Reserved.Failures == policy.MaxFailures {
+			log.Printf("Aviso: %s bloqueado por %s após %d falhas de login", reservation.Key, policy.LockoutDuration, reservation.Reserved.Failures)
+		}
+	}
+}
+
+// releaseLoginAttempt desfaz as reservas de uma tentativa que não falhou
+func (s *AuthService) releaseLoginAttempt(ctx context.Context, reservations ...*domain.LoginReservation) {
+	for _, reservation := range reservations {
+		if err := s.loginTracker.Release(ctx, reservation); err != nil {
+			log.Printf("Erro ao desfazer reserva de tentativa de login (%s): %v", reservation.Key, err)
+		}
+	}
+}
+
+// resetLoginAttempts remove o histórico de falhas da chave
+func (s *AuthService) resetLoginAttempts(ctx context.Context, k throttleKey) {
+	if err := s.loginTracker.Reset(ctx, k.key); err != nil {
+		log.Printf("Erro ao limpar tentativas de login (%s): %v", k.key, err)
+	}
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	addUser(t, users, "ana@example.com", "senha-correta", true)
+
+	login := func(password, ip string) error {
+		_, err := service.Login(ctx, &LoginRequest{Email: "ana@example.com", Password: password, ClientInfo: ClientInfo{IPAddress: ip}})
+		return err
+	}
+
+	if err := login("errada", "10.0.0.1"); err == nil || err.Error() != "credenciais inválidas" {
+		t.Fatalf("primeira falha = %v", err)
+	}
+
+	// Em espera, nem a senha correta é verificada, mesmo de outro IP
+	var tooMany *TooManyAttemptsError
+	if err := login("senha-correta", "10.0.0.2"); !errors.As(err, &tooMany) {
+		t.Fatalf("login em espera = %v, esperado TooManyAttemptsError", err)
+	}
+	if tooMany.RetryAfter <= 0 || tooMany.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %s, esperado até 1m", tooMany.RetryAfter)
+	}
+}
+
+func TestLoginConcurrentAttempts(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	addUser(t, users, "ana@example.com", "senha-correta", true)
+
+	// Requisições simultâneas para a mesma conta: só uma passa pelo backoff e
+	// chega à verificação da senha
+	const attempts = 8
+	errs := make([]error, attempts)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = service.Login(ctx, &LoginRequest{
+				Email:      "ana@example.com",
+				Password:   fmt.Sprintf("tentativa-%d", i),
+				ClientInfo: ClientInfo{IPAddress: fmt.Sprintf("10.0.0.%d", i)},
+			})
+		}()
+	}
+	wg.Wait()
+
+	verified, throttled := 0, 0
+	for _, err := range errs {
+		var tooMany *TooManyAttemptsError
+		switch {
+		case errors.As(err, &tooMany):
+			throttled++
+		case err != nil && err.Error() == "credenciais inválidas":
+			verified++
+		default:
+			t.Errorf("erro inesperado: %v", err)
+		}
+	}
+	if verified != 1 || throttled != attempts-1 {
+		t.Errorf("%d senhas verificadas e %d em espera, esperado 1 e %d", verified, throttled, attempts-1)
+	}
+}
+
+func TestLoginSuccessReleasesReservation(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	addUser(t, users, "ana@example.com", "senha-da-ana", true)
+	addUser(t, users, "bruno@example.com", "senha-do-bruno", true)
+
+	// Dois logins corretos seguidos do mesmo IP: a reserva do IP é desfeita
+	for _, req := range []LoginRequest{
+		{Email: "ana@example.com", Password: "senha-da-ana"},
+		{Email: "bruno@example.com", Password: "senha-do-bruno"},
+		{Email: "ana@example.com", Password: "senha-da-ana"},
+	} {
+		req.IPAddress = "10.0.0.1"
+		if _, err := service.Login(ctx, &req); err != nil {
+			t.Fatalf("login de %s: %v", req.Email, err)
+		}
+	}
+}
+
+func TestLoginThrottledIPKeepsAccountFree(t *testing.T) {
+	ctx := context.Background()
+	service, users := newTestAuthService(t, nil)
+	addUser(t, users, "ana@example.com", "senha-da-ana", true)
+
+	// Uma falha de outra conta deixa o IP em espera
+	service.Login(ctx, &LoginRequest{Email: "ninguem@example.com", Password: "x", ClientInfo: ClientInfo{IPAddress: "10.0.0.1"}})
+
+	var tooMany *TooManyAttemptsError
+	_, err := service.Login(ctx, &LoginRequest{Email: "ana@example.com", Password: "senha-da-ana", ClientInfo: ClientInfo{IPAddress: "10.0.0.1"}})
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("login do IP em espera = %v, esperado TooManyAttemptsError", err)
+	}
+
+	// A reserva feita na conta antes da recusa pelo IP foi desfeita
+	if _, err := service.Login(ctx, &LoginRequest{Email: "ana@example.com", Password: "senha-da-ana", ClientInfo: ClientInfo{IPAddress: "10.0.0.2"}}); err != nil {
+		t.Errorf("login de outro IP: %v", err)
+	}
+}
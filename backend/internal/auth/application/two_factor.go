@@ -0,0 +1,283 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/pkg/password"
+	"cloud-reader/backend/pkg/securetoken"
+	"cloud-reader/backend/pkg/totp"
+)
+
+const (
+	// recoveryCodeCount é a quantidade de códigos de recuperação gerados
+	recoveryCodeCount = 10
+
+	// recoveryCodeLength é o tamanho de cada código (sem o separador)
+	recoveryCodeLength = 10
+
+	// totpSkew é a tolerância de janelas TOTP para cada lado (relógios dessincronizados)
+	totpSkew = 1
+)
+
+// recoveryCodeAlphabet exclui caracteres ambíguos (0/o, 1/l/i)
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// EnrollTwoFactor inicia a configuração do 2FA, gerando um novo segredo pendente.
+// O 2FA só passa a valer após a confirmação com um código válido.
+func (s *AuthService) EnrollTwoFactor(ctx context.Context, userID uint) (*TwoFactorEnrollResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("autenticação em dois fatores já está ativa")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("erro ao gerar segredo")
+	}
+
+	if err := s.userRepo.UpdateTwoFactor(ctx, user.ID, secret, nil); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor ativa o 2FA após validar um código gerado com o segredo pendente
+// e retorna os códigos de recuperação
+func (s *AuthService) ConfirmTwoFactor(ctx context.Context, userID uint, req *TwoFactorCodeRequest) (*RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("autenticação em dois fatores já está ativa")
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, errors.New("configuração de dois fatores não iniciada")
+	}
+
+	step, ok := totp.Validate(user.TwoFactorSecret, req.Code, time.Now(), totpSkew)
+	if !ok {
+		return nil, errors.New("código inválido")
+	}
+
+	enabledAt := time.Now()
+	if err := s.userRepo.UpdateTwoFactor(ctx, user.ID, user.TwoFactorSecret, &enabledAt); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.AdvanceTwoFactorStep(ctx, user.ID, step); err != nil {
+		return nil, err
+	}
+
+	return s.regenerateRecoveryCodes(ctx, user.ID)
+}
+
+// DisableTwoFactor desativa o 2FA. Exige a senha e um código TOTP ou de recuperação.
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userID uint, req *TwoFactorDisableRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return errors.New("autenticação em dois fatores não está ativa")
+	}
+	if !password.Verify(req.Password, user.Password) {
+		return errors.New("senha incorreta")
+	}
+	if err := s.verifySecondFactor(ctx, user, req.Code, req.RecoveryCode); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdateTwoFactor(ctx, user.ID, "", nil); err != nil {
+		return err
+	}
+	return s.recoveryCodeRepo.DeleteByUserID(ctx, user.ID)
+}
+
+// RegenerateRecoveryCodes invalida os códigos de recuperação atuais e gera novos
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uint, req *TwoFactorCodeRequest) (*RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, errors.New("autenticação em dois fatores não está ativa")
+	}
+	if err := s.verifySecondFactor(ctx, user, req.Code, ""); err != nil {
+		return nil, err
+	}
+
+	return s.regenerateRecoveryCodes(ctx, user.ID)
+}
+
+// GetTwoFactorStatus retorna o estado do 2FA do usuário
+func (s *AuthService) GetTwoFactorStatus(ctx context.Context, userID uint) (*TwoFactorStatusResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TwoFactorStatusResponse{Enabled: user.IsTwoFactorEnabled()}
+	if resp.Enabled {
+		remaining, err := s.recoveryCodeRepo.CountUnused(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		resp.RemainingRecoveryCodes = remaining
+	}
+	return resp, nil
+}
+
+// LoginTwoFactor conclui o login de um usuário com 2FA ativo e abre a sessão
+func (s *AuthService) LoginTwoFactor(ctx context.Context, req *TwoFactorLoginRequest) (*LoginResponse, error) {
+	challenge, err := s.userTokenRepo.FindByHash(ctx, domain.TokenPurposeTwoFactorLogin, securetoken.Hash(req.ChallengeToken))
+	if err != nil || !challenge.IsValid(time.Now()) {
+		return nil, errors.New("desafio inválido ou expirado")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil || !user.IsTwoFactorEnabled() {
+		return nil, errors.New("desafio inválido ou expirado")
+	}
+
+	// Limita as tentativas de adivinhar o código
+	throttle := s.twoFactorThrottleKey(user.ID)
+	reservations, err := s.reserveLoginAttempt(ctx, throttle)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(ctx, user, req.Code, req.RecoveryCode); err != nil {
+		if err.Error() == "código inválido" {
+			s.loginAttemptFailed([]throttleKey{throttle}, reservations)
+		} else {
+			s.releaseLoginAttempt(ctx, reservations...)
+		}
+		return nil, err
+	}
+	s.resetLoginAttempts(ctx, throttle)
+
+	// O desafio só pode ser concluído uma vez
+	used, err := s.userTokenRepo.MarkUsed(ctx, challenge.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("desafio inválido ou expirado")
+	}
+
+	return s.startSession(ctx, user, req.ClientInfo)
+}
+
+// startTwoFactorChallenge emite o desafio do segundo passo do login
+func (s *AuthService) startTwoFactorChallenge(ctx context.Context, user *domain.User) (*LoginResponse, error) {
+	if user.IsDisabled() {
+		return nil, errors.New("conta desativada")
+	}
+
+	challenge, err := s.issueUserToken(ctx, user.ID, domain.TokenPurposeTwoFactorLogin, s.twoFactorChallengeTTL)
+	if err != nil {
+		return nil, errors.New("erro ao iniciar autenticação em dois fatores")
+	}
+
+	return &LoginResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     challenge,
+		ChallengeExpiresAt: time.Now().Add(s.twoFactorChallengeTTL).Format(time.RFC3339),
+	}, nil
+}
+
+// verifySecondFactor valida um código TOTP (sem permitir reuso) ou consome um código de recuperação
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *domain.User, code, recoveryCode string) error {
+	if code != "" {
+		step, ok := totp.Validate(user.TwoFactorSecret, code, time.Now(), totpSkew)
+		if !ok || step <= user.TwoFactorLastStep {
+			return errors.New("código inválido")
+		}
+		advanced, err := s.userRepo.AdvanceTwoFactorStep(ctx, user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return errors.New("código inválido")
+		}
+		return nil
+	}
+
+	if recoveryCode != "" {
+		consumed, err := s.recoveryCodeRepo.Consume(ctx, user.ID, securetoken.Hash(normalizeRecoveryCode(recoveryCode)), time.Now())
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return errors.New("código inválido")
+		}
+		return nil
+	}
+
+	return errors.New("código inválido")
+}
+
+// regenerateRecoveryCodes gera e armazena (apenas o hash) um novo conjunto de códigos
+func (s *AuthService) regenerateRecoveryCodes(ctx context.Context, userID uint) (*RecoveryCodesResponse, error) {
+	plain := make([]string, recoveryCodeCount)
+	codes := make([]*domain.RecoveryCode, recoveryCodeCount)
+	for i := range plain {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, errors.New("erro ao gerar códigos de recuperação")
+		}
+		plain[i] = code
+		codes[i] = &domain.RecoveryCode{
+			UserID:   userID,
+			CodeHash: securetoken.Hash(normalizeRecoveryCode(code)),
+		}
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, userID, codes); err != nil {
+		return nil, err
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: plain}, nil
+}
+
+// generateRecoveryCode gera um código no formato xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	// Descarta bytes acima do maior múltiplo do alfabeto para evitar viés de módulo
+	limit := byte(256 - 256%len(recoveryCodeAlphabet))
+
+	var code strings.Builder
+	buf := make([]byte, 1)
+	for n := 0; n < recoveryCodeLength; {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		if buf[0] >= limit {
+			continue
+		}
+		if n == recoveryCodeLength/2 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(recoveryCodeAlphabet[int(buf[0])%len(recoveryCodeAlphabet)])
+		n++
+	}
+	return code.String(), nil
+}
+
+// normalizeRecoveryCode remove separadores e espaços e converte para minúsculas
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
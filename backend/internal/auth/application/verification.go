@@ -0,0 +1,211 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/mailer"
+	"cloud-reader/backend/pkg/password"
+	"cloud-reader/backend/pkg/securetoken"
+)
+
+const (
+	// userTokenBytes é a entropia (em bytes) dos tokens enviados por email
+	userTokenBytes = 32
+
+	// mailTimeout é o tempo máximo para envio de um email em segundo plano
+	mailTimeout = 30 * time.Second
+)
+
+// ForgotPassword envia um link de redefinição de senha para o email informado.
+// A resposta é sempre a mesma, exista ou não uma conta com o email (evita enumeração).
+func (s *AuthService) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		return nil
+	}
+
+	return s.sendPasswordResetEmail(ctx, user)
+}
+
+// sendPasswordResetEmail emite um novo link de redefinição de senha e o envia ao usuário
+func (s *AuthService) sendPasswordResetEmail(ctx context.Context, user *domain.User) error {
+	// Apenas o link mais recente é válido
+	if err := s.userTokenRepo.InvalidateByUserID(ctx, user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, err := s.issueUserToken(ctx, user.ID, domain.TokenPurposePasswordReset, s.passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.appBaseURL + "/reset-password?token=" + url.QueryEscape(token)
+	s.sendMailAsync(&mailer.Message{
+		To:      user.Email,
+		Subject: "Redefinição de senha - Cloud Reader",
+		Body: fmt.Sprintf(
+			"Olá, %s!\n\nRecebemos uma solicitação para redefinir a senha da sua conta.\n"+
+				"Use o link abaixo em até %s:\n\n%s\n\n"+
+				"Se você não fez esta solicitação, ignore este email.\n",
+			user.Name, formatTTL(s.passwordResetTTL), link,
+		),
+	})
+
+	return nil
+}
+
+// ResetPassword redefine a senha usando um token de redefinição válido.
+// Todas as sessões do usuário são revogadas.
+func (s *AuthService) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	token, err := s.consumeUserToken(ctx, domain.TokenPurposePasswordReset, req.Token)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := password.Hash(req.Password)
+	if err != nil {
+		return errors.New("erro ao processar senha")
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, token.UserID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllByUserID(ctx, token.UserID, "", "password_reset"); err != nil {
+		log.Printf("Erro ao revogar sessões do usuário %d: %v", token.UserID, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail confirma o email do usuário usando um token de verificação válido
+func (s *AuthService) VerifyEmail(ctx context.Context, req *VerifyEmailRequest) error {
+	token, err := s.consumeUserToken(ctx, domain.TokenPurposeEmailVerification, req.Token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, token.UserID, time.Now()); err != nil {
+		return err
+	}
+
+	// Com o email comprovado, a conta listada em ADMIN_EMAILS vira administradora
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+	return s.promoteAdmin(ctx, user)
+}
+
+// ResendVerificationEmail reenvia o link de verificação para o usuário autenticado
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return errors.New("email já verificado")
+	}
+
+	s.sendVerificationEmail(user)
+	return nil
+}
+
+// sendVerificationEmail gera um token de verificação e envia o link por email
+func (s *AuthService) sendVerificationEmail(user *domain.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+	defer cancel()
+
+	if err := s.userTokenRepo.InvalidateByUserID(ctx, user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		log.Printf("Erro ao invalidar tokens de verificação do usuário %d: %v", user.ID, err)
+		return
+	}
+
+	token, err := s.issueUserToken(ctx, user.ID, domain.TokenPurposeEmailVerification, s.emailVerificationTTL)
+	if err != nil {
+		log.Printf("Erro ao gerar token de verificação do usuário %d: %v", user.ID, err)
+		return
+	}
+
+	link := s.appBaseURL + "/verify-email?token=" + url.QueryEscape(token)
+	s.sendMailAsync(&mailer.Message{
+		To:      user.Email,
+		Subject: "Confirme seu email - Cloud Reader",
+		Body: fmt.Sprintf(
+			"Olá, %s!\n\nConfirme seu email acessando o link abaixo em até %s:\n\n%s\n",
+			user.Name, formatTTL(s.emailVerificationTTL), link,
+		),
+	})
+}
+
+// issueUserToken cria um token de uso único e retorna seu valor em texto puro
+func (s *AuthService) issueUserToken(ctx context.Context, userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, err := securetoken.Generate(userTokenBytes)
+	if err != nil {
+		return "", errors.New("erro ao gerar token")
+	}
+
+	if err := s.userTokenRepo.Create(ctx, &domain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: securetoken.Hash(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeUserToken valida e marca como usado um token de uso único
+func (s *AuthService) consumeUserToken(ctx context.Context, purpose string, value string) (*domain.UserToken, error) {
+	token, err := s.userTokenRepo.FindByHash(ctx, purpose, securetoken.Hash(value))
+	if err != nil {
+		return nil, errors.New("token inválido ou expirado")
+	}
+
+	now := time.Now()
+	if !token.IsValid(now) {
+		return nil, errors.New("token inválido ou expirado")
+	}
+
+	used, err := s.userTokenRepo.MarkUsed(ctx, token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("token inválido ou expirado")
+	}
+
+	return token, nil
+}
+
+// sendMailAsync envia o email em segundo plano, sem bloquear a requisição
+func (s *AuthService) sendMailAsync(msg *mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("Erro ao enviar email para %s: %v", msg.To, err)
+		}
+	}()
+}
+
+// formatTTL formata a validade de um link para exibição no email
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		hours := int(ttl / time.Hour)
+		if hours == 1 {
+			return "1 hora"
+		}
+		return fmt.Sprintf("%d horas", hours)
+	}
+	return fmt.Sprintf("%d minutos", int(ttl/time.Minute))
+}
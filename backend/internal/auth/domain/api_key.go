@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Escopos das chaves de API
+const (
+	ScopeBooksRead     = "books:read"
+	ScopeBooksWrite    = "books:write"
+	ScopeProgressWrite = "progress:write"
+)
+
+// APIKeyPrefix identifica as chaves de API e as diferencia dos tokens de acesso JWT
+const APIKeyPrefix = "cr_"
+
+// APIKey representa uma chave de API pessoal usada por scripts e dispositivos.
+// Apenas o hash é armazenado; a chave em texto puro é exibida uma única vez.
+type APIKey struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Name       string     `gorm:"not null" json:"name"`
+	Prefix     string     `gorm:"not null" json:"prefix"` // Início da chave, para identificação na listagem
+	KeyHash    string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes     string     `gorm:"not null" json:"scopes"` // Escopos separados por vírgula
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil = não expira
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList retorna os escopos da chave
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// IsActive retorna true se a chave não foi revogada e não expirou
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// IsValidScope retorna true se o escopo é conhecido
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopeBooksRead, ScopeBooksWrite, ScopeProgressWrite:
+		return true
+	default:
+		return false
+	}
+}
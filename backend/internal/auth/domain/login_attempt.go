@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttempt representa o histórico recente de falhas de login de uma chave
+// (email ou IP do cliente)
+type LoginAttempt struct {
+	Key           string    `gorm:"primarykey" json:"key"`
+	Failures      int       `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time `gorm:"not null;index" json:"last_failure_at"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginReservation é uma tentativa de login em andamento, já contada como falha
+type LoginReservation struct {
+	Key      string
+	Reserved LoginAttempt  // Histórico com a tentativa contada
+	Previous *LoginAttempt // Histórico anterior (nil se não havia falhas na janela)
+}
+
+// LoginAttemptTracker define a interface de registro de falhas de login (port)
+type LoginAttemptTracker interface {
+	// Reserve verifica a espera da chave e, se ela estiver livre, conta a
+	// tentativa como falha na mesma operação atômica. Assim requisições
+	// simultâneas não passam juntas pela verificação. Com a chave em espera,
+	// retorna a reserva nil e o tempo restante.
+	Reserve(ctx context.Context, key string, now time.Time, policy LoginThrottlePolicy) (*LoginReservation, time.Duration, error)
+
+	// Release desfaz uma reserva restaurando o histórico anterior, desde que
+	// ele não tenha sido alterado por outra tentativa depois da reserva
+	Release(ctx context.Context, reservation *LoginReservation) error
+
+	// Reset remove o histórico da chave
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginThrottlePolicy define o backoff exponencial e o bloqueio temporário de uma chave
+type LoginThrottlePolicy struct {
+	BaseDelay       time.Duration // Espera após a primeira falha (dobra a cada falha)
+	MaxDelay        time.Duration // Espera máxima do backoff
+	MaxFailures     int           // Falhas até o bloqueio temporário
+	LockoutDuration time.Duration // Duração do bloqueio
+	Window          time.Duration // Falhas mais antigas que a janela são esquecidas
+}
+
+// RetryAfter retorna quanto tempo a chave ainda deve esperar antes de uma nova tentativa
+func (p LoginThrottlePolicy) RetryAfter(attempt *LoginAttempt, now time.Time) time.Duration {
+	if attempt == nil || attempt.Failures == 0 || now.Sub(attempt.LastFailureAt) >= p.Window {
+		return 0
+	}
+
+	var wait time.Duration
+	if p.MaxFailures > 0 && attempt.Failures >= p.MaxFailures {
+		wait = p.LockoutDuration
+	} else {
+		wait = p.BaseDelay
+		for i := 1; i < attempt.Failures && wait < p.MaxDelay; i++ {
+			wait *= 2
+		}
+		if wait > p.MaxDelay {
+			wait = p.MaxDelay
+		}
+	}
+
+	remaining := attempt.LastFailureAt.Add(wait).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Reserve calcula a reserva de uma tentativa sobre o histórico atual da chave.
+// Se a chave ainda estiver em espera, retorna nil e o tempo restante.
+func (p LoginThrottlePolicy) Reserve(key string, current *LoginAttempt, now time.Time) (*LoginReservation, time.Duration) {
+	var previous *LoginAttempt
+	if current != nil && current.Failures > 0 && now.Sub(current.LastFailureAt) < p.Window {
+		copied := *current
+		previous = &copied
+	}
+	if wait := p.RetryAfter(previous, now); wait > 0 {
+		return nil, wait
+	}
+
+	reservation := &LoginReservation{
+		Key:      key,
+		Reserved: LoginAttempt{Key: key, Failures: 1, LastFailureAt: now},
+		Previous: previous,
+	}
+	if previous != nil {
+		reservation.Reserved.Failures = previous.Failures + 1
+	}
+	return reservation, 0
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginThrottlePolicyRetryAfter(t *testing.T) {
+	policy := LoginThrottlePolicy{
+		BaseDelay:       time.Second,
+		MaxDelay:        10 * time.Second,
+		MaxFailures:     5,
+		LockoutDuration: time.Hour,
+		Window:          24 * time.Hour,
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		attempt *LoginAttempt
+		want    time.Duration
+	}{
+		{name: "sem histórico", attempt: nil, want: 0},
+		{name: "sem falhas", attempt: &LoginAttempt{LastFailureAt: now}, want: 0},
+		{name: "uma falha", attempt: &LoginAttempt{Failures: 1, LastFailureAt: now}, want: time.Second},
+		{name: "backoff dobra", attempt: &LoginAttempt{Failures: 3, LastFailureAt: now}, want: 4 * time.Second},
+		{name: "backoff limitado", attempt: &LoginAttempt{Failures: 4, LastFailureAt: now}, want: 8 * time.Second},
+		{name: "espera em andamento", attempt: &LoginAttempt{Failures: 3, LastFailureAt: now.Add(-3 * time.Second)}, want: time.Second},
+		{name: "espera cumprida", attempt: &LoginAttempt{Failures: 3, LastFailureAt: now.Add(-time.Minute)}, want: 0},
+		{name: "bloqueio", attempt: &LoginAttempt{Failures: 5, LastFailureAt: now.Add(-time.Minute)}, want: 59 * time.Minute},
+		{name: "fora da janela", attempt: &LoginAttempt{Failures: 50, LastFailureAt: now.Add(-25 * time.Hour)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.RetryAfter(tt.attempt, now); got != tt.want {
+				t.Errorf("RetryAfter = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+
+	capped := policy
+	capped.MaxFailures = 0
+	if got := capped.RetryAfter(&LoginAttempt{Failures: 30, LastFailureAt: now}, now); got != capped.MaxDelay {
+		t.Errorf("RetryAfter sem bloqueio = %s, esperado %s", got, capped.MaxDelay)
+	}
+}
+
+func TestLoginThrottlePolicyReserve(t *testing.T) {
+	policy := LoginThrottlePolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Hour}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	reservation, wait := policy.Reserve("email:a", nil, now)
+	if reservation == nil || wait != 0 {
+		t.Fatalf("Reserve sem histórico = %v, %s", reservation, wait)
+	}
+	if reservation.Previous != nil || reservation.Reserved.Failures != 1 || !reservation.Reserved.LastFailureAt.Equal(now) {
+		t.Errorf("reserva = %+v", reservation)
+	}
+
+	// Falhas anteriores continuam contando
+	current := &LoginAttempt{Key: "email:a", Failures: 2, LastFailureAt: now.Add(-time.Minute)}
+	reservation, _ = policy.Reserve("email:a", current, now)
+	if reservation == nil || reservation.Reserved.Failures != 3 || *reservation.Previous != *current {
+		t.Errorf("reserva com histórico = %+v", reservation)
+	}
+
+	// Em espera, nada é reservado
+	reservation, wait = policy.Reserve("email:a", &LoginAttempt{Failures: 2, LastFailureAt: now}, now)
+	if reservation != nil || wait != 2*time.Second {
+		t.Errorf("Reserve em espera = %v, %s", reservation, wait)
+	}
+
+	// Falhas fora da janela são esquecidas
+	reservation, _ = policy.Reserve("email:a", &LoginAttempt{Failures: 9, LastFailureAt: now.Add(-2 * time.Hour)}, now)
+	if reservation == nil || reservation.Previous != nil || reservation.Reserved.Failures != 1 {
+		t.Errorf("reserva depois da janela = %+v", reservation)
+	}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserIdentity vincula um usuário a uma identidade de um provedor OpenID Connect
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID  uint   `gorm:"not null;index" json:"user_id"`
+	Issuer  string `gorm:"not null;uniqueIndex:idx_identity_issuer_subject" json:"issuer"`
+	Subject string `gorm:"not null;uniqueIndex:idx_identity_issuer_subject" json:"subject"`
+	Email   string `json:"email"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OIDCLoginState guarda os dados de um login OIDC em andamento (state, nonce e
+// code_verifier do PKCE). Cada state é de uso único.
+type OIDCLoginState struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	StateHash    string    `gorm:"uniqueIndex;not null" json:"-"`
+	Nonce        string    `gorm:"not null" json:"-"`
+	CodeVerifier string    `gorm:"not null" json:"-"`
+	RedirectPath string    `json:"redirect_path"` // Página do frontend após o login
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// OIDCClaims representa os claims verificados do ID token
+type OIDCClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCProvider define a interface do provedor OpenID Connect (port)
+type OIDCProvider interface {
+	// AuthCodeURL monta a URL de autorização com state, nonce e o desafio PKCE (S256)
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+
+	// Exchange troca o código de autorização pelos tokens e retorna os claims
+	// verificados do ID token (assinatura, issuer, audience e nonce)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCClaims, error)
+}
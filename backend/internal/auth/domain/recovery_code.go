@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+// RecoveryCode representa um código de recuperação de uso único da autenticação em
+// dois fatores. Apenas o hash é armazenado.
+type RecoveryCode struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID   uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash string     `gorm:"not null;index" json:"-"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
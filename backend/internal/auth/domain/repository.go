@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // UserRepository define a interface do repositório de usuários (port)
@@ -17,5 +18,160 @@ type UserRepository interface {
 
 	// ExistsByEmail verifica se já existe um usuário com o email fornecido
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+
+	// UpdatePassword atualiza o hash da senha do usuário
+	UpdatePassword(ctx context.Context, id uint, hashedPassword string) error
+
+	// MarkEmailVerified marca o email do usuário como verificado
+	MarkEmailVerified(ctx context.Context, id uint, verifiedAt time.Time) error
+
+	// UpdateTwoFactor atualiza o segredo TOTP e o estado da autenticação em dois fatores
+	UpdateTwoFactor(ctx context.Context, id uint, secret string, enabledAt *time.Time) error
+
+	// AdvanceTwoFactorStep registra a janela TOTP usada. Retorna false se a janela
+	// não for posterior à última aceita (código reutilizado).
+	AdvanceTwoFactorStep(ctx context.Context, id uint, step int64) (bool, error)
+
+	// List lista os usuários com paginação, retornando também o total
+	List(ctx context.Context, filter UserListFilter) ([]*User, int64, error)
+
+	// UpdateRole altera o papel do usuário
+	UpdateRole(ctx context.Context, id uint, role string) error
+
+	// SetDisabled desativa (disabledAt != nil) ou reativa a conta
+	SetDisabled(ctx context.Context, id uint, disabledAt *time.Time) error
+
+	// UpdateProfile atualiza nome, email e o estado de verificação do email
+	UpdateProfile(ctx context.Context, user *User) error
+
+	// SoftDelete marca o usuário como removido, libera o email para um novo cadastro
+	// e apaga os dados de autenticação vinculados (exceto as sessões, revogadas à parte)
+	SoftDelete(ctx context.Context, id uint) error
+
+	// Delete remove definitivamente o usuário e os dados de autenticação vinculados
+	// (sessões, tokens, códigos de recuperação, chaves de API e identidades)
+	Delete(ctx context.Context, id uint) error
 }
 
+
+// SessionRepository define a interface do repositório de sessões e refresh tokens (port)
+type SessionRepository interface {
+	// Create cria uma nova sessão com seu primeiro refresh token
+	Create(ctx context.Context, session *Session, token *RefreshToken) error
+
+	// FindByID busca uma sessão pelo ID
+	FindByID(ctx context.Context, id string) (*Session, error)
+
+	// FindActiveByUserID busca as sessões ativas de um usuário
+	FindActiveByUserID(ctx context.Context, userID uint) ([]*Session, error)
+
+	// FindRefreshTokenByHash busca um refresh token pelo hash
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	// Rotate marca o token atual como usado e cria o próximo token da sessão.
+	// Retorna false se o token atual já tinha sido usado (reuso).
+	Rotate(ctx context.Context, currentTokenID uint, next *RefreshToken, lastUsedAt time.Time) (bool, error)
+
+	// Revoke revoga uma sessão
+	Revoke(ctx context.Context, id string, reason string) error
+
+	// RevokeAllByUserID revoga todas as sessões ativas de um usuário, exceto exceptID
+	RevokeAllByUserID(ctx context.Context, userID uint, exceptID string, reason string) error
+}
+
+// UserTokenRepository define a interface do repositório de tokens de uso único (port)
+type UserTokenRepository interface {
+	// Create cria um novo token
+	Create(ctx context.Context, token *UserToken) error
+
+	// FindByHash busca um token pelo propósito e hash
+	FindByHash(ctx context.Context, purpose string, tokenHash string) (*UserToken, error)
+
+	// MarkUsed marca o token como usado. Retorna false se ele já tinha sido usado.
+	MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+
+	// InvalidateByUserID invalida os tokens pendentes de um usuário para o propósito
+	InvalidateByUserID(ctx context.Context, userID uint, purpose string) error
+}
+
+// RecoveryCodeRepository define a interface do repositório de códigos de recuperação (port)
+type RecoveryCodeRepository interface {
+	// ReplaceForUser substitui todos os códigos de recuperação do usuário
+	ReplaceForUser(ctx context.Context, userID uint, codes []*RecoveryCode) error
+
+	// Consume marca como usado o código com o hash informado. Retorna false se não houver código válido.
+	Consume(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error)
+
+	// CountUnused conta os códigos ainda não usados do usuário
+	CountUnused(ctx context.Context, userID uint) (int64, error)
+
+	// DeleteByUserID remove todos os códigos do usuário
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
+
+// APIKeyRepository define a interface do repositório de chaves de API (port)
+type APIKeyRepository interface {
+	// Create cria uma nova chave
+	Create(ctx context.Context, key *APIKey) error
+
+	// FindByUserID busca as chaves não revogadas de um usuário
+	FindByUserID(ctx context.Context, userID uint) ([]*APIKey, error)
+
+	// FindByHash busca uma chave pelo hash
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	// Revoke revoga uma chave (valida ownership)
+	Revoke(ctx context.Context, id uint, userID uint) error
+
+	// TouchLastUsed atualiza a data de último uso. Para evitar uma escrita por
+	// requisição, só atualiza se o último uso for anterior a now-minInterval.
+	TouchLastUsed(ctx context.Context, id uint, now time.Time, minInterval time.Duration) error
+}
+
+// UserIdentityRepository define a interface do repositório de identidades externas (port)
+type UserIdentityRepository interface {
+	// Create vincula uma identidade a um usuário
+	Create(ctx context.Context, identity *UserIdentity) error
+
+	// FindByIssuerSubject busca uma identidade pelo provedor e subject
+	FindByIssuerSubject(ctx context.Context, issuer, subject string) (*UserIdentity, error)
+}
+
+// OIDCStateRepository define a interface do repositório de logins OIDC em andamento (port)
+type OIDCStateRepository interface {
+	// Create registra um novo login em andamento
+	Create(ctx context.Context, state *OIDCLoginState) error
+
+	// Consume busca e remove o state (uso único)
+	Consume(ctx context.Context, stateHash string) (*OIDCLoginState, error)
+
+	// DeleteExpired remove os states expirados
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// UserDataRemover remove os dados de outros módulos vinculados a um usuário
+// (ex.: livros e arquivos) quando a conta é excluída (port)
+type UserDataRemover interface {
+	DeleteUserData(ctx context.Context, userID uint) error
+}
+
+// UserDataRemoverFunc adapta uma função à interface UserDataRemover
+type UserDataRemoverFunc func(ctx context.Context, userID uint) error
+
+// DeleteUserData chama f(ctx, userID)
+func (f UserDataRemoverFunc) DeleteUserData(ctx context.Context, userID uint) error {
+	return f(ctx, userID)
+}
+
+// UserDataRemovers executa vários UserDataRemover em sequência, parando no primeiro erro
+type UserDataRemovers []UserDataRemover
+
+// DeleteUserData chama DeleteUserData de cada item
+func (r UserDataRemovers) DeleteUserData(ctx context.Context, userID uint) error {
+	for _, remover := range r {
+		if err := remover.DeleteUserData(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
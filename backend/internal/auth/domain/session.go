@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+)
+
+// Session representa uma sessão de login de um dispositivo (família de refresh tokens)
+type Session struct {
+	ID        string    `gorm:"type:uuid;primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	DeviceName   string     `json:"device_name"`
+	UserAgent    string     `json:"user_agent"`
+	IPAddress    string     `json:"ip_address"`
+	LastUsedAt   time.Time  `gorm:"not null" json:"last_used_at"`
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"` // logout, revoked, reuse_detected
+}
+
+// TableName define o nome da tabela no banco de dados
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsActive retorna true se a sessão não foi revogada e não expirou
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken representa um refresh token emitido para uma sessão.
+// Apenas o hash do token é armazenado; cada token pode ser usado uma única vez.
+type RefreshToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	SessionID string     `gorm:"type:uuid;not null;index" json:"session_id"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"` // Preenchido quando o token é rotacionado
+}
+
+// TableName define o nome da tabela no banco de dados
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+)
+
+// AccessTokenClaims representa as informações extraídas de um token de acesso válido
+type AccessTokenClaims struct {
+	UserID    uint
+	SessionID string
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// TokenService define a interface de emissão e validação de tokens de acesso (port)
+type TokenService interface {
+	// GenerateAccessToken gera um token de acesso assinado para o usuário e a sessão
+	GenerateAccessToken(user *User, sessionID string) (string, time.Time, error)
+
+	// ValidateAccessToken valida a assinatura e a validade do token e retorna seus claims
+	ValidateAccessToken(token string) (*AccessTokenClaims, error)
+}
@@ -6,6 +6,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// Papéis de usuário
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User representa a entidade de usuário no domínio
 type User struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
@@ -16,10 +22,51 @@ type User struct {
 	Name     string `gorm:"not null" json:"name"`
 	Email    string `gorm:"uniqueIndex;not null" json:"email"`
 	Password string `gorm:"not null" json:"-"` // Senha não é exposta no JSON
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"` // nil = email não verificado
+
+	Role       string     `gorm:"not null;default:user" json:"role"`  // user ou admin
+	DisabledAt *time.Time `gorm:"index" json:"disabled_at,omitempty"` // nil = conta ativa
+
+	// Autenticação em dois fatores (TOTP)
+	TwoFactorSecret    string     `json:"-"`                               // Segredo TOTP (base32), pendente até a confirmação
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at,omitempty"` // nil = 2FA desabilitado
+	TwoFactorLastStep  int64      `gorm:"default:0" json:"-"`              // Última janela TOTP aceita (evita replay)
+}
+
+// IsTwoFactorEnabled retorna true se a autenticação em dois fatores está ativa
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// IsAdmin retorna true se o usuário é administrador
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsDisabled retorna true se a conta foi desativada por um administrador
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsValidRole retorna true se o papel é conhecido
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
+
+// UserListFilter define os filtros da listagem de usuários
+type UserListFilter struct {
+	Query  string // Busca por nome ou email (vazio = todos)
+	Offset int
+	Limit  int
+}
+
+// IsEmailVerified retorna true se o email do usuário foi verificado
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 // TableName define o nome da tabela no banco de dados
 func (User) TableName() string {
 	return "users"
 }
-
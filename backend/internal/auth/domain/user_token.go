@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+)
+
+// Propósitos dos tokens de uso único
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeTwoFactorLogin    = "two_factor_login"
+	TokenPurposeOIDCExchange      = "oidc_exchange"
+)
+
+// UserToken representa um token de uso único enviado ao usuário por email
+// (redefinição de senha, verificação de email). Apenas o hash é armazenado.
+type UserToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Purpose   string     `gorm:"not null;index" json:"purpose"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// IsValid retorna true se o token não foi usado e não expirou
+func (t *UserToken) IsValid(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package attempts
+
+import (
+	"fmt"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/auth/infrastructure/repository"
+	"cloud-reader/backend/internal/shared/config"
+
+	"gorm.io/gorm"
+)
+
+// New cria o registro de tentativas de login configurado em LOGIN_ATTEMPT_STORE.
+// Use postgres quando houver mais de uma instância do servidor.
+func New(db *gorm.DB, cfg *config.Config) (domain.LoginAttemptTracker, error) {
+	switch cfg.LoginAttemptStore {
+	case "memory":
+		return NewMemoryTracker(cfg.LoginFailureWindow), nil
+	case "postgres":
+		return repository.NewPostgresLoginAttemptTracker(db), nil
+	default:
+		return nil, fmt.Errorf("armazenamento de tentativas de login não suportado: %s", cfg.LoginAttemptStore)
+	}
+}
@@ -0,0 +1,87 @@
+package attempts
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+)
+
+// cleanupInterval é o intervalo mínimo entre limpezas das entradas expiradas
+const cleanupInterval = time.Minute
+
+// memoryTracker implementa LoginAttemptTracker em memória (uma única instância do servidor)
+type memoryTracker struct {
+	mu          sync.Mutex
+	attempts    map[string]*domain.LoginAttempt
+	window      time.Duration
+	lastCleanup time.Time
+}
+
+// NewMemoryTracker cria um novo registro de tentativas de login em memória.
+// window é usado para descartar entradas antigas.
+func NewMemoryTracker(window time.Duration) domain.LoginAttemptTracker {
+	return &memoryTracker{
+		attempts: make(map[string]*domain.LoginAttempt),
+		window:   window,
+	}
+}
+
+// Reserve verifica a espera e conta a tentativa com o mutex travado
+func (t *memoryTracker) Reserve(ctx context.Context, key string, now time.Time, policy domain.LoginThrottlePolicy) (*domain.LoginReservation, time.Duration, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cleanupLocked(now)
+
+	reservation, wait := policy.Reserve(key, t.attempts[key], now)
+	if reservation == nil {
+		return nil, wait, nil
+	}
+	reserved := reservation.Reserved
+	t.attempts[key] = &reserved
+	return reservation, 0, nil
+}
+
+// Release restaura o histórico anterior à reserva
+func (t *memoryTracker) Release(ctx context.Context, reservation *domain.LoginReservation) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	attempt, ok := t.attempts[reservation.Key]
+	if !ok || *attempt != reservation.Reserved {
+		// Outra tentativa alterou o histórico depois da reserva
+		return nil
+	}
+	if reservation.Previous == nil {
+		delete(t.attempts, reservation.Key)
+		return nil
+	}
+	previous := *reservation.Previous
+	t.attempts[reservation.Key] = &previous
+	return nil
+}
+
+// Reset remove o histórico da chave
+func (t *memoryTracker) Reset(ctx context.Context, key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.attempts, key)
+	return nil
+}
+
+// cleanupLocked remove entradas fora da janela (chamado com o mutex travado)
+func (t *memoryTracker) cleanupLocked(now time.Time) {
+	if now.Sub(t.lastCleanup) < cleanupInterval {
+		return
+	}
+	t.lastCleanup = now
+
+	for key, attempt := range t.attempts {
+		if now.Sub(attempt.LastFailureAt) >= t.window {
+			delete(t.attempts, key)
+		}
+	}
+}
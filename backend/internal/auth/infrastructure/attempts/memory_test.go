@@ -0,0 +1,54 @@
+package attempts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+)
+
+func TestMemoryTrackerRelease(t *testing.T) {
+	ctx := context.Background()
+	policy := domain.LoginThrottlePolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Hour}
+	tracker := NewMemoryTracker(policy.Window)
+	now := time.Now()
+
+	first, _, err := tracker.Reserve(ctx, "ip:1", now, policy)
+	if err != nil || first == nil {
+		t.Fatalf("Reserve = %v, %v", first, err)
+	}
+	if again, wait, _ := tracker.Reserve(ctx, "ip:1", now, policy); again != nil || wait != time.Second {
+		t.Errorf("segunda reserva = %v, %s; esperado espera de 1s", again, wait)
+	}
+
+	// Desfeita, a chave volta a ficar livre
+	if err := tracker.Release(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	second, _, _ := tracker.Reserve(ctx, "ip:1", now, policy)
+	if second == nil || second.Reserved.Failures != 1 {
+		t.Fatalf("reserva depois de desfeita = %+v", second)
+	}
+
+	// Uma reserva posterior não é apagada pela anterior
+	later, _, _ := tracker.Reserve(ctx, "ip:1", now.Add(2*time.Second), policy)
+	if later == nil || later.Reserved.Failures != 2 {
+		t.Fatalf("reserva posterior = %+v", later)
+	}
+	if err := tracker.Release(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	if again, _, _ := tracker.Reserve(ctx, "ip:1", now.Add(2*time.Second), policy); again != nil {
+		t.Error("reserva posterior desfeita pela anterior")
+	}
+
+	// Desfazer a reserva posterior restaura o histórico anterior a ela
+	if err := tracker.Release(ctx, later); err != nil {
+		t.Fatal(err)
+	}
+	restored, wait, _ := tracker.Reserve(ctx, "ip:1", now.Add(1500*time.Millisecond), policy)
+	if restored == nil || restored.Reserved.Failures != 2 || wait != 0 {
+		t.Errorf("reserva sobre o histórico restaurado = %+v, %s", restored, wait)
+	}
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"net/http"
+
+	"cloud-reader/backend/internal/auth/application"
+	"cloud-reader/backend/internal/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler gerencia os handlers HTTP da conta do usuário autenticado
+type AccountHandler struct {
+	accountService *application.AccountService
+}
+
+// NewAccountHandler cria uma nova instância do AccountHandler
+func NewAccountHandler(accountService *application.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// GetProfile retorna os dados da conta
+func (h *AccountHandler) GetProfile(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.accountService.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateProfile atualiza nome e/ou email
+func (h *AccountHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.accountService.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ChangePassword troca a senha do usuário e revoga as demais sessões
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.accountService.ChangePassword(c.Request.Context(), principal.UserID, principal.SessionID, &req); err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAccount exclui a conta do usuário com seus livros e arquivos
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(c.Request.Context(), userID, &req); err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// accountErrorStatus mapeia os erros dos casos de uso da conta para o status HTTP.
+// Senha incorreta responde 403 para não ser confundida com token expirado (401).
+func accountErrorStatus(err error) int {
+	switch err.Error() {
+	case "senha incorreta":
+		return http.StatusForbidden
+	case "usuário não encontrado":
+		return http.StatusNotFound
+	case "email já está em uso":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"context"
+
+	"cloud-reader/backend/internal/auth/application"
+	"cloud-reader/backend/internal/shared/middleware"
+)
+
+// authenticator adapta o AuthService à interface middleware.Authenticator
+type authenticator struct {
+	authService *application.AuthService
+}
+
+// NewAuthenticator cria o autenticador usado pelo middleware de autenticação
+func NewAuthenticator(authService *application.AuthService) middleware.Authenticator {
+	return &authenticator{
+		authService: authService,
+	}
+}
+
+// Authenticate valida o token e retorna o usuário autenticado
+func (a *authenticator) Authenticate(ctx context.Context, token string) (*middleware.Principal, error) {
+	user, err := a.authService.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &middleware.Principal{
+		UserID:    user.UserID,
+		SessionID: user.SessionID,
+		APIKeyID:  user.APIKeyID,
+		Role:      user.Role,
+		Scopes:    user.Scopes,
+	}, nil
+}
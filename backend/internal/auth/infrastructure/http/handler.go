@@ -1,9 +1,14 @@
 package http
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"cloud-reader/backend/internal/auth/application"
+	"cloud-reader/backend/internal/shared/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -59,9 +64,49 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+
 	resp, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
 		statusCode := http.StatusUnauthorized
+		if err.Error() == "conta desativada" {
+			statusCode = http.StatusForbidden
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+
+// Refresh lida com requisições de renovação de tokens
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req application.RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+
+	resp, err := h.authService.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "refresh token inválido" {
+			statusCode = http.StatusUnauthorized
+		}
 		c.JSON(statusCode, gin.H{
 			"error": err.Error(),
 		})
@@ -71,3 +116,463 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// Logout lida com requisições de logout (revoga a sessão do refresh token)
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req application.LogoutRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "logout realizado com sucesso",
+	})
+}
+
+// ListSessions lista as sessões ativas do usuário autenticado
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.authService.ListSessions(c.Request.Context(), principal.UserID, principal.SessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeSession revoga uma sessão do usuário autenticado
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "sessão não encontrada" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "sessão revogada com sucesso",
+	})
+}
+
+// ForgotPassword lida com requisições de recuperação de senha
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req application.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.ForgotPassword(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "erro ao processar solicitação",
+		})
+		return
+	}
+
+	// Mesma resposta para emails existentes e inexistentes
+	c.JSON(http.StatusOK, gin.H{
+		"message": "se o email estiver cadastrado, você receberá um link para redefinir a senha",
+	})
+}
+
+// ResetPassword lida com requisições de redefinição de senha
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req application.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), &req); err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "token inválido ou expirado" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "senha redefinida com sucesso",
+	})
+}
+
+// VerifyEmail lida com requisições de verificação de email
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req application.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.VerifyEmail(c.Request.Context(), &req); err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "token inválido ou expirado" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email verificado com sucesso",
+	})
+}
+
+// ResendVerificationEmail reenvia o link de verificação para o usuário autenticado
+func (h *AuthHandler) ResendVerificationEmail(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	if err := h.authService.ResendVerificationEmail(c.Request.Context(), userID); err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "email já verificado" {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email de verificação enviado",
+	})
+}
+
+// LoginTwoFactor lida com o segundo passo do login com 2FA
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req application.TwoFactorLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+
+	resp, err := h.authService.LoginTwoFactor(c.Request.Context(), &req)
+	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// TwoFactorStatus retorna o estado do 2FA do usuário autenticado
+func (h *AuthHandler) TwoFactorStatus(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.authService.GetTwoFactorStatus(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// EnrollTwoFactor inicia a configuração do 2FA e retorna a URI otpauth
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.authService.EnrollTwoFactor(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ConfirmTwoFactor ativa o 2FA e retorna os códigos de recuperação
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.authService.ConfirmTwoFactor(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DisableTwoFactor desativa o 2FA do usuário autenticado
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(c.Request.Context(), userID, &req); err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "autenticação em dois fatores desativada",
+	})
+}
+
+// RegenerateRecoveryCodes gera um novo conjunto de códigos de recuperação
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// twoFactorErrorStatus mapeia os erros de 2FA para o status HTTP
+func twoFactorErrorStatus(err error) int {
+	switch err.Error() {
+	case "código inválido", "desafio inválido ou expirado", "senha incorreta":
+		return http.StatusUnauthorized
+	case "autenticação em dois fatores já está ativa":
+		return http.StatusConflict
+	case "conta desativada":
+		return http.StatusForbidden
+	case "autenticação em dois fatores não está ativa", "configuração de dois fatores não iniciada":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondTooManyAttempts responde 429 com Retry-After se o erro for de excesso de tentativas
+func respondTooManyAttempts(c *gin.Context, err error) bool {
+	var tooMany *application.TooManyAttemptsError
+	if !errors.As(err, &tooMany) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(tooMany.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       err.Error(),
+		"retry_after": retryAfter,
+	})
+	return true
+}
+
+// CreateAPIKey cria uma chave de API para o usuário autenticado
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	var req application.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.authService.CreateAPIKey(c.Request.Context(), userID, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "escopo inválido") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListAPIKeys lista as chaves de API do usuário autenticado
+func (h *AuthHandler) ListAPIKeys(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.authService.ListAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeAPIKey revoga uma chave de API do usuário autenticado
+func (h *AuthHandler) RevokeAPIKey(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(c.Request.Context(), userID, uint(id)); err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "chave de API não encontrada" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "chave de API revogada com sucesso",
+	})
+}
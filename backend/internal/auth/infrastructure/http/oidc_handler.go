@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+
+	"cloud-reader/backend/internal/auth/application"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCHandler gerencia os handlers HTTP do login via OpenID Connect
+type OIDCHandler struct {
+	oidcService *application.OIDCService
+}
+
+// NewOIDCHandler cria uma nova instância do OIDCHandler
+func NewOIDCHandler(oidcService *application.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// Config informa ao frontend se o login OIDC está disponível
+func (h *OIDCHandler) Config(c *gin.Context) {
+	c.JSON(http.StatusOK, h.oidcService.GetConfig())
+}
+
+// Login redireciona o navegador para o provedor OIDC
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, err := h.oidcService.StartLogin(c.Request.Context(), c.Query("redirect"))
+	if err != nil {
+		c.Redirect(http.StatusFound, h.oidcService.FailureURL(err.Error()))
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback recebe o retorno do provedor e redireciona para o frontend
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	// O provedor informa falhas (ex.: access_denied) pelo parâmetro error
+	if providerError := c.Query("error"); providerError != "" {
+		c.Redirect(http.StatusFound, h.oidcService.FailureURL(providerError))
+		return
+	}
+
+	redirectURL, err := h.oidcService.Callback(c.Request.Context(), c.Query("code"), c.Query("state"))
+	if err != nil {
+		c.Redirect(http.StatusFound, h.oidcService.FailureURL(err.Error()))
+		return
+	}
+
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// Exchange troca o código de uso único por uma sessão
+func (h *OIDCHandler) Exchange(c *gin.Context) {
+	var req application.OIDCExchangeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+
+	resp, err := h.oidcService.Exchange(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "token inválido ou expirado":
+			status = http.StatusUnauthorized
+		case "conta desativada":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
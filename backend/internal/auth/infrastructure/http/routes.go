@@ -1,15 +1,69 @@
 package http
 
 import (
+	"cloud-reader/backend/internal/shared/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterRoutes registra todas as rotas de autenticação no router
-func RegisterRoutes(router *gin.RouterGroup, handler *AuthHandler) {
+func RegisterRoutes(router *gin.RouterGroup, handler *AuthHandler, authMiddleware gin.HandlerFunc) {
 	auth := router.Group("/auth")
 	{
 		auth.POST("/register", handler.Register)
 		auth.POST("/login", handler.Login)
+		auth.POST("/login/2fa", handler.LoginTwoFactor)
+		auth.POST("/refresh", handler.Refresh)
+		auth.POST("/logout", handler.Logout)
+		auth.POST("/password/forgot", handler.ForgotPassword)
+		auth.POST("/password/reset", handler.ResetPassword)
+		auth.POST("/verify-email", handler.VerifyEmail)
+		auth.POST("/verify-email/resend", authMiddleware, middleware.RequireSession(), handler.ResendVerificationEmail)
+
+		// Rotas de gerenciamento da conta: exigem autenticação por sessão (não aceitam chave de API)
+		twoFactor := auth.Group("/2fa", authMiddleware, middleware.RequireSession())
+		{
+			twoFactor.GET("", handler.TwoFactorStatus)
+			twoFactor.POST("/enroll", handler.EnrollTwoFactor)
+			twoFactor.POST("/confirm", handler.ConfirmTwoFactor)
+			twoFactor.POST("/disable", handler.DisableTwoFactor)
+			twoFactor.POST("/recovery-codes", handler.RegenerateRecoveryCodes)
+		}
+
+		sessions := auth.Group("/sessions", authMiddleware, middleware.RequireSession())
+		{
+			sessions.GET("", handler.ListSessions)
+			sessions.DELETE("/:id", handler.RevokeSession)
+		}
+
+		apiKeys := auth.Group("/api-keys", authMiddleware, middleware.RequireSession())
+		{
+			apiKeys.POST("", handler.CreateAPIKey)
+			apiKeys.GET("", handler.ListAPIKeys)
+			apiKeys.DELETE("/:id", handler.RevokeAPIKey)
+		}
 	}
 }
 
+// RegisterOIDCRoutes registra as rotas do login via OpenID Connect
+func RegisterOIDCRoutes(router *gin.RouterGroup, handler *OIDCHandler) {
+	oidc := router.Group("/auth/oidc")
+	{
+		oidc.GET("", handler.Config)
+		oidc.GET("/login", handler.Login)
+		oidc.GET("/callback", handler.Callback)
+		oidc.POST("/exchange", handler.Exchange)
+	}
+}
+
+// RegisterAccountRoutes registra as rotas da conta do usuário autenticado (/me).
+// Exigem autenticação por sessão (não aceitam chave de API).
+func RegisterAccountRoutes(router *gin.RouterGroup, handler *AccountHandler, authMiddleware gin.HandlerFunc) {
+	me := router.Group("/me", authMiddleware, middleware.RequireSession())
+	{
+		me.GET("", handler.GetProfile)
+		me.PATCH("", handler.UpdateProfile)
+		me.DELETE("", handler.DeleteAccount)
+		me.POST("/password", handler.ChangePassword)
+	}
+}
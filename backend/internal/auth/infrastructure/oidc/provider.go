@@ -0,0 +1,134 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/config"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// oidcProvider implementa OIDCProvider usando go-oidc e oauth2.
+// A descoberta (/.well-known/openid-configuration) é feita no primeiro uso, para
+// que o servidor inicie mesmo se o provedor estiver indisponível.
+type oidcProvider struct {
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+
+	mu       sync.Mutex
+	provider *gooidc.Provider
+	verifier *gooidc.IDTokenVerifier
+}
+
+// idTokenClaims são os claims lidos do ID token
+type idTokenClaims struct {
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // Alguns provedores enviam como string
+	Name          string      `json:"name"`
+}
+
+// NewOIDCProvider cria uma nova instância do provedor OpenID Connect
+func NewOIDCProvider(cfg *config.Config) domain.OIDCProvider {
+	return &oidcProvider{
+		issuerURL:    cfg.OIDCIssuerURL,
+		clientID:     cfg.OIDCClientID,
+		clientSecret: cfg.OIDCClientSecret,
+		redirectURL:  cfg.OIDCRedirectURL,
+		scopes:       cfg.OIDCScopes,
+	}
+}
+
+// AuthCodeURL monta a URL de autorização com state, nonce e o desafio PKCE (S256)
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauthConfig.AuthCodeURL(state,
+		gooidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(codeVerifier),
+	), nil
+}
+
+// Exchange troca o código de autorização pelos tokens e retorna os claims verificados
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCClaims, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao trocar código de autorização: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("resposta do provedor sem id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token inválido: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("nonce do id_token não confere")
+	}
+
+	var claims idTokenClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("erro ao ler claims do id_token: %w", err)
+	}
+
+	return &domain.OIDCClaims{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: parseBoolClaim(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// oauthConfig retorna a configuração OAuth2, fazendo a descoberta do provedor se necessário
+func (p *oidcProvider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider == nil {
+		provider, err := gooidc.NewProvider(ctx, p.issuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("erro na descoberta do provedor OIDC: %w", err)
+		}
+		p.provider = provider
+		p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.clientID})
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.clientID,
+		ClientSecret: p.clientSecret,
+		RedirectURL:  p.redirectURL,
+		Endpoint:     p.provider.Endpoint(),
+		Scopes:       p.scopes,
+	}, nil
+}
+
+// parseBoolClaim interpreta um claim booleano enviado como bool ou string
+func parseBoolClaim(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
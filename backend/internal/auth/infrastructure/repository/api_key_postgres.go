@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"gorm.io/gorm"
+)
+
+// postgresAPIKeyRepository implementa APIKeyRepository usando PostgreSQL/GORM
+type postgresAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresAPIKeyRepository cria uma nova instância do repositório de chaves de API
+func NewPostgresAPIKeyRepository(db *gorm.DB) domain.APIKeyRepository {
+	return &postgresAPIKeyRepository{
+		db: db,
+	}
+}
+
+// Create cria uma nova chave
+func (r *postgresAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// FindByUserID busca as chaves não revogadas de um usuário
+func (r *postgresAPIKeyRepository) FindByUserID(ctx context.Context, userID uint) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// FindByHash busca uma chave pelo hash
+func (r *postgresAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("chave de API não encontrada")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Revoke revoga uma chave (valida ownership)
+func (r *postgresAPIKeyRepository) Revoke(ctx context.Context, id uint, userID uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("chave de API não encontrada")
+	}
+	return nil
+}
+
+// TouchLastUsed atualiza a data de último uso, no máximo uma vez por minInterval
+func (r *postgresAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, now time.Time, minInterval time.Duration) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-minInterval)).
+		UpdateColumn("last_used_at", now).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresLoginAttemptTracker implementa LoginAttemptTracker usando PostgreSQL/GORM,
+// compartilhando o histórico entre várias instâncias do servidor
+type postgresLoginAttemptTracker struct {
+	db *gorm.DB
+}
+
+// NewPostgresLoginAttemptTracker cria uma nova instância do registro de tentativas no PostgreSQL
+func NewPostgresLoginAttemptTracker(db *gorm.DB) domain.LoginAttemptTracker {
+	return &postgresLoginAttemptTracker{
+		db: db,
+	}
+}
+
+// Reserve verifica a espera e conta a tentativa numa transação com a linha
+// da chave travada (SELECT ... FOR UPDATE)
+func (r *postgresLoginAttemptTracker) Reserve(ctx context.Context, key string, now time.Time, policy domain.LoginThrottlePolicy) (*domain.LoginReservation, time.Duration, error) {
+	// O PostgreSQL guarda microssegundos; Release compara o horário gravado
+	now = now.Truncate(time.Microsecond)
+
+	var reservation *domain.LoginReservation
+	var wait time.Duration
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Garante a linha para que requisições simultâneas esperem pelo lock
+		// (uma linha sem falhas não conta para o backoff)
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.LoginAttempt{Key: key, LastFailureAt: now}).Error; err != nil {
+			return err
+		}
+
+		var current domain.LoginAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).First(&current).Error; err != nil {
+			return err
+		}
+
+		reservation, wait = policy.Reserve(key, &current, now)
+		if reservation == nil {
+			return nil
+		}
+		return tx.Model(&domain.LoginAttempt{}).Where("key = ?", key).Updates(map[string]interface{}{
+			"failures":        reservation.Reserved.Failures,
+			"last_failure_at": reservation.Reserved.LastFailureAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return reservation, wait, nil
+}
+
+// Release restaura o histórico anterior à reserva. A condição sobre a linha
+// reservada faz a troca atômica: se outra tentativa a alterou, nada muda.
+func (r *postgresLoginAttemptTracker) Release(ctx context.Context, reservation *domain.LoginReservation) error {
+	query := r.db.WithContext(ctx).Model(&domain.LoginAttempt{}).Where(
+		"key = ? AND failures = ? AND last_failure_at = ?",
+		reservation.Key, reservation.Reserved.Failures, reservation.Reserved.LastFailureAt,
+	)
+	if reservation.Previous == nil {
+		return query.Delete(&domain.LoginAttempt{}).Error
+	}
+	return query.Updates(map[string]interface{}{
+		"failures":        reservation.Previous.Failures,
+		"last_failure_at": reservation.Previous.LastFailureAt,
+	}).Error
+}
+
+// Reset remove o histórico da chave
+func (r *postgresLoginAttemptTracker) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginAttempt{}).Error
+}
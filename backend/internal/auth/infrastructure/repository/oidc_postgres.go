@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresUserIdentityRepository implementa UserIdentityRepository usando PostgreSQL/GORM
+type postgresUserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUserIdentityRepository cria uma nova instância do repositório de identidades
+func NewPostgresUserIdentityRepository(db *gorm.DB) domain.UserIdentityRepository {
+	return &postgresUserIdentityRepository{
+		db: db,
+	}
+}
+
+// Create vincula uma identidade a um usuário
+func (r *postgresUserIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// FindByIssuerSubject busca uma identidade pelo provedor e subject
+func (r *postgresUserIdentityRepository) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	if err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("identidade não encontrada")
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// postgresOIDCStateRepository implementa OIDCStateRepository usando PostgreSQL/GORM
+type postgresOIDCStateRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresOIDCStateRepository cria uma nova instância do repositório de logins OIDC
+func NewPostgresOIDCStateRepository(db *gorm.DB) domain.OIDCStateRepository {
+	return &postgresOIDCStateRepository{
+		db: db,
+	}
+}
+
+// Create registra um novo login em andamento
+func (r *postgresOIDCStateRepository) Create(ctx context.Context, state *domain.OIDCLoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+// Consume busca e remove o state em uma única operação (uso único)
+func (r *postgresOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	var states []domain.OIDCLoginState
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, errors.New("state não encontrado")
+	}
+	return &states[0], nil
+}
+
+// DeleteExpired remove os states expirados
+func (r *postgresOIDCStateRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&domain.OIDCLoginState{}).Error
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"cloud-reader/backend/internal/auth/domain"
 	"gorm.io/gorm"
@@ -61,3 +63,184 @@ func (r *postgresUserRepository) ExistsByEmail(ctx context.Context, email string
 	return count > 0, nil
 }
 
+
+// UpdatePassword atualiza o hash da senha do usuário
+func (r *postgresUserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("password", hashedPassword)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// MarkEmailVerified marca o email do usuário como verificado
+func (r *postgresUserRepository) MarkEmailVerified(ctx context.Context, id uint, verifiedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("email_verified_at", verifiedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// UpdateTwoFactor atualiza o segredo TOTP e o estado da autenticação em dois fatores
+func (r *postgresUserRepository) UpdateTwoFactor(ctx context.Context, id uint, secret string, enabledAt *time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"two_factor_secret":     secret,
+			"two_factor_enabled_at": enabledAt,
+			"two_factor_last_step":  0,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// AdvanceTwoFactorStep registra a janela TOTP usada, rejeitando janelas já usadas
+func (r *postgresUserRepository) AdvanceTwoFactorStep(ctx context.Context, id uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND two_factor_last_step < ?", id, step).
+		Update("two_factor_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// List lista os usuários com paginação, retornando também o total
+func (r *postgresUserRepository) List(ctx context.Context, filter domain.UserListFilter) ([]*domain.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.User{})
+	if filter.Query != "" {
+		pattern := "%" + filter.Query + "%"
+		query = query.Where("name ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*domain.User
+	if err := query.Order("id ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// UpdateRole altera o papel do usuário
+func (r *postgresUserRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// SetDisabled desativa (disabledAt != nil) ou reativa a conta
+func (r *postgresUserRepository) SetDisabled(ctx context.Context, id uint, disabledAt *time.Time) error {
+	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("disabled_at", disabledAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// Delete remove definitivamente o usuário e os dados de autenticação vinculados
+func (r *postgresUserRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sessionIDs := tx.Model(&domain.Session{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Where("session_id IN (?)", sessionIDs).Delete(&domain.RefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&domain.Session{},
+			&domain.UserToken{},
+			&domain.RecoveryCode{},
+			&domain.APIKey{},
+			&domain.UserIdentity{},
+		} {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Delete(&domain.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("usuário não encontrado")
+		}
+		return nil
+	})
+}
+
+// UpdateProfile atualiza nome, email e o estado de verificação do email
+func (r *postgresUserRepository) UpdateProfile(ctx context.Context, user *domain.User) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]interface{}{
+			"name":              user.Name,
+			"email":             user.Email,
+			"email_verified_at": user.EmailVerifiedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// SoftDelete marca o usuário como removido e apaga os dados de autenticação vinculados
+func (r *postgresUserRepository) SoftDelete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{
+			&domain.UserToken{},
+			&domain.RecoveryCode{},
+			&domain.APIKey{},
+			&domain.UserIdentity{},
+		} {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// O email tem índice único: substitui por um valor reservado para liberá-lo
+		now := time.Now()
+		result := tx.Model(&domain.User{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"email":      fmt.Sprintf("deleted-%d-%d@deleted.invalid", id, now.Unix()),
+				"deleted_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("usuário não encontrado")
+		}
+		return nil
+	})
+}
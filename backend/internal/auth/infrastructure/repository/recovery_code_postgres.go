@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"gorm.io/gorm"
+)
+
+// postgresRecoveryCodeRepository implementa RecoveryCodeRepository usando PostgreSQL/GORM
+type postgresRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRecoveryCodeRepository cria uma nova instância do repositório de códigos de recuperação
+func NewPostgresRecoveryCodeRepository(db *gorm.DB) domain.RecoveryCodeRepository {
+	return &postgresRecoveryCodeRepository{
+		db: db,
+	}
+}
+
+// ReplaceForUser substitui todos os códigos de recuperação do usuário
+func (r *postgresRecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uint, codes []*domain.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Consume marca como usado o código com o hash informado
+func (r *postgresRecoveryCodeRepository) Consume(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnused conta os códigos ainda não usados do usuário
+func (r *postgresRecoveryCodeRepository) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteByUserID remove todos os códigos do usuário
+func (r *postgresRecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
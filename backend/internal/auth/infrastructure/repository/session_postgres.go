@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"gorm.io/gorm"
+)
+
+// postgresSessionRepository implementa SessionRepository usando PostgreSQL/GORM
+type postgresSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresSessionRepository cria uma nova instância do repositório de sessões
+func NewPostgresSessionRepository(db *gorm.DB) domain.SessionRepository {
+	return &postgresSessionRepository{
+		db: db,
+	}
+}
+
+// Create cria uma nova sessão com seu primeiro refresh token
+func (r *postgresSessionRepository) Create(ctx context.Context, session *domain.Session, token *domain.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+// FindByID busca uma sessão pelo ID
+func (r *postgresSessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sessão não encontrada")
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID busca as sessões ativas de um usuário
+func (r *postgresSessionRepository) FindActiveByUserID(ctx context.Context, userID uint) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// FindRefreshTokenByHash busca um refresh token pelo hash
+func (r *postgresSessionRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token não encontrado")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marca o token atual como usado e cria o próximo token da sessão
+func (r *postgresSessionRepository) Rotate(ctx context.Context, currentTokenID uint, next *domain.RefreshToken, lastUsedAt time.Time) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A condição used_at IS NULL garante que apenas uma requisição concorrente vença
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", currentTokenID).
+			Update("used_at", lastUsedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.Session{}).
+			Where("id = ?", next.SessionID).
+			Updates(map[string]interface{}{
+				"last_used_at": lastUsedAt,
+				"expires_at":   next.ExpiresAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return rotated, nil
+}
+
+// Revoke revoga uma sessão
+func (r *postgresSessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":    time.Now(),
+			"revoke_reason": reason,
+		}).Error
+}
+
+// RevokeAllByUserID revoga todas as sessões ativas de um usuário, exceto exceptID
+func (r *postgresSessionRepository) RevokeAllByUserID(ctx context.Context, userID uint, exceptID string, reason string) error {
+	query := r.db.WithContext(ctx).
+		Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	return query.Updates(map[string]interface{}{
+		"revoked_at":    time.Now(),
+		"revoke_reason": reason,
+	}).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"gorm.io/gorm"
+)
+
+// postgresUserTokenRepository implementa UserTokenRepository usando PostgreSQL/GORM
+type postgresUserTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUserTokenRepository cria uma nova instância do repositório de tokens de uso único
+func NewPostgresUserTokenRepository(db *gorm.DB) domain.UserTokenRepository {
+	return &postgresUserTokenRepository{
+		db: db,
+	}
+}
+
+// Create cria um novo token
+func (r *postgresUserTokenRepository) Create(ctx context.Context, token *domain.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByHash busca um token pelo propósito e hash
+func (r *postgresUserTokenRepository) FindByHash(ctx context.Context, purpose string, tokenHash string) (*domain.UserToken, error) {
+	var token domain.UserToken
+	if err := r.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("token não encontrado")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed marca o token como usado. Retorna false se ele já tinha sido usado.
+func (r *postgresUserTokenRepository) MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateByUserID invalida os tokens pendentes de um usuário para o propósito
+func (r *postgresUserTokenRepository) InvalidateByUserID(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
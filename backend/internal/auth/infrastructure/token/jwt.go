@@ -0,0 +1,222 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/shared/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// minSecretLength é o tamanho mínimo do JWT_SECRET, em qualquer ambiente:
+// um ENVIRONMENT ausente cai em development e não pode enfraquecer o segredo
+const minSecretLength = 32
+
+// accessClaims são os claims do token de acesso
+type accessClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
+// jwtTokenService implementa TokenService usando JWT
+type jwtTokenService struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+	ttl       time.Duration
+}
+
+// NewJWTTokenService cria uma nova instância do serviço de tokens JWT
+func NewJWTTokenService(cfg *config.Config) (domain.TokenService, error) {
+	s := &jwtTokenService{
+		issuer: cfg.JWTIssuer,
+		ttl:    cfg.AccessTokenTTL,
+	}
+
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		secret := cfg.JWTSecret
+		if secret == "" {
+			return nil, errors.New("JWT_SECRET é obrigatório para o algoritmo HS256 (gere com: openssl rand -hex 32)")
+		}
+		if len(secret) < minSecretLength {
+			return nil, fmt.Errorf("JWT_SECRET deve ter pelo menos %d caracteres", minSecretLength)
+		}
+		s.method = jwt.SigningMethodHS256
+		s.signKey = []byte(secret)
+		s.verifyKey = []byte(secret)
+
+	case "RS256":
+		privateKey, publicKey, err := loadKeyPair(cfg.JWTPrivateKeyPath, cfg.JWTPublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		rsaPrivate, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("chave privada não é RSA")
+		}
+		if publicKey == nil {
+			publicKey = &rsaPrivate.PublicKey
+		}
+		if _, ok := publicKey.(*rsa.PublicKey); !ok {
+			return nil, errors.New("chave pública não é RSA")
+		}
+		s.method = jwt.SigningMethodRS256
+		s.signKey = rsaPrivate
+		s.verifyKey = publicKey
+
+	case "EdDSA":
+		privateKey, publicKey, err := loadKeyPair(cfg.JWTPrivateKeyPath, cfg.JWTPublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		edPrivate, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("chave privada não é Ed25519")
+		}
+		if publicKey == nil {
+			publicKey = edPrivate.Public()
+		}
+		if _, ok := publicKey.(ed25519.PublicKey); !ok {
+			return nil, errors.New("chave pública não é Ed25519")
+		}
+		s.method = jwt.SigningMethodEdDSA
+		s.signKey = edPrivate
+		s.verifyKey = publicKey
+
+	default:
+		return nil, fmt.Errorf("algoritmo JWT não suportado: %s", cfg.JWTAlgorithm)
+	}
+
+	return s, nil
+}
+
+// GenerateAccessToken gera um token de acesso assinado para o usuário e a sessão
+func (s *jwtTokenService) GenerateAccessToken(user *domain.User, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Issuer:    s.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		SessionID: sessionID,
+	}
+
+	signed, err := jwt.NewWithClaims(s.method, claims).SignedString(s.signKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("erro ao assinar token: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// ValidateAccessToken valida a assinatura e a validade do token e retorna seus claims
+func (s *jwtTokenService) ValidateAccessToken(tokenString string) (*domain.AccessTokenClaims, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		return s.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, errors.New("token inválido")
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || userID == 0 {
+		return nil, errors.New("token inválido")
+	}
+
+	result := &domain.AccessTokenClaims{
+		UserID:    uint(userID),
+		SessionID: claims.SessionID,
+		TokenID:   claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	return result, nil
+}
+
+// loadKeyPair carrega a chave privada (obrigatória) e a chave pública (opcional) em formato PEM
+func loadKeyPair(privatePath, publicPath string) (crypto.PrivateKey, crypto.PublicKey, error) {
+	if privatePath == "" {
+		return nil, nil, errors.New("JWT_PRIVATE_KEY_PATH é obrigatório para algoritmos assimétricos")
+	}
+
+	block, err := readPEM(privatePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var privateKey crypto.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler chave privada: %w", err)
+	}
+
+	if publicPath == "" {
+		return privateKey, nil, nil
+	}
+
+	block, err = readPEM(publicPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var publicKey crypto.PublicKey
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler chave pública: %w", err)
+	}
+
+	return privateKey, publicKey, nil
+}
+
+// readPEM lê o primeiro bloco PEM do arquivo
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de chave %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("arquivo de chave %s não contém PEM válido", path)
+	}
+	return block, nil
+}
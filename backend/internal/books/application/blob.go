@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/storage"
+)
+
+// storeBlob registra uma referência ao conteúdo do upload e o grava no
+// armazenamento apenas se ainda não existir uma cópia com o mesmo hash
+func (s *BookService) storeBlob(ctx context.Context, spool *storage.SpooledFile, contentType string) (*domain.Blob, error) {
+	blob, err := s.blobRepo.Acquire(ctx, &domain.Blob{
+		Hash:       spool.Hash,
+		StorageKey: storage.BlobKey(spool.Hash),
+		Size:       spool.Size,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar arquivo: %w", err)
+	}
+
+	// O conteúdo também é gravado quando o registro existe mas o objeto não
+	// (ex.: upload anterior interrompido entre o registro e a gravação)
+	if err := s.putBlobContent(ctx, blob, spool, contentType); err != nil {
+		s.releaseBlob(ctx, blob.Hash)
+		return nil, fmt.Errorf("erro ao salvar arquivo: %w", err)
+	}
+
+	return blob, nil
+}
+
+// putBlobContent grava o conteúdo do blob se o objeto ainda não existir
+func (s *BookService) putBlobContent(ctx context.Context, blob *domain.Blob, spool *storage.SpooledFile, contentType string) error {
+	_, err := s.store.Stat(ctx, blob.StorageKey)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	content, err := spool.Reader()
+	if err != nil {
+		return err
+	}
+	return s.store.Put(ctx, blob.StorageKey, content, spool.Size, contentType)
+}
+
+// releaseBlob remove uma referência ao conteúdo; o arquivo e as capas só são
+// apagados do armazenamento quando o último livro que o referencia é removido
+func (s *BookService) releaseBlob(ctx context.Context, hash string) error {
+	return s.blobRepo.Release(ctx, hash, func(blob *domain.Blob) error {
+		if err := s.deleteCovers(ctx, blob.Hash); err != nil {
+			return err
+		}
+		return s.store.Delete(ctx, blob.StorageKey)
+	})
+}
+
+// deleteBookContent libera o arquivo de um livro removido. Livros anteriores
+// à deduplicação não têm hash e apontam diretamente para o próprio arquivo.
+func (s *BookService) deleteBookContent(ctx context.Context, book *domain.Book) error {
+	if book.ContentHash != "" {
+		return s.releaseBlob(ctx, book.ContentHash)
+	}
+	return s.store.Delete(ctx, storage.KeyFromPath(book.FilePath))
+}
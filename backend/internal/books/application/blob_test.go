@@ -0,0 +1,110 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cloud-reader/backend/internal/shared/storage"
+)
+
+// orgContent gera um arquivo Org com exatamente size bytes
+func orgContent(title string, size int) []byte {
+	content := fmt.Sprintf("#+TITLE: %s\n", title)
+	return []byte(content + strings.Repeat("x", size-len(content)))
+}
+
+// upload cria um livro Org com o conteúdo informado
+func (tb *testBooks) upload(t *testing.T, userID uint, content []byte, allowDuplicate bool) (*BookResponse, error) {
+	t.Helper()
+	return tb.service.createBook(context.Background(), userID, "livro.org", bytes.NewReader(content), allowDuplicate)
+}
+
+// mustUpload cria um livro e falha o teste em caso de erro
+func (tb *testBooks) mustUpload(t *testing.T, userID uint, content []byte, allowDuplicate bool) *BookResponse {
+	t.Helper()
+	book, err := tb.upload(t, userID, content, allowDuplicate)
+	if err != nil {
+		t.Fatalf("upload do usuário %d: %v", userID, err)
+	}
+	return book
+}
+
+// stored informa se o objeto existe no armazenamento
+func (tb *testBooks) stored(t *testing.T, key string) bool {
+	t.Helper()
+	_, err := tb.store.Stat(context.Background(), key)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestBlobRefCount(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	content := orgContent("Compartilhado", 64)
+
+	first := tb.mustUpload(t, 1, content, false)
+	second := tb.mustUpload(t, 2, content, false)
+	extra := tb.mustUpload(t, 1, content, true)
+
+	// Uma só cópia no armazenamento, referenciada pelos três livros
+	if first.FilePath != second.FilePath || first.FilePath != extra.FilePath {
+		t.Errorf("caminhos diferentes para o mesmo conteúdo: %q, %q, %q", first.FilePath, second.FilePath, extra.FilePath)
+	}
+	if got := tb.blobs.refCount(first.ContentHash); got != 3 {
+		t.Fatalf("referências = %d, esperado 3", got)
+	}
+
+	// Sem allow_duplicate, o mesmo conteúdo é recusado sem nova referência
+	var duplicate *DuplicateBookError
+	if _, err := tb.upload(t, 1, content, false); !errors.As(err, &duplicate) || duplicate.ExistingBookID != first.ID {
+		t.Errorf("upload repetido = %v, esperado DuplicateBookError do livro %d", err, first.ID)
+	}
+	if got := tb.blobs.refCount(first.ContentHash); got != 3 {
+		t.Errorf("referências após o upload recusado = %d, esperado 3", got)
+	}
+
+	// O conteúdo só é apagado com o último livro
+	for i, book := range []*BookResponse{first, extra, second} {
+		if !tb.stored(t, first.FilePath) {
+			t.Fatalf("conteúdo apagado com %d livros restantes", 3-i)
+		}
+		if err := tb.service.DeleteBook(ctx, book.ID, book.UserID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tb.stored(t, first.FilePath) {
+		t.Error("conteúdo mantido sem livros")
+	}
+	if got := tb.blobs.refCount(first.ContentHash); got != 0 {
+		t.Errorf("referências após remover todos os livros = %d", got)
+	}
+}
+
+func TestDeleteUserBooksKeepsSharedBlobs(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	shared := orgContent("Compartilhado", 64)
+	own := orgContent("Só do usuário 1", 64)
+
+	sharedBook := tb.mustUpload(t, 1, shared, false)
+	ownBook := tb.mustUpload(t, 1, own, false)
+	tb.mustUpload(t, 2, shared, false)
+
+	if err := tb.service.DeleteUserBooks(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !tb.stored(t, sharedBook.FilePath) || tb.blobs.refCount(sharedBook.ContentHash) != 1 {
+		t.Errorf("conteúdo compartilhado: armazenado %v, referências %d; esperado mantido com 1",
+			tb.stored(t, sharedBook.FilePath), tb.blobs.refCount(sharedBook.ContentHash))
+	}
+	if tb.stored(t, ownBook.FilePath) || tb.blobs.refCount(ownBook.ContentHash) != 0 {
+		t.Error("conteúdo exclusivo do usuário removido mantido")
+	}
+}
@@ -0,0 +1,249 @@
+package application
+
+import (
+	"context"
+	"encoding/hex"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/internal/shared/storage"
+)
+
+// storagePrefixes são as áreas do armazenamento com arquivos de livros
+// (blobs endereçados por hash, o layout antigo por usuário e as capas)
+var storagePrefixes = []string{"blobs/", "books/", "covers/"}
+
+// StorageCheckService reconcilia o armazenamento com as tabelas de livros e blobs:
+// encontra arquivos sem livro (ex.: queda entre gravar o arquivo e criar o
+// registro) e livros sem arquivo, e opcionalmente remove os arquivos órfãos.
+type StorageCheckService struct {
+	bookRepo domain.BookRepository
+	blobRepo domain.BlobRepository
+	store    storage.Storage
+
+	interval      time.Duration
+	grace         time.Duration
+	deleteOrphans bool
+}
+
+// NewStorageCheckService cria uma nova instância do StorageCheckService
+func NewStorageCheckService(bookRepo domain.BookRepository, blobRepo domain.BlobRepository, store storage.Storage, cfg *config.Config) *StorageCheckService {
+	return &StorageCheckService{
+		bookRepo:      bookRepo,
+		blobRepo:      blobRepo,
+		store:         store,
+		interval:      cfg.StorageCheckInterval,
+		grace:         cfg.StorageOrphanGrace,
+		deleteOrphans: cfg.StorageDeleteOrphans,
+	}
+}
+
+// Start inicia a verificação periódica, se houver intervalo configurado
+func (s *StorageCheckService) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			report, err := s.Check(ctx, s.deleteOrphans)
+			if err != nil {
+				log.Printf("Erro ao verificar armazenamento: %v", err)
+				continue
+			}
+			logStorageReport(report)
+		}
+	}()
+}
+
+// Check compara o armazenamento com os registros. Com deleteOrphans, remove os
+// arquivos órfãos mais antigos que o período de carência e os blobs sem livros.
+func (s *StorageCheckService) Check(ctx context.Context, deleteOrphans bool) (*StorageReport, error) {
+	report := &StorageReport{
+		CheckedAt:          time.Now(),
+		OrphanFiles:        []OrphanFile{},
+		MissingFiles:       []MissingFile{},
+		RefCountMismatches: []RefCountMismatch{},
+	}
+
+	// Os registros são lidos antes da listagem: um arquivo gravado depois aparece
+	// como órfão, mas é recente e fica protegido pelo período de carência
+	books, err := s.bookRepo.FindAllFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := s.blobRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []storage.ObjectInfo
+	for _, prefix := range storagePrefixes {
+		listed, err := s.store.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, listed...)
+	}
+
+	exists := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		exists[object.Key] = true
+	}
+
+	referenced := make(map[string]bool, len(books))
+	booksByHash := make(map[string]int)
+	for _, book := range books {
+		key := storage.KeyFromPath(book.FilePath)
+		referenced[key] = true
+		if book.ContentHash != "" {
+			booksByHash[book.ContentHash]++
+		}
+		if !exists[key] {
+			report.MissingFiles = append(report.MissingFiles, MissingFile{
+				BookID: book.ID,
+				UserID: book.UserID,
+				Key:    key,
+			})
+		}
+	}
+
+	for _, blob := range blobs {
+		if count := booksByHash[blob.Hash]; count != blob.RefCount {
+			report.RefCountMismatches = append(report.RefCountMismatches, RefCountMismatch{
+				Hash:     blob.Hash,
+				RefCount: blob.RefCount,
+				Books:    count,
+			})
+		}
+	}
+
+	for _, object := range objects {
+		// As capas pertencem ao conteúdo: valem enquanto houver livro com o hash
+		if hash, ok := coverHashFromKey(object.Key); ok && booksByHash[hash] > 0 {
+			continue
+		}
+		if !referenced[object.Key] {
+			report.OrphanFiles = append(report.OrphanFiles, OrphanFile{
+				Key:     object.Key,
+				Size:    object.Size,
+				ModTime: object.ModTime,
+			})
+		}
+	}
+
+	report.Objects = len(objects)
+	report.Books = len(books)
+
+	if deleteOrphans {
+		if err := s.removeOrphans(ctx, report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// removeOrphans remove os arquivos órfãos antigos e os registros de blobs sem
+// livros. Blobs são removidos com o registro bloqueado, para não apagar um
+// conteúdo que um upload simultâneo acabou de passar a referenciar.
+func (s *StorageCheckService) removeOrphans(ctx context.Context, report *StorageReport) error {
+	cutoff := report.CheckedAt.Add(-s.grace)
+	removed := make(map[string]bool)
+
+	for i := range report.OrphanFiles {
+		orphan := &report.OrphanFiles[i]
+		if orphan.ModTime.After(cutoff) {
+			continue
+		}
+
+		deleted := true
+		if hash, ok := blobHashFromKey(orphan.Key); ok {
+			var err error
+			deleted, err = s.blobRepo.DeleteUnreferenced(ctx, hash, orphan.Key, cutoff, func(blob *domain.Blob) error {
+				return s.store.Delete(ctx, blob.StorageKey)
+			})
+			if err != nil {
+				return err
+			}
+			removed[hash] = deleted
+		} else if err := s.store.Delete(ctx, orphan.Key); err != nil {
+			return err
+		}
+
+		if deleted {
+			orphan.Deleted = true
+			report.DeletedFiles++
+			report.DeletedBytes += orphan.Size
+		}
+	}
+
+	// Registros sem livros cujo arquivo já não existe (não aparecem como órfãos)
+	for i := range report.RefCountMismatches {
+		mismatch := &report.RefCountMismatches[i]
+		if mismatch.Books > 0 || removed[mismatch.Hash] {
+			mismatch.Deleted = removed[mismatch.Hash]
+			continue
+		}
+
+		deleted, err := s.blobRepo.DeleteUnreferenced(ctx, mismatch.Hash, storage.BlobKey(mismatch.Hash), cutoff, func(blob *domain.Blob) error {
+			return s.store.Delete(ctx, blob.StorageKey)
+		})
+		if err != nil {
+			return err
+		}
+		mismatch.Deleted = deleted
+	}
+
+	return nil
+}
+
+// blobHashFromKey extrai o hash de uma chave no formato de BlobKey
+func blobHashFromKey(key string) (string, bool) {
+	hash := path.Base(key)
+	if _, err := hex.DecodeString(hash); err != nil || len(hash) != 64 || storage.BlobKey(hash) != key {
+		return "", false
+	}
+	return hash, true
+}
+
+// coverHashFromKey extrai o hash de uma chave no formato de CoverKey
+func coverHashFromKey(key string) (string, bool) {
+	hash := path.Base(path.Dir(key))
+	if _, err := hex.DecodeString(hash); err != nil || len(hash) != 64 || !strings.HasPrefix(key, storage.CoverPrefix(hash)) {
+		return "", false
+	}
+	return hash, true
+}
+
+// logStorageReport registra o resumo da verificação e cada divergência encontrada
+func logStorageReport(report *StorageReport) {
+	for _, missing := range report.MissingFiles {
+		log.Printf("Armazenamento: arquivo do livro %d (usuário %d) não encontrado: %s", missing.BookID, missing.UserID, missing.Key)
+	}
+	for _, mismatch := range report.RefCountMismatches {
+		log.Printf("Armazenamento: blob %s com %d referências e %d livros", mismatch.Hash, mismatch.RefCount, mismatch.Books)
+	}
+	for _, orphan := range report.OrphanFiles {
+		if !orphan.Deleted {
+			log.Printf("Armazenamento: arquivo órfão %s (%d bytes)", orphan.Key, orphan.Size)
+		}
+	}
+
+	log.Printf("Verificação do armazenamento: %d arquivos, %d livros, %d órfãos (%d removidos, %d bytes), %d ausentes, %d contagens divergentes",
+		report.Objects, report.Books, len(report.OrphanFiles), report.DeletedFiles, report.DeletedBytes,
+		len(report.MissingFiles), len(report.RefCountMismatches))
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/storage"
+	"cloud-reader/backend/pkg/epub"
+	"cloud-reader/backend/pkg/pdf"
+	"cloud-reader/backend/pkg/thumbnail"
+)
+
+const (
+	// DefaultCoverSize é o tamanho servido quando ?size= não é informado
+	DefaultCoverSize = "medium"
+
+	// maxCoverPixels limita a imagem de capa decodificada (~40 megapixels)
+	maxCoverPixels = 40 << 20
+
+	// maxCoverFileSize limita a leitura da imagem de capa do EPUB
+	maxCoverFileSize = 20 << 20
+)
+
+// coverSizes são os tamanhos das miniaturas: largura máxima, com altura de até
+// 1,5x a largura. O último é gravado por último e indica que todos existem.
+var coverSizes = []struct {
+	name  string
+	width int
+}{
+	{"small", 160},
+	{"medium", 320},
+	{"large", 640},
+}
+
+// errNoCover indica um arquivo sem imagem de capa (não é uma falha)
+var errNoCover = errors.New("arquivo sem capa")
+
+// generateCovers gera e grava as miniaturas da capa do conteúdo. Capas já
+// geradas para o mesmo hash são reaproveitadas. Falhas não impedem o upload:
+// o livro só fica sem capa.
+func (s *BookService) generateCovers(ctx context.Context, spool *storage.SpooledFile, format string) bool {
+	last := coverSizes[len(coverSizes)-1].name
+	if _, err := s.store.Stat(ctx, storage.CoverKey(spool.Hash, last)); err == nil {
+		return true
+	}
+
+	img, err := safeCoverImage(spool, format)
+	if err != nil {
+		if !errors.Is(err, errNoCover) {
+			log.Printf("Erro ao extrair capa do livro: %v", err)
+		}
+		return false
+	}
+
+	// Cada tamanho é reduzido a partir do seguinte, maior: só a primeira
+	// redução percorre (e copia) a imagem original
+	thumbs := make([]image.Image, len(coverSizes))
+	for i := len(coverSizes) - 1; i >= 0; i-- {
+		img = thumbnail.Fit(img, coverSizes[i].width, coverSizes[i].width*3/2)
+		thumbs[i] = img
+	}
+
+	for i, size := range coverSizes {
+		var buf bytes.Buffer
+		if err := thumbnail.Encode(&buf, thumbs[i]); err != nil {
+			log.Printf("Erro ao gerar miniatura da capa: %v", err)
+			return false
+		}
+		key := storage.CoverKey(spool.Hash, size.name)
+		if err := s.store.Put(ctx, key, &buf, int64(buf.Len()), "image/jpeg"); err != nil {
+			log.Printf("Erro ao salvar miniatura da capa %s: %v", key, err)
+			return false
+		}
+	}
+	return true
+}
+
+// safeCoverImage chama coverImage convertendo um pânico dos decodificadores
+// (arquivos forjados) em erro, para que o upload continue sem capa
+func safeCoverImage(spool *storage.SpooledFile, format string) (img image.Image, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			img, err = nil, fmt.Errorf("pânico ao extrair capa: %v", recovered)
+		}
+	}()
+	return coverImage(spool, format)
+}
+
+// coverImage extrai a imagem de capa: a do manifest no EPUB e a maior imagem
+// da primeira página no PDF
+func coverImage(spool *storage.SpooledFile, format string) (image.Image, error) {
+	switch format {
+	case "epub":
+		pkg, err := epub.Open(spool.ReaderAt(), spool.Size)
+		if err != nil {
+			return nil, err
+		}
+		item := pkg.Cover()
+		if item == nil {
+			return nil, errNoCover
+		}
+
+		file, err := pkg.OpenItem(item)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, maxCoverFileSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxCoverFileSize {
+			return nil, fmt.Errorf("capa %s grande demais", item.Path)
+		}
+		return thumbnail.Decode(bytes.NewReader(data), maxCoverPixels)
+	case "pdf":
+		img, err := pdf.FirstPageImage(spool.ReaderAt(), spool.Size)
+		if errors.Is(err, pdf.ErrNoImage) || errors.Is(err, pdf.ErrEncrypted) {
+			return nil, errNoCover
+		}
+		return img, err
+	default:
+		return nil, errNoCover
+	}
+}
+
+// deleteCovers remove as miniaturas de capa de um conteúdo
+func (s *BookService) deleteCovers(ctx context.Context, hash string) error {
+	return storage.DeletePrefix(ctx, s.store, storage.CoverPrefix(hash))
+}
+
+// GetCover abre a miniatura de capa de um livro. O chamador deve fechar o Content.
+func (s *BookService) GetCover(ctx context.Context, id uint, userID uint, size string) (*CoverFile, error) {
+	if size == "" {
+		size = DefaultCoverSize
+	}
+	valid := false
+	for _, coverSize := range coverSizes {
+		valid = valid || coverSize.name == size
+	}
+	if !valid {
+		return nil, errors.New("tamanho de capa inválido")
+	}
+
+	book, err := s.bookRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, domain.ErrBookNotFound
+	}
+	if !book.HasCover || book.ContentHash == "" {
+		return nil, errors.New("capa não encontrada")
+	}
+
+	key := storage.CoverKey(book.ContentHash, size)
+	info, err := s.store.Stat(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Erro ao consultar capa %s: %v", key, err)
+		}
+		return nil, errors.New("capa não encontrada")
+	}
+	content, err := s.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Erro ao abrir capa %s: %v", key, err)
+		}
+		return nil, errors.New("capa não encontrada")
+	}
+
+	return &CoverFile{
+		Content: content,
+		ETag:    book.ContentHash + "-" + size,
+		ModTime: info.ModTime,
+	}, nil
+}
@@ -1,5 +1,13 @@
 package application
 
+import (
+	"io"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/pkg/org"
+)
+
 // BookResponse representa a resposta com dados do livro
 type BookResponse struct {
 	ID                uint    `json:"id"`
@@ -7,23 +15,173 @@ type BookResponse struct {
 	Title             string  `json:"title"`
 	Filename          string  `json:"filename"`
 	FilePath          string  `json:"file_path"`
+	ContentHash       string  `json:"content_hash,omitempty"`
 	FileSize          int64   `json:"file_size"`
 	Format            string  `json:"format"`
+	MimeType          string  `json:"mime_type,omitempty"`
+	Metadata          *BookMetadataResponse `json:"metadata,omitempty"`
+	PageCount         int     `json:"page_count"`
+	HasCover          bool    `json:"has_cover"`
 	CurrentPage       int     `json:"current_page"`
 	ProgressPercentage float64 `json:"progress_percentage"`
 	CreatedAt         string  `json:"created_at"`
 	UpdatedAt         string  `json:"updated_at"`
 }
 
+// BookMetadataResponse representa os metadados bibliográficos do livro
+type BookMetadataResponse struct {
+	Creators      []domain.Creator    `json:"creators"`
+	Language      string              `json:"language,omitempty"`
+	Publisher     string              `json:"publisher,omitempty"`
+	PublishedDate string              `json:"published_date,omitempty"`
+	ISBN          string              `json:"isbn,omitempty"`
+	Identifiers   []domain.Identifier `json:"identifiers"`
+	Description   string              `json:"description,omitempty"`
+	Subjects      []string            `json:"subjects"`
+	Series        string              `json:"series,omitempty"`
+	SeriesIndex   *float64            `json:"series_index,omitempty"`
+	CreationDate  *time.Time          `json:"creation_date,omitempty"`
+}
+
+// OutlineResponse representa o sumário de um livro
+type OutlineResponse struct {
+	BookID    uint                 `json:"book_id"`
+	PageCount int                  `json:"page_count"`
+	Outline   []domain.OutlineItem `json:"outline"`
+}
+
+// OrgDocumentResponse representa um documento Org renderizado em HTML
+type OrgDocumentResponse struct {
+	BookID   uint          `json:"book_id"`
+	Title    string        `json:"title"`
+	Author   string        `json:"author,omitempty"`
+	HTML     string        `json:"html"` // Corpo do documento, já sanitizado
+	Headings []org.Heading `json:"headings"`
+}
+
 // UpdateProgressRequest representa a requisição de atualização de progresso
 type UpdateProgressRequest struct {
 	CurrentPage       int     `json:"current_page" binding:"required,min=0"`
 	ProgressPercentage float64 `json:"progress_percentage" binding:"required,min=0,max=100"`
 }
 
+// UpdateBookRequest representa a edição do título e dos metadados de um livro.
+// Campos omitidos não são alterados; series vazio remove a série e a posição.
+type UpdateBookRequest struct {
+	Title         *string           `json:"title" binding:"omitempty,max=500"`
+	Creators      *[]domain.Creator `json:"creators" binding:"omitempty,max=50"`
+	Language      *string           `json:"language" binding:"omitempty,max=35"`
+	Publisher     *string           `json:"publisher" binding:"omitempty,max=255"`
+	PublishedDate *string           `json:"published_date" binding:"omitempty,max=32"`
+	ISBN          *string           `json:"isbn" binding:"omitempty,max=20"`
+	Description   *string           `json:"description" binding:"omitempty,max=20000"`
+	Subjects      *[]string         `json:"subjects" binding:"omitempty,max=100"`
+	Series        *string           `json:"series" binding:"omitempty,max=255"`
+	SeriesIndex   *float64          `json:"series_index" binding:"omitempty,min=0"`
+	WriteBack     bool              `json:"write_back"` // Grava também no OPF do EPUB (gera um novo arquivo)
+}
+
 // ListBooksResponse representa a resposta com lista de livros
 type ListBooksResponse struct {
 	Books []BookResponse `json:"books"`
 	Total int           `json:"total"`
 }
 
+// BookFile representa o conteúdo de um livro aberto para download
+type BookFile struct {
+	Content     io.ReadSeekCloser
+	ContentHash string // SHA-256 do conteúdo (vazio em livros antigos)
+	Filename    string
+	Format      string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// CoverFile representa uma miniatura de capa aberta para leitura
+type CoverFile struct {
+	Content io.ReadSeekCloser
+	ETag    string // Hash do conteúdo e tamanho: a miniatura não muda
+	ModTime time.Time
+}
+
+// StorageUsageResponse representa o espaço ocupado pelos livros de um usuário
+type StorageUsageResponse struct {
+	UserID uint  `json:"user_id"`
+	Books  int64 `json:"books"`
+	Bytes  int64 `json:"bytes"`
+}
+
+// CreateUploadRequest representa a criação de um upload resumível (tus)
+type CreateUploadRequest struct {
+	Length         int64
+	Filename       string
+	Metadata       string // Upload-Metadata original, devolvido no HEAD
+	AllowDuplicate bool
+}
+
+// UploadResponse representa o estado de um upload resumível
+type UploadResponse struct {
+	ID        string
+	Filename  string
+	Length    int64
+	Offset    int64
+	Metadata  string
+	BookID    *uint
+	ExpiresAt time.Time
+}
+
+// UsageResponse representa o uso de armazenamento do usuário autenticado
+type UsageResponse struct {
+	Books          int64  `json:"books"`
+	UsedBytes      int64  `json:"used_bytes"`
+	QuotaBytes     int64  `json:"quota_bytes"`     // 0 = sem limite
+	AvailableBytes *int64 `json:"available_bytes"` // null quando não há limite
+}
+
+// UserQuotaResponse representa a cota de um usuário
+type UserQuotaResponse struct {
+	QuotaBytes int64 `json:"quota_bytes"` // 0 = sem limite
+	Custom     bool  `json:"quota_custom"` // true quando definida pelo administrador
+}
+
+// SetQuotaRequest representa a definição da cota de um usuário pelo administrador
+type SetQuotaRequest struct {
+	QuotaBytes *int64 `json:"quota_bytes" binding:"omitempty,min=0"` // null volta para a cota padrão; 0 = sem limite
+}
+
+// StorageReport representa o resultado da verificação de consistência entre o
+// armazenamento e a tabela de livros
+type StorageReport struct {
+	CheckedAt          time.Time          `json:"checked_at"`
+	Objects            int                `json:"objects"`
+	Books              int                `json:"books"`
+	OrphanFiles        []OrphanFile       `json:"orphan_files"`         // Arquivos que nenhum livro referencia
+	MissingFiles       []MissingFile      `json:"missing_files"`        // Livros cujo arquivo não existe
+	RefCountMismatches []RefCountMismatch `json:"ref_count_mismatches"` // Blobs com contagem diferente dos livros
+	DeletedFiles       int                `json:"deleted_files"`
+	DeletedBytes       int64              `json:"deleted_bytes"`
+}
+
+// OrphanFile representa um arquivo sem livro
+type OrphanFile struct {
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"` // Tamanho gravado (cifrado, se houver criptografia)
+	ModTime time.Time `json:"mod_time"`
+	Deleted bool      `json:"deleted"`
+}
+
+// MissingFile representa um livro cujo arquivo não está no armazenamento
+type MissingFile struct {
+	BookID uint   `json:"book_id"`
+	UserID uint   `json:"user_id"`
+	Key    string `json:"key"`
+}
+
+// RefCountMismatch representa um blob cuja contagem de referências não confere
+type RefCountMismatch struct {
+	Hash     string `json:"hash"`
+	RefCount int    `json:"ref_count"`
+	Books    int    `json:"books"`
+	Deleted  bool   `json:"deleted"` // Registro sem livros removido
+}
@@ -0,0 +1,298 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/storage"
+	"cloud-reader/backend/pkg/epub"
+)
+
+// isbnPattern aceita ISBN-10 e ISBN-13 já sem hífens
+var isbnPattern = regexp.MustCompile(`^(97[89])?\d{9}[\dX]$`)
+
+// UpdateBook edita o título e os metadados de um livro. Com WriteBack, os
+// metadados também são gravados no OPF do EPUB: o arquivo editado é um novo
+// conteúdo (novo hash) e o anterior não é alterado, pois pode ser
+// compartilhado com outros livros.
+func (s *BookService) UpdateBook(ctx context.Context, id uint, userID uint, req *UpdateBookRequest) (*BookResponse, error) {
+	if err := normalizeUpdate(req); err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, domain.ErrBookNotFound
+	}
+	if req.WriteBack && book.Format != "epub" {
+		return nil, &InvalidMetadataError{Field: "write_back", Reason: "só é possível gravar os metadados no arquivo de livros EPUB"}
+	}
+
+	previous := *book
+	applyUpdate(book, req)
+
+	replaced := false
+	if req.WriteBack {
+		spool, err := s.rewriteEPUB(ctx, &previous, req)
+		switch {
+		case errors.Is(err, epub.ErrNotModified):
+			// O arquivo já tem esses metadados
+		case err != nil:
+			return nil, err
+		default:
+			defer spool.Close()
+			if err := s.replaceContent(ctx, book, spool); err != nil {
+				return nil, err
+			}
+			replaced = true
+		}
+	}
+
+	// Só grava se o conteúdo não mudou desde a leitura (outra edição simultânea)
+	if err := s.bookRepo.Update(ctx, book, previous.ContentHash); err != nil {
+		if replaced {
+			s.releaseBlob(ctx, book.ContentHash)
+			s.releaseQuota(ctx, userID, book.FileSize)
+		}
+		if errors.Is(err, domain.ErrDuplicateBook) {
+			return nil, s.duplicateError(ctx, userID, book.ContentHash)
+		}
+		return nil, err
+	}
+
+	if replaced {
+		// O livro deixa de referenciar o conteúdo anterior
+		if err := s.deleteBookContent(ctx, &previous); err != nil {
+			log.Printf("Erro ao liberar arquivo anterior do livro %d: %v", book.ID, err)
+		}
+		s.releaseQuota(ctx, userID, previous.FileSize)
+	}
+
+	return s.GetBook(ctx, id, userID)
+}
+
+// rewriteEPUB gera uma cópia do EPUB do livro com as alterações no OPF.
+// Retorna epub.ErrNotModified se o OPF já tiver os metadados pedidos.
+func (s *BookService) rewriteEPUB(ctx context.Context, book *domain.Book, req *UpdateBookRequest) (*storage.SpooledFile, error) {
+	content, err := s.store.Get(ctx, storage.KeyFromPath(book.FilePath))
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Erro ao abrir arquivo do livro %d: %v", book.ID, err)
+		}
+		return nil, errors.New("arquivo não encontrado")
+	}
+	original, err := storage.Spool(content)
+	content.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer original.Close()
+
+	archive, err := zip.NewReader(original.ReaderAt(), original.Size)
+	if err != nil {
+		return nil, fmt.Errorf("epub inválido: %w", err)
+	}
+	pkg, err := epub.Read(archive)
+	if err != nil {
+		return nil, fmt.Errorf("epub inválido: %w", err)
+	}
+
+	// As alterações são aplicadas sobre os metadados do próprio OPF, para não
+	// apagar do arquivo campos que o banco não tem (ex.: livros antigos)
+	metadata := pkg.Metadata
+	applyEPUBUpdate(&metadata, req)
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	go func() {
+		writer.CloseWithError(epub.Rewrite(archive, writer, &metadata))
+	}()
+	return storage.Spool(reader)
+}
+
+// replaceContent grava o novo conteúdo do livro, com a cota e as capas
+func (s *BookService) replaceContent(ctx context.Context, book *domain.Book, spool *storage.SpooledFile) error {
+	mimeType, err := spool.DetectContentType(book.Format)
+	if err != nil {
+		return err
+	}
+	if err := s.reserveQuota(ctx, book.UserID, spool.Size); err != nil {
+		return err
+	}
+	blob, err := s.storeBlob(ctx, spool, mimeType)
+	if err != nil {
+		s.releaseQuota(ctx, book.UserID, spool.Size)
+		return err
+	}
+
+	book.FilePath = blob.StorageKey
+	book.ContentHash = blob.Hash
+	book.FileSize = spool.Size
+	book.MimeType = mimeType
+	book.HasCover = s.generateCovers(ctx, spool, book.Format)
+	return nil
+}
+
+// normalizeUpdate remove espaços extras dos campos e valida os valores
+func normalizeUpdate(req *UpdateBookRequest) error {
+	for _, value := range []*string{req.Title, req.Language, req.Publisher, req.PublishedDate, req.Description, req.Series} {
+		if value != nil {
+			*value = strings.TrimSpace(*value)
+		}
+	}
+	if req.Title != nil && *req.Title == "" {
+		return &InvalidMetadataError{Field: "title", Reason: "o título não pode ser vazio"}
+	}
+
+	if req.Creators != nil {
+		creators := make([]domain.Creator, 0, len(*req.Creators))
+		for _, creator := range *req.Creators {
+			creator.Name = strings.TrimSpace(creator.Name)
+			creator.Role = strings.ToLower(strings.TrimSpace(creator.Role))
+			creator.FileAs = strings.TrimSpace(creator.FileAs)
+			if creator.Name == "" {
+				return &InvalidMetadataError{Field: "creators", Reason: "o nome não pode ser vazio"}
+			}
+			creators = append(creators, creator)
+		}
+		req.Creators = &creators
+	}
+
+	if req.ISBN != nil {
+		isbn := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(*req.ISBN))
+		if isbn != "" && !isbnPattern.MatchString(isbn) {
+			return &InvalidMetadataError{Field: "isbn", Reason: "informe um ISBN de 10 ou 13 dígitos"}
+		}
+		req.ISBN = &isbn
+	}
+
+	if req.Subjects != nil {
+		subjects := []string{}
+		for _, subject := range *req.Subjects {
+			if subject = strings.TrimSpace(subject); subject != "" {
+				subjects = append(subjects, subject)
+			}
+		}
+		req.Subjects = &subjects
+	}
+	return nil
+}
+
+// applyUpdate aplica a edição ao livro e aos metadados do banco
+func applyUpdate(book *domain.Book, req *UpdateBookRequest) {
+	if req.Title != nil {
+		book.Title = *req.Title
+	}
+
+	metadata := book.Metadata
+	if metadata == nil {
+		metadata = &domain.BookMetadata{
+			BookID:      book.ID,
+			Creators:    []domain.Creator{},
+			Identifiers: []domain.Identifier{},
+			Subjects:    []string{},
+		}
+	} else {
+		// Cópia: o livro anterior (previous) compartilha o ponteiro
+		copied := *metadata
+		metadata = &copied
+	}
+	book.Metadata = metadata
+
+	if req.Creators != nil {
+		metadata.Creators = *req.Creators
+	}
+	if req.Language != nil {
+		metadata.Language = *req.Language
+	}
+	if req.Publisher != nil {
+		metadata.Publisher = *req.Publisher
+	}
+	if req.PublishedDate != nil {
+		metadata.PublishedDate = *req.PublishedDate
+	}
+	if req.Description != nil {
+		metadata.Description = *req.Description
+	}
+	if req.Subjects != nil {
+		metadata.Subjects = *req.Subjects
+	}
+	if req.ISBN != nil {
+		metadata.ISBN = *req.ISBN
+		identifiers := []domain.Identifier{}
+		for _, identifier := range metadata.Identifiers {
+			if identifier.Scheme != "isbn" {
+				identifiers = append(identifiers, identifier)
+			}
+		}
+		if *req.ISBN != "" {
+			identifiers = append(identifiers, domain.Identifier{Scheme: "isbn", Value: *req.ISBN})
+		}
+		metadata.Identifiers = identifiers
+	}
+	if req.Series != nil {
+		metadata.Series = *req.Series
+	}
+	if req.SeriesIndex != nil {
+		metadata.SeriesIndex = req.SeriesIndex
+	}
+	if metadata.Series == "" {
+		metadata.SeriesIndex = nil
+	}
+}
+
+// applyEPUBUpdate aplica a edição aos metadados lidos do OPF
+func applyEPUBUpdate(metadata *epub.Metadata, req *UpdateBookRequest) {
+	if req.Title != nil {
+		metadata.Title = *req.Title
+	}
+	if req.Creators != nil {
+		metadata.Creators = make([]epub.Creator, len(*req.Creators))
+		for i, creator := range *req.Creators {
+			metadata.Creators[i] = epub.Creator(creator)
+		}
+	}
+	if req.Language != nil {
+		metadata.Language = *req.Language
+	}
+	if req.Publisher != nil {
+		metadata.Publisher = *req.Publisher
+	}
+	if req.PublishedDate != nil {
+		metadata.Date = *req.PublishedDate
+	}
+	if req.Description != nil {
+		metadata.Description = *req.Description
+	}
+	if req.Subjects != nil {
+		metadata.Subjects = *req.Subjects
+	}
+	if req.ISBN != nil {
+		var identifiers []epub.Identifier
+		for _, identifier := range metadata.Identifiers {
+			if identifier.Scheme != "isbn" {
+				identifiers = append(identifiers, identifier)
+			}
+		}
+		if *req.ISBN != "" {
+			identifiers = append(identifiers, epub.Identifier{Scheme: "isbn", Value: *req.ISBN})
+		}
+		metadata.Identifiers = identifiers
+	}
+	if req.Series != nil {
+		metadata.Series = *req.Series
+	}
+	if req.SeriesIndex != nil {
+		metadata.SeriesIndex = req.SeriesIndex
+	}
+	if metadata.Series == "" {
+		metadata.SeriesIndex = nil
+	}
+}
@@ -0,0 +1,34 @@
+package application
+
+// DuplicateBookError indica que o usuário já possui um livro com o mesmo conteúdo
+type DuplicateBookError struct {
+	ExistingBookID uint
+}
+
+// Error implementa a interface error
+func (e *DuplicateBookError) Error() string {
+	return "este arquivo já está na sua biblioteca"
+}
+
+// QuotaExceededError indica que o upload ultrapassaria a cota de armazenamento do usuário
+type QuotaExceededError struct {
+	QuotaBytes     int64
+	UsedBytes      int64
+	RequestedBytes int64
+}
+
+// Error implementa a interface error
+func (e *QuotaExceededError) Error() string {
+	return "cota de armazenamento excedida"
+}
+
+// InvalidMetadataError indica um campo inválido na edição dos metadados
+type InvalidMetadataError struct {
+	Field  string
+	Reason string
+}
+
+// Error implementa a interface error
+func (e *InvalidMetadataError) Error() string {
+	return "metadado inválido (" + e.Field + "): " + e.Reason
+}
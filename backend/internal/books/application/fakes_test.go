@@ -0,0 +1,423 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/internal/shared/storage"
+)
+
+var errNotFound = errors.New("registro não encontrado")
+
+// testBooks reúne o BookService de teste e os fakes por trás dele
+type testBooks struct {
+	service *BookService
+	books   *fakeBookRepo
+	blobs   *fakeBlobRepo
+	usage   *fakeUsageRepo
+	store   storage.Storage
+}
+
+// newTestBookService cria um BookService sobre repositórios em memória e um
+// armazenamento local temporário, com a cota padrão informada (0 = sem limite)
+func newTestBookService(t *testing.T, defaultQuota int64) *testBooks {
+	t.Helper()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb := &testBooks{
+		books: &fakeBookRepo{books: make(map[uint]*domain.Book)},
+		blobs: &fakeBlobRepo{blobs: make(map[string]*domain.Blob)},
+		usage: &fakeUsageRepo{usage: make(map[uint]*domain.UserStorage)},
+		store: store,
+	}
+	tb.service = NewBookService(tb.books, tb.blobs, tb.usage, store, &config.Config{
+		StorageQuotaBytes:       defaultQuota,
+		EPUBMaxUncompressedSize: 10 << 20,
+		EPUBMaxCompressionRatio: 100,
+		EPUBMaxEntries:          100,
+		EPUBInspectTimeout:      time.Second,
+	})
+	return tb
+}
+
+// fakeBookRepo guarda os livros em memória e, como o índice único de conteúdo,
+// recusa um segundo livro do usuário com o mesmo hash (exceto cópias permitidas)
+type fakeBookRepo struct {
+	mu     sync.Mutex
+	books  map[uint]*domain.Book
+	nextID uint
+}
+
+func (r *fakeBookRepo) Create(ctx context.Context, book *domain.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !book.Duplicate {
+		for _, existing := range r.books {
+			if existing.UserID == book.UserID && existing.ContentHash == book.ContentHash && !existing.Duplicate {
+				return domain.ErrDuplicateBook
+			}
+		}
+	}
+	r.nextID++
+	book.ID = r.nextID
+	book.CreatedAt = time.Now()
+	book.UpdatedAt = book.CreatedAt
+	copied := *book
+	r.books[book.ID] = &copied
+	return nil
+}
+
+func (r *fakeBookRepo) FindByID(ctx context.Context, id uint, userID uint) (*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	book, ok := r.books[id]
+	if !ok || book.UserID != userID {
+		return nil, domain.ErrBookNotFound
+	}
+	copied := *book
+	return &copied, nil
+}
+
+func (r *fakeBookRepo) FindByUserAndHash(ctx context.Context, userID uint, contentHash string) (*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found *domain.Book
+	for _, book := range r.books {
+		if book.UserID == userID && book.ContentHash == contentHash && (found == nil || book.ID < found.ID) {
+			found = book
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrBookNotFound
+	}
+	copied := *found
+	return &copied, nil
+}
+
+func (r *fakeBookRepo) FindByUserID(ctx context.Context, userID uint) ([]*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var books []*domain.Book
+	for _, book := range r.books {
+		if book.UserID == userID {
+			copied := *book
+			books = append(books, &copied)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID > books[j].ID })
+	return books, nil
+}
+
+func (r *fakeBookRepo) Delete(ctx context.Context, id uint, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if book, ok := r.books[id]; !ok || book.UserID != userID {
+		return domain.ErrBookNotFound
+	}
+	delete(r.books, id)
+	return nil
+}
+
+func (r *fakeBookRepo) UpdateProgress(ctx context.Context, id uint, userID uint, currentPage int, progressPercentage float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	book, ok := r.books[id]
+	if !ok || book.UserID != userID {
+		return domain.ErrBookNotFound
+	}
+	book.CurrentPage = currentPage
+	book.ProgressPercentage = progressPercentage
+	return nil
+}
+
+func (r *fakeBookRepo) Update(ctx context.Context, book *domain.Book, contentHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.books[book.ID]
+	if !ok || stored.ContentHash != contentHash {
+		return domain.ErrBookNotFound
+	}
+	copied := *book
+	r.books[book.ID] = &copied
+	return nil
+}
+
+func (r *fakeBookRepo) UsageByUser(ctx context.Context, userIDs []uint) ([]domain.StorageUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byUser := make(map[uint]*domain.StorageUsage)
+	for _, book := range r.books {
+		usage, ok := byUser[book.UserID]
+		if !ok {
+			usage = &domain.StorageUsage{UserID: book.UserID}
+			byUser[book.UserID] = usage
+		}
+		usage.Books++
+		usage.Bytes += book.FileSize
+	}
+	var result []domain.StorageUsage
+	for _, userID := range userIDs {
+		if usage, ok := byUser[userID]; ok {
+			result = append(result, *usage)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeBookRepo) DeleteByUserID(ctx context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, book := range r.books {
+		if book.UserID == userID {
+			delete(r.books, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeBookRepo) FindAllFiles(ctx context.Context) ([]*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var books []*domain.Book
+	for _, book := range r.books {
+		copied := *book
+		books = append(books, &copied)
+	}
+	return books, nil
+}
+
+// fakeBlobRepo guarda os blobs e a contagem de referências em memória
+type fakeBlobRepo struct {
+	mu    sync.Mutex
+	blobs map[string]*domain.Blob
+}
+
+// refCount retorna a contagem de referências do blob (0 se não houver registro)
+func (r *fakeBlobRepo) refCount(hash string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if blob, ok := r.blobs[hash]; ok {
+		return blob.RefCount
+	}
+	return 0
+}
+
+func (r *fakeBlobRepo) Acquire(ctx context.Context, blob *domain.Blob) (*domain.Blob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.blobs[blob.Hash]
+	if !ok {
+		copied := *blob
+		copied.RefCount = 0
+		copied.CreatedAt = time.Now()
+		stored = &copied
+		r.blobs[blob.Hash] = stored
+	}
+	stored.RefCount++
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *fakeBlobRepo) Release(ctx context.Context, hash string, deleteContent func(blob *domain.Blob) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blob, ok := r.blobs[hash]
+	if !ok {
+		return nil
+	}
+	if blob.RefCount > 1 {
+		blob.RefCount--
+		return nil
+	}
+	if err := deleteContent(blob); err != nil {
+		return err
+	}
+	delete(r.blobs, hash)
+	return nil
+}
+
+func (r *fakeBlobRepo) FindByHash(ctx context.Context, hash string) (*domain.Blob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blob, ok := r.blobs[hash]
+	if !ok {
+		return nil, errNotFound
+	}
+	copied := *blob
+	return &copied, nil
+}
+
+func (r *fakeBlobRepo) FindAll(ctx context.Context) ([]*domain.Blob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var blobs []*domain.Blob
+	for _, blob := range r.blobs {
+		copied := *blob
+		blobs = append(blobs, &copied)
+	}
+	return blobs, nil
+}
+
+func (r *fakeBlobRepo) DeleteUnreferenced(ctx context.Context, hash, storageKey string, cutoff time.Time, deleteContent func(blob *domain.Blob) error) (bool, error) {
+	return false, errors.New("não implementado")
+}
+
+// fakeUsageRepo guarda o uso e a cota por usuário em memória
+type fakeUsageRepo struct {
+	mu    sync.Mutex
+	usage map[uint]*domain.UserStorage
+}
+
+// ensure retorna o registro do usuário, criando-o se necessário
+func (r *fakeUsageRepo) ensure(userID uint) *domain.UserStorage {
+	usage, ok := r.usage[userID]
+	if !ok {
+		usage = &domain.UserStorage{UserID: userID}
+		r.usage[userID] = usage
+	}
+	return usage
+}
+
+// used retorna o uso registrado do usuário
+func (r *fakeUsageRepo) used(userID uint) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ensure(userID).UsedBytes
+}
+
+func (r *fakeUsageRepo) Get(ctx context.Context, userID uint) (*domain.UserStorage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *r.ensure(userID)
+	return &copied, nil
+}
+
+func (r *fakeUsageRepo) FindByUserIDs(ctx context.Context, userIDs []uint) ([]*domain.UserStorage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*domain.UserStorage
+	for _, userID := range userIDs {
+		if usage, ok := r.usage[userID]; ok {
+			copied := *usage
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeUsageRepo) Reserve(ctx context.Context, userID uint, bytes int64, defaultQuota int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := r.ensure(userID)
+	quota := usage.EffectiveQuota(defaultQuota)
+	if quota != 0 && usage.UsedBytes+bytes > quota {
+		return false, nil
+	}
+	usage.UsedBytes += bytes
+	return true, nil
+}
+
+func (r *fakeUsageRepo) Release(ctx context.Context, userID uint, bytes int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := r.ensure(userID)
+	usage.UsedBytes = max(usage.UsedBytes-bytes, 0)
+	return nil
+}
+
+func (r *fakeUsageRepo) SetQuota(ctx context.Context, userID uint, quotaBytes *int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ensure(userID).QuotaBytes = quotaBytes
+	return nil
+}
+
+func (r *fakeUsageRepo) Delete(ctx context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.usage, userID)
+	return nil
+}
+
+// fakeUploadRepo guarda os uploads resumíveis em memória
+type fakeUploadRepo struct {
+	mu      sync.Mutex
+	uploads map[string]domain.Upload
+}
+
+func (r *fakeUploadRepo) Create(ctx context.Context, upload *domain.Upload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	upload.CreatedAt = time.Now()
+	r.uploads[upload.ID] = *upload
+	return nil
+}
+
+func (r *fakeUploadRepo) Update(ctx context.Context, upload *domain.Upload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uploads[upload.ID] = *upload
+	return nil
+}
+
+func (r *fakeUploadRepo) FindByID(ctx context.Context, id string, userID uint) (*domain.Upload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	upload, ok := r.uploads[id]
+	if !ok || upload.UserID != userID {
+		return nil, errNotFound
+	}
+	return &upload, nil
+}
+
+func (r *fakeUploadRepo) FindExpired(ctx context.Context, now time.Time) ([]*domain.Upload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var uploads []*domain.Upload
+	for _, upload := range r.uploads {
+		if upload.IsExpired(now) {
+			upload := upload
+			uploads = append(uploads, &upload)
+		}
+	}
+	return uploads, nil
+}
+
+func (r *fakeUploadRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.uploads, id)
+	return nil
+}
+
+func (r *fakeUploadRepo) FindByUserID(ctx context.Context, userID uint) ([]*domain.Upload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var uploads []*domain.Upload
+	for _, upload := range r.uploads {
+		if upload.UserID == userID {
+			upload := upload
+			uploads = append(uploads, &upload)
+		}
+	}
+	return uploads, nil
+}
+
+func (r *fakeUploadRepo) DeleteByUserID(ctx context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, upload := range r.uploads {
+		if upload.UserID == userID {
+			delete(r.uploads, id)
+		}
+	}
+	return nil
+}
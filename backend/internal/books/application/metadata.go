@@ -0,0 +1,147 @@
+package application
+
+import (
+	"log"
+	"regexp"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/storage"
+	"cloud-reader/backend/pkg/epub"
+	"cloud-reader/backend/pkg/pdf"
+)
+
+// extractMetadata lê os metadados e o número de páginas do arquivo enviado.
+// Metadados ilegíveis não impedem o upload: o título volta a ser o nome do arquivo.
+func extractMetadata(spool *storage.SpooledFile, format string) (string, int, *domain.BookMetadata) {
+	switch format {
+	case "epub":
+		pkg, err := epub.Open(spool.ReaderAt(), spool.Size)
+		if err != nil {
+			log.Printf("Erro ao ler metadados do EPUB: %v", err)
+			return "", 0, nil
+		}
+		return pkg.Metadata.Title, 0, epubMetadata(&pkg.Metadata)
+	case "pdf":
+		doc, err := pdf.Read(spool.ReaderAt(), spool.Size)
+		if err != nil {
+			log.Printf("Erro ao ler metadados do PDF: %v", err)
+			return "", 0, nil
+		}
+		return pdfTitle(doc.Metadata.Title), doc.PageCount, pdfMetadata(doc)
+	case "org":
+		r, err := spool.Reader()
+		if err != nil {
+			log.Printf("Erro ao ler metadados do documento Org: %v", err)
+			return "", 0, nil
+		}
+		doc, err := readOrg(r)
+		if err != nil {
+			log.Printf("Erro ao ler metadados do documento Org: %v", err)
+			return "", 0, nil
+		}
+		return doc.Title, 0, orgMetadata(doc)
+	default:
+		return "", 0, nil
+	}
+}
+
+// epubMetadata converte os metadados do OPF
+func epubMetadata(metadata *epub.Metadata) *domain.BookMetadata {
+	creators := make([]domain.Creator, len(metadata.Creators))
+	for i, creator := range metadata.Creators {
+		creators[i] = domain.Creator(creator)
+	}
+	identifiers := make([]domain.Identifier, len(metadata.Identifiers))
+	for i, identifier := range metadata.Identifiers {
+		identifiers[i] = domain.Identifier(identifier)
+	}
+	subjects := metadata.Subjects
+	if subjects == nil {
+		subjects = []string{}
+	}
+
+	return &domain.BookMetadata{
+		Creators:      creators,
+		Language:      metadata.Language,
+		Publisher:     metadata.Publisher,
+		PublishedDate: metadata.Date,
+		ISBN:          metadata.ISBN(),
+		Identifiers:   identifiers,
+		Description:   metadata.Description,
+		Subjects:      subjects,
+		Series:        metadata.Series,
+		SeriesIndex:   metadata.SeriesIndex,
+	}
+}
+
+// pdfMetadata converte o dicionário Info/XMP e o sumário do PDF. Sem campos
+// bibliográficos próprios, o assunto vira a descrição e as palavras-chave, os assuntos.
+func pdfMetadata(doc *pdf.Document) *domain.BookMetadata {
+	creators := make([]domain.Creator, len(doc.Metadata.Authors))
+	for i, author := range doc.Metadata.Authors {
+		creators[i] = domain.Creator{Name: author, Role: "aut"}
+	}
+	subjects := doc.Metadata.Keywords
+	if subjects == nil {
+		subjects = []string{}
+	}
+
+	return &domain.BookMetadata{
+		Creators:     creators,
+		Language:     doc.Metadata.Language,
+		Publisher:    doc.Metadata.Publisher,
+		Identifiers:  []domain.Identifier{},
+		Description:  doc.Metadata.Subject,
+		Subjects:     subjects,
+		CreationDate: doc.Metadata.CreationDate,
+		Outline:      outlineItems(doc.Outline),
+	}
+}
+
+// outlineItems converte o sumário do PDF
+func outlineItems(items []pdf.OutlineItem) []domain.OutlineItem {
+	if len(items) == 0 {
+		return nil
+	}
+	converted := make([]domain.OutlineItem, len(items))
+	for i, item := range items {
+		converted[i] = domain.OutlineItem{
+			Title:    item.Title,
+			Page:     item.Page,
+			Children: outlineItems(item.Children),
+		}
+	}
+	return converted
+}
+
+// junkTitle reconhece títulos gerados por ferramentas em vez do título do
+// documento (nome de arquivo, "Untitled", "Microsoft Word - ...")
+var junkTitle = regexp.MustCompile(`(?i)^(untitled|sem título|document\d*|microsoft (word|powerpoint) - .*|.*\.(docx?|pdf|tex|dvi|indd|pptx?|odt|rtf|txt))$`)
+
+// pdfTitle descarta títulos que não descrevem o documento
+func pdfTitle(title string) string {
+	if junkTitle.MatchString(title) {
+		return ""
+	}
+	return title
+}
+
+// toMetadataResponse converte os metadados para a resposta (nil se o livro não tiver)
+func toMetadataResponse(metadata *domain.BookMetadata) *BookMetadataResponse {
+	if metadata == nil {
+		return nil
+	}
+	return &BookMetadataResponse{
+		Creators:      metadata.Creators,
+		Language:      metadata.Language,
+		Publisher:     metadata.Publisher,
+		PublishedDate: metadata.PublishedDate,
+		ISBN:          metadata.ISBN,
+		Identifiers:   metadata.Identifiers,
+		Description:   metadata.Description,
+		Subjects:      metadata.Subjects,
+		Series:        metadata.Series,
+		SeriesIndex:   metadata.SeriesIndex,
+		CreationDate:  metadata.CreationDate,
+	}
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/storage"
+	"cloud-reader/backend/pkg/org"
+)
+
+// maxOrgFileSize limita o documento Org lido para metadados e renderização
+const maxOrgFileSize = 16 << 20
+
+// orgDate reconhece a data em #+DATE (ex.: "2024-03-01", "<2024-03-01 Fri>")
+var orgDate = regexp.MustCompile(`\d{4}(-\d{2}(-\d{2})?)?`)
+
+// GetOrgDocument renderiza um documento Org em HTML seguro, com a árvore de
+// títulos para navegação
+func (s *BookService) GetOrgDocument(ctx context.Context, id uint, userID uint) (*OrgDocumentResponse, error) {
+	book, err := s.bookRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, domain.ErrBookNotFound
+	}
+	if book.Format != "org" {
+		return nil, errors.New("o livro não é um documento Org")
+	}
+
+	key := storage.KeyFromPath(book.FilePath)
+	content, err := s.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Erro ao abrir arquivo %s: %v", key, err)
+		}
+		return nil, errors.New("arquivo não encontrado")
+	}
+	defer content.Close()
+
+	doc, err := readOrg(content)
+	if err != nil {
+		return nil, err
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = book.Title
+	}
+	headings := doc.Headings
+	if headings == nil {
+		headings = []org.Heading{}
+	}
+
+	return &OrgDocumentResponse{
+		BookID:   book.ID,
+		Title:    title,
+		Author:   doc.Author,
+		HTML:     doc.HTML(),
+		Headings: headings,
+	}, nil
+}
+
+// readOrg lê e interpreta um documento Org de até maxOrgFileSize
+func readOrg(r io.Reader) (*org.Document, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxOrgFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo: %w", err)
+	}
+	if len(data) > maxOrgFileSize {
+		return nil, errors.New("documento Org grande demais para exibir")
+	}
+	return org.Parse(string(data)), nil
+}
+
+// orgMetadata converte as palavras-chave do documento (#+AUTHOR, #+LANGUAGE,
+// #+DATE, #+DESCRIPTION)
+func orgMetadata(doc *org.Document) *domain.BookMetadata {
+	creators := []domain.Creator{}
+	if doc.Author != "" {
+		creators = append(creators, domain.Creator{Name: doc.Author, Role: "aut"})
+	}
+	language := doc.Keywords["LANGUAGE"]
+	if len(language) > 35 {
+		language = ""
+	}
+
+	return &domain.BookMetadata{
+		Creators:      creators,
+		Language:      language,
+		PublishedDate: orgDate.FindString(doc.Keywords["DATE"]),
+		Identifiers:   []domain.Identifier{},
+		Description:   strings.TrimSpace(doc.Keywords["DESCRIPTION"]),
+		Subjects:      []string{},
+	}
+}
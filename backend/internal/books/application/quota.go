@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"cloud-reader/backend/internal/books/domain"
+)
+
+// GetUsage retorna o uso de armazenamento e a cota do usuário
+func (s *BookService) GetUsage(ctx context.Context, userID uint) (*UsageResponse, error) {
+	usage, err := s.usageRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := s.bookRepo.UsageByUser(ctx, []uint{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &UsageResponse{
+		UsedBytes:  usage.UsedBytes,
+		QuotaBytes: usage.EffectiveQuota(s.defaultQuota),
+	}
+	if len(books) > 0 {
+		resp.Books = books[0].Books
+	}
+	if resp.QuotaBytes > 0 {
+		available := max(resp.QuotaBytes-resp.UsedBytes, 0)
+		resp.AvailableBytes = &available
+	}
+	return resp, nil
+}
+
+// GetUserQuotas retorna a cota de cada usuário informado
+func (s *BookService) GetUserQuotas(ctx context.Context, userIDs []uint) (map[uint]UserQuotaResponse, error) {
+	usage, err := s.usageRepo.FindByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := make(map[uint]UserQuotaResponse, len(userIDs))
+	for _, userID := range userIDs {
+		quotas[userID] = UserQuotaResponse{QuotaBytes: s.defaultQuota}
+	}
+	for _, u := range usage {
+		quotas[u.UserID] = UserQuotaResponse{
+			QuotaBytes: u.EffectiveQuota(s.defaultQuota),
+			Custom:     u.QuotaBytes != nil,
+		}
+	}
+	return quotas, nil
+}
+
+// SetUserQuota define a cota de um usuário (nil volta para a cota padrão)
+func (s *BookService) SetUserQuota(ctx context.Context, userID uint, req *SetQuotaRequest) error {
+	if err := s.usageRepo.SetQuota(ctx, userID, req.QuotaBytes); err != nil {
+		return fmt.Errorf("erro ao definir cota: %w", err)
+	}
+	return nil
+}
+
+// CheckQuota verifica, sem reservar, se bytes ainda cabem na cota do usuário.
+// Usado para recusar cedo uploads que certamente excederiam a cota.
+func (s *BookService) CheckQuota(ctx context.Context, userID uint, bytes int64) error {
+	usage, err := s.usageRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.quotaError(usage, bytes)
+}
+
+// reserveQuota soma o tamanho do novo livro ao uso do usuário, se couber na cota
+func (s *BookService) reserveQuota(ctx context.Context, userID uint, bytes int64) error {
+	ok, err := s.usageRepo.Reserve(ctx, userID, bytes, s.defaultQuota)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar cota: %w", err)
+	}
+	if ok {
+		return nil
+	}
+
+	usage, err := s.usageRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return &QuotaExceededError{
+		QuotaBytes:     usage.EffectiveQuota(s.defaultQuota),
+		UsedBytes:      usage.UsedBytes,
+		RequestedBytes: bytes,
+	}
+}
+
+// releaseQuota devolve o tamanho de um livro removido ao uso do usuário
+func (s *BookService) releaseQuota(ctx context.Context, userID uint, bytes int64) {
+	if err := s.usageRepo.Release(ctx, userID, bytes); err != nil {
+		log.Printf("Erro ao atualizar uso de armazenamento do usuário %d: %v", userID, err)
+	}
+}
+
+// quotaError retorna QuotaExceededError se bytes não couberem na cota
+func (s *BookService) quotaError(usage *domain.UserStorage, bytes int64) error {
+	quota := usage.EffectiveQuota(s.defaultQuota)
+	if quota == 0 || usage.UsedBytes+bytes <= quota {
+		return nil
+	}
+	return &QuotaExceededError{
+		QuotaBytes:     quota,
+		UsedBytes:      usage.UsedBytes,
+		RequestedBytes: bytes,
+	}
+}
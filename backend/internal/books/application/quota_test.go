@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 100)
+
+	first := tb.mustUpload(t, 1, orgContent("Primeiro", 60), false)
+	if got := tb.usage.used(1); got != 60 {
+		t.Fatalf("uso após o primeiro livro = %d, esperado 60", got)
+	}
+
+	// O livro que não cabe é recusado sem ocupar espaço nem deixar conteúdo
+	rejected := orgContent("Segundo", 60)
+	var exceeded *QuotaExceededError
+	_, err := tb.upload(t, 1, rejected, false)
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("upload acima da cota = %v, esperado QuotaExceededError", err)
+	}
+	if exceeded.QuotaBytes != 100 || exceeded.UsedBytes != 60 || exceeded.RequestedBytes != 60 {
+		t.Errorf("erro de cota = %+v", exceeded)
+	}
+	if got := tb.usage.used(1); got != 60 {
+		t.Errorf("uso após o upload recusado = %d, esperado 60", got)
+	}
+	if err := tb.service.CheckQuota(ctx, 1, 60); !errors.As(err, &exceeded) {
+		t.Errorf("CheckQuota = %v, esperado QuotaExceededError", err)
+	}
+	if err := tb.service.CheckQuota(ctx, 1, 40); err != nil {
+		t.Errorf("CheckQuota dentro da cota: %v", err)
+	}
+
+	// Outro usuário tem a própria cota, mesmo com o mesmo conteúdo
+	tb.mustUpload(t, 2, orgContent("Primeiro", 60), false)
+	if got := tb.usage.used(2); got != 60 {
+		t.Errorf("uso do usuário 2 = %d, esperado 60", got)
+	}
+
+	// A cota própria substitui a padrão
+	custom := int64(200)
+	if err := tb.service.SetUserQuota(ctx, 1, &SetQuotaRequest{QuotaBytes: &custom}); err != nil {
+		t.Fatal(err)
+	}
+	tb.mustUpload(t, 1, rejected, false)
+
+	usage, err := tb.service.GetUsage(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.UsedBytes != 120 || usage.QuotaBytes != 200 || usage.Books != 2 || usage.AvailableBytes == nil || *usage.AvailableBytes != 80 {
+		t.Errorf("GetUsage = %+v", usage)
+	}
+
+	quotas, err := tb.service.GetUserQuotas(ctx, []uint{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := quotas[1]; q.QuotaBytes != 200 || !q.Custom {
+		t.Errorf("cota do usuário 1 = %+v", q)
+	}
+	for _, userID := range []uint{2, 3} {
+		if q := quotas[userID]; q.QuotaBytes != 100 || q.Custom {
+			t.Errorf("cota do usuário %d = %+v", userID, q)
+		}
+	}
+
+	// Remover um livro devolve o espaço
+	if err := tb.service.DeleteBook(ctx, first.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := tb.usage.used(1); got != 60 {
+		t.Errorf("uso após remover um livro = %d, esperado 60", got)
+	}
+
+	// Cota 0 é ilimitada
+	unlimited := int64(0)
+	if err := tb.service.SetUserQuota(ctx, 1, &SetQuotaRequest{QuotaBytes: &unlimited}); err != nil {
+		t.Fatal(err)
+	}
+	usage, _ = tb.service.GetUsage(ctx, 1)
+	if usage.QuotaBytes != 0 || usage.AvailableBytes != nil {
+		t.Errorf("GetUsage sem limite = %+v", usage)
+	}
+	tb.mustUpload(t, 1, orgContent("Grande", 500), false)
+}
@@ -4,58 +4,154 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"mime/multipart"
-	"os"
 	"time"
 
 	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/config"
 	"cloud-reader/backend/internal/shared/storage"
 )
 
 // BookService define os casos de uso de livros
 type BookService struct {
-	bookRepo domain.BookRepository
+	bookRepo  domain.BookRepository
+	blobRepo  domain.BlobRepository
+	usageRepo domain.UserStorageRepository
+	store     storage.Storage
+
+	defaultQuota  int64
+	archiveLimits storage.ArchiveLimits
 }
 
 // NewBookService cria uma nova instância do BookService
-func NewBookService(bookRepo domain.BookRepository) *BookService {
+func NewBookService(
+	bookRepo domain.BookRepository,
+	blobRepo domain.BlobRepository,
+	usageRepo domain.UserStorageRepository,
+	store storage.Storage,
+	cfg *config.Config,
+) *BookService {
 	return &BookService{
-		bookRepo: bookRepo,
+		bookRepo:     bookRepo,
+		blobRepo:     blobRepo,
+		usageRepo:    usageRepo,
+		store:        store,
+		defaultQuota: cfg.StorageQuotaBytes,
+		archiveLimits: storage.ArchiveLimits{
+			MaxUncompressedSize: cfg.EPUBMaxUncompressedSize,
+			MaxCompressionRatio: cfg.EPUBMaxCompressionRatio,
+			MaxEntries:          cfg.EPUBMaxEntries,
+			Timeout:             cfg.EPUBInspectTimeout,
+		},
 	}
 }
 
-// UploadBook faz upload de um livro
-func (s *BookService) UploadBook(ctx context.Context, userID uint, fileHeader *multipart.FileHeader, file multipart.File) (*BookResponse, error) {
+// UploadBook faz upload de um livro. Se o usuário já tiver um livro com o mesmo
+// conteúdo, retorna DuplicateBookError, a menos que allowDuplicate seja true.
+func (s *BookService) UploadBook(ctx context.Context, userID uint, fileHeader *multipart.FileHeader, file multipart.File, allowDuplicate bool) (*BookResponse, error) {
 	// Valida arquivo
 	if err := storage.ValidateFile(fileHeader); err != nil {
 		return nil, err
 	}
 
-	// Salva arquivo no sistema de arquivos
-	filePath, _, err := storage.SaveFile(file, userID, fileHeader.Filename)
+	return s.createBook(ctx, userID, fileHeader.Filename, file, allowDuplicate)
+}
+
+// createBook grava o conteúdo e cria o livro. É o pipeline comum ao upload
+// multipart e à conclusão de um upload resumível.
+func (s *BookService) createBook(ctx context.Context, userID uint, filename string, content io.Reader, allowDuplicate bool) (*BookResponse, error) {
+	// Extrai informações do arquivo
+	title := storage.ExtractTitle(filename)
+	format := storage.GetFileFormat(filename)
+
+	// Copia o upload para um arquivo temporário calculando o SHA-256
+	spool, err := storage.Spool(content)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao salvar arquivo: %w", err)
+		return nil, err
 	}
+	defer spool.Close()
 
-	// Extrai informações do arquivo
-	title := storage.ExtractTitle(fileHeader.Filename)
-	format := storage.GetFileFormat(fileHeader.Filename)
+	// Confere pelos bytes se o conteúdo é mesmo do formato da extensão
+	mimeType, err := spool.DetectContentType(format)
+	if err != nil {
+		return nil, err
+	}
+
+	// EPUBs são lidos no servidor; recusa zip bombs e caminhos fora do arquivo
+	if format == "epub" {
+		if err := spool.InspectArchive(ctx, s.archiveLimits); err != nil {
+			return nil, err
+		}
+	}
+
+	// O título do arquivo (ex.: OPF do EPUB, Info do PDF) tem preferência sobre o nome do arquivo
+	metadataTitle, pageCount, metadata := extractMetadata(spool, format)
+	if metadataTitle != "" {
+		title = metadataTitle
+	}
+
+	// Evita uma segunda cópia do mesmo livro na biblioteca do usuário
+	if !allowDuplicate {
+		existing, err := s.bookRepo.FindByUserAndHash(ctx, userID, spool.Hash)
+		if err == nil {
+			return nil, &DuplicateBookError{ExistingBookID: existing.ID}
+		}
+		if !errors.Is(err, domain.ErrBookNotFound) {
+			return nil, err
+		}
+	}
+
+	// Reserva o espaço na cota do usuário
+	if err := s.reserveQuota(ctx, userID, spool.Size); err != nil {
+		return nil, err
+	}
+
+	// Salva o conteúdo no armazenamento (uma única cópia por hash)
+	blob, err := s.storeBlob(ctx, spool, mimeType)
+	if err != nil {
+		s.releaseQuota(ctx, userID, spool.Size)
+		return nil, err
+	}
+
+	// Até o livro ser criado, qualquer falha (inclusive um pânico ao ler o
+	// arquivo) libera a referência ao conteúdo e a cota
+	created := false
+	defer func() {
+		if !created {
+			s.releaseBlob(ctx, blob.Hash)
+			s.releaseQuota(ctx, userID, spool.Size)
+		}
+	}()
+
+	// Gera as miniaturas da capa (compartilhadas entre livros com o mesmo conteúdo)
+	hasCover := s.generateCovers(ctx, spool, format)
 
 	// Cria registro no banco de dados
 	book := &domain.Book{
-		UserID:   userID,
-		Title:    title,
-		Filename: fileHeader.Filename,
-		FilePath: filePath,
-		FileSize: fileHeader.Size,
-		Format:   format,
+		UserID:      userID,
+		Title:       title,
+		Filename:    filename,
+		FilePath:    blob.StorageKey,
+		ContentHash: blob.Hash,
+		FileSize:    spool.Size,
+		Format:      format,
+		MimeType:    mimeType,
+		PageCount:   pageCount,
+		HasCover:    hasCover,
+		Duplicate:   allowDuplicate,
+		Metadata:    metadata,
 	}
 
 	if err := s.bookRepo.Create(ctx, book); err != nil {
-		// Se falhar ao criar no BD, remove o arquivo
-		storage.DeleteFile(filePath)
+		// Outro upload do mesmo conteúdo foi concluído depois da verificação acima
+		if errors.Is(err, domain.ErrDuplicateBook) {
+			return nil, s.duplicateError(ctx, userID, blob.Hash)
+		}
 		return nil, fmt.Errorf("erro ao criar registro: %w", err)
 	}
+	created = true
 
 	return &BookResponse{
 		ID:                book.ID,
@@ -63,8 +159,13 @@ func (s *BookService) UploadBook(ctx context.Context, userID uint, fileHeader *m
 		Title:             book.Title,
 		Filename:          book.Filename,
 		FilePath:          book.FilePath,
+		ContentHash:       book.ContentHash,
 		FileSize:          book.FileSize,
 		Format:            book.Format,
+		MimeType:          book.MimeType,
+		Metadata:          toMetadataResponse(book.Metadata),
+		PageCount:         book.PageCount,
+		HasCover:          book.HasCover,
 		CurrentPage:       book.CurrentPage,
 		ProgressPercentage: book.ProgressPercentage,
 		CreatedAt:         book.CreatedAt.Format(time.RFC3339),
@@ -72,6 +173,15 @@ func (s *BookService) UploadBook(ctx context.Context, userID uint, fileHeader *m
 	}, nil
 }
 
+// duplicateError monta o DuplicateBookError com o livro que já tem o conteúdo
+func (s *BookService) duplicateError(ctx context.Context, userID uint, contentHash string) error {
+	existing, err := s.bookRepo.FindByUserAndHash(ctx, userID, contentHash)
+	if err != nil {
+		return &DuplicateBookError{}
+	}
+	return &DuplicateBookError{ExistingBookID: existing.ID}
+}
+
 // ListBooks lista todos os livros de um usuário
 func (s *BookService) ListBooks(ctx context.Context, userID uint) (*ListBooksResponse, error) {
 	books, err := s.bookRepo.FindByUserID(ctx, userID)
@@ -87,8 +197,13 @@ func (s *BookService) ListBooks(ctx context.Context, userID uint) (*ListBooksRes
 			Title:             book.Title,
 			Filename:          book.Filename,
 			FilePath:          book.FilePath,
+			ContentHash:       book.ContentHash,
 			FileSize:          book.FileSize,
 			Format:            book.Format,
+			MimeType:          book.MimeType,
+			Metadata:          toMetadataResponse(book.Metadata),
+			PageCount:         book.PageCount,
+			HasCover:          book.HasCover,
 			CurrentPage:       book.CurrentPage,
 			ProgressPercentage: book.ProgressPercentage,
 			CreatedAt:         book.CreatedAt.Format(time.RFC3339),
@@ -106,7 +221,7 @@ func (s *BookService) ListBooks(ctx context.Context, userID uint) (*ListBooksRes
 func (s *BookService) GetBook(ctx context.Context, id uint, userID uint) (*BookResponse, error) {
 	book, err := s.bookRepo.FindByID(ctx, id, userID)
 	if err != nil {
-		return nil, errors.New("livro não encontrado")
+		return nil, domain.ErrBookNotFound
 	}
 
 	return &BookResponse{
@@ -115,8 +230,13 @@ func (s *BookService) GetBook(ctx context.Context, id uint, userID uint) (*BookR
 		Title:             book.Title,
 		Filename:          book.Filename,
 		FilePath:          book.FilePath,
+		ContentHash:       book.ContentHash,
 		FileSize:          book.FileSize,
 		Format:            book.Format,
+		MimeType:          book.MimeType,
+		Metadata:          toMetadataResponse(book.Metadata),
+		PageCount:         book.PageCount,
+		HasCover:          book.HasCover,
 		CurrentPage:       book.CurrentPage,
 		ProgressPercentage: book.ProgressPercentage,
 		CreatedAt:         book.CreatedAt.Format(time.RFC3339),
@@ -127,9 +247,14 @@ func (s *BookService) GetBook(ctx context.Context, id uint, userID uint) (*BookR
 // UpdateReadingProgress atualiza o progresso de leitura de um livro
 func (s *BookService) UpdateReadingProgress(ctx context.Context, id uint, userID uint, currentPage int, progressPercentage float64) error {
 	// Valida ownership primeiro
-	_, err := s.bookRepo.FindByID(ctx, id, userID)
+	book, err := s.bookRepo.FindByID(ctx, id, userID)
 	if err != nil {
-		return errors.New("livro não encontrado")
+		return domain.ErrBookNotFound
+	}
+
+	// Com o total de páginas conhecido, a página atual não pode passar dele
+	if book.PageCount > 0 && currentPage > book.PageCount {
+		return errors.New("página atual maior que o total de páginas do livro")
 	}
 
 	// Atualiza o progresso
@@ -140,21 +265,41 @@ func (s *BookService) UpdateReadingProgress(ctx context.Context, id uint, userID
 	return nil
 }
 
+// GetOutline obtém o sumário de um livro (vazio se o arquivo não tiver)
+func (s *BookService) GetOutline(ctx context.Context, id uint, userID uint) (*OutlineResponse, error) {
+	book, err := s.bookRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, domain.ErrBookNotFound
+	}
+
+	outline := []domain.OutlineItem{}
+	if book.Metadata != nil && book.Metadata.Outline != nil {
+		outline = book.Metadata.Outline
+	}
+
+	return &OutlineResponse{
+		BookID:    book.ID,
+		PageCount: book.PageCount,
+		Outline:   outline,
+	}, nil
+}
+
 // DeleteBook remove um livro
 func (s *BookService) DeleteBook(ctx context.Context, id uint, userID uint) error {
 	// Busca o livro para obter o caminho do arquivo
 	book, err := s.bookRepo.FindByID(ctx, id, userID)
 	if err != nil {
-		return errors.New("livro não encontrado")
+		return domain.ErrBookNotFound
 	}
 
 	// Remove do banco de dados
 	if err := s.bookRepo.Delete(ctx, id, userID); err != nil {
 		return err
 	}
+	s.releaseQuota(ctx, userID, book.FileSize)
 
-	// Remove arquivo do sistema de arquivos
-	if err := storage.DeleteFile(book.FilePath); err != nil {
+	// Remove o arquivo do armazenamento se nenhum outro livro o referenciar
+	if err := s.deleteBookContent(ctx, book); err != nil {
 		// Log do erro mas não falha a operação se o arquivo já não existir
 		fmt.Printf("Aviso: erro ao deletar arquivo %s: %v\n", book.FilePath, err)
 	}
@@ -162,17 +307,88 @@ func (s *BookService) DeleteBook(ctx context.Context, id uint, userID uint) erro
 	return nil
 }
 
-// GetBookFile retorna o caminho do arquivo para download
-func (s *BookService) GetBookFile(ctx context.Context, id uint, userID uint) (string, error) {
+// OpenBookFile abre o arquivo do livro para leitura. O chamador deve fechar o Content.
+func (s *BookService) OpenBookFile(ctx context.Context, id uint, userID uint) (*BookFile, error) {
 	book, err := s.bookRepo.FindByID(ctx, id, userID)
 	if err != nil {
-		return "", errors.New("livro não encontrado")
+		return nil, domain.ErrBookNotFound
+	}
+
+	key := storage.KeyFromPath(book.FilePath)
+	info, err := s.store.Stat(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Erro ao consultar arquivo %s: %v", key, err)
+		}
+		return nil, errors.New("arquivo não encontrado")
+	}
+
+	content, err := s.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Erro ao abrir arquivo %s: %v", key, err)
+		}
+		return nil, errors.New("arquivo não encontrado")
 	}
 
-	// Verifica se o arquivo existe
-	if _, err := os.Stat(book.FilePath); os.IsNotExist(err) {
-		return "", errors.New("arquivo não encontrado")
+	// Livros antigos não têm o tipo detectado; usa o da extensão
+	contentType := book.MimeType
+	if contentType == "" {
+		contentType = storage.ContentType(book.Format)
 	}
 
-	return book.FilePath, nil
+	return &BookFile{
+		Content:     content,
+		ContentHash: book.ContentHash,
+		Filename:    book.Filename,
+		Format:      book.Format,
+		ContentType: contentType,
+		Size:        info.Size,
+		ModTime:     info.ModTime,
+	}, nil
+}
+
+// GetStorageUsage retorna o espaço ocupado por usuário (userIDs vazio = todos)
+func (s *BookService) GetStorageUsage(ctx context.Context, userIDs []uint) ([]StorageUsageResponse, error) {
+	usage, err := s.bookRepo.UsageByUser(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]StorageUsageResponse, len(usage))
+	for i, u := range usage {
+		responses[i] = StorageUsageResponse{
+			UserID: u.UserID,
+			Books:  u.Books,
+			Bytes:  u.Bytes,
+		}
+	}
+	return responses, nil
+}
+
+// DeleteUserBooks remove todos os livros de um usuário e os arquivos armazenados
+func (s *BookService) DeleteUserBooks(ctx context.Context, userID uint) error {
+	books, err := s.bookRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.bookRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("erro ao remover livros: %w", err)
+	}
+	if err := s.usageRepo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("erro ao remover uso de armazenamento: %w", err)
+	}
+
+	// Libera os arquivos dos livros e remove o que restar no prefixo antigo do usuário
+	for _, book := range books {
+		if err := s.deleteBookContent(ctx, book); err != nil {
+			return err
+		}
+	}
+	if err := storage.DeletePrefix(ctx, s.store, storage.UserBooksPrefix(userID)); err != nil {
+		return fmt.Errorf("erro ao remover arquivos: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,327 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/internal/shared/storage"
+
+	"github.com/google/uuid"
+)
+
+// uploadCleanupInterval é o intervalo da remoção de uploads abandonados
+const uploadCleanupInterval = time.Hour
+
+// UploadService define os casos de uso dos uploads resumíveis (protocolo tus).
+// Os bytes são acumulados em um arquivo parcial no disco local; ao receber o
+// último byte, o arquivo segue o mesmo pipeline do upload multipart.
+type UploadService struct {
+	books      *BookService
+	uploadRepo domain.UploadRepository
+
+	uploadDir string
+	maxSize   int64
+	ttl       time.Duration
+
+	// locks impede que dois PATCH simultâneos escrevam no mesmo upload
+	locks sync.Map
+}
+
+// NewUploadService cria uma nova instância do UploadService
+func NewUploadService(books *BookService, uploadRepo domain.UploadRepository, cfg *config.Config) *UploadService {
+	return &UploadService{
+		books:      books,
+		uploadRepo: uploadRepo,
+		uploadDir:  cfg.UploadDir,
+		maxSize:    cfg.UploadMaxSize,
+		ttl:        cfg.UploadTTL,
+	}
+}
+
+// MaxSize retorna o tamanho máximo aceito para um upload resumível
+func (s *UploadService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Start inicia a remoção periódica dos uploads expirados
+func (s *UploadService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(uploadCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			s.deleteExpired(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CreateUpload registra um novo upload e cria o arquivo parcial vazio
+func (s *UploadService) CreateUpload(ctx context.Context, userID uint, req *CreateUploadRequest) (*UploadResponse, error) {
+	if req.Length <= 0 {
+		return nil, errors.New("tamanho do upload inválido")
+	}
+	if req.Length > s.maxSize {
+		return nil, errors.New("upload maior que o tamanho máximo permitido")
+	}
+	if req.Filename == "" {
+		return nil, errors.New("nome do arquivo não informado")
+	}
+	if err := storage.ValidateExtension(req.Filename); err != nil {
+		return nil, err
+	}
+	// A cota é reservada na conclusão; aqui apenas recusa cedo o que já não cabe
+	if err := s.books.CheckQuota(ctx, userID, req.Length); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(s.uploadDir, 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	upload := &domain.Upload{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Filename:       filepath.Base(req.Filename),
+		Length:         req.Length,
+		Metadata:       req.Metadata,
+		AllowDuplicate: req.AllowDuplicate,
+		ExpiresAt:      time.Now().Add(s.ttl),
+	}
+
+	file, err := os.Create(s.dataPath(upload.ID))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar arquivo: %w", err)
+	}
+	file.Close()
+
+	if err := s.uploadRepo.Create(ctx, upload); err != nil {
+		os.Remove(s.dataPath(upload.ID))
+		return nil, err
+	}
+
+	return toUploadResponse(upload), nil
+}
+
+// GetUpload retorna o estado de um upload
+func (s *UploadService) GetUpload(ctx context.Context, userID uint, id string) (*UploadResponse, error) {
+	upload, err := s.findActive(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toUploadResponse(upload), nil
+}
+
+// WriteChunk acrescenta os bytes recebidos a partir de offset. Ao completar o
+// tamanho declarado, cria o livro e retorna o upload com BookID preenchido.
+func (s *UploadService) WriteChunk(ctx context.Context, userID uint, id string, offset int64, body io.Reader) (*UploadResponse, error) {
+	if _, err := s.findActive(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	unlock, ok := s.tryLock(id)
+	if !ok {
+		return nil, errors.New("upload em uso por outra requisição")
+	}
+	defer unlock()
+
+	// Relê o upload: outra requisição pode tê-lo alterado antes do lock
+	upload, err := s.findActive(ctx, userID, id)
+	if err != nil {
+		s.locks.Delete(id)
+		return nil, err
+	}
+	if upload.IsComplete() || offset != upload.Offset {
+		return nil, errors.New("offset do upload não confere")
+	}
+
+	written, writeErr := s.appendData(upload, body)
+
+	// Os bytes gravados são registrados mesmo se a conexão cair no meio do envio
+	upload.Offset += written
+	upload.ExpiresAt = time.Now().Add(s.ttl)
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return nil, err
+	}
+	if writeErr != nil {
+		return nil, fmt.Errorf("erro ao gravar upload: %w", writeErr)
+	}
+
+	if upload.IsComplete() {
+		if err := s.finish(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	return toUploadResponse(upload), nil
+}
+
+// DeleteUpload cancela um upload e remove o arquivo parcial
+func (s *UploadService) DeleteUpload(ctx context.Context, userID uint, id string) error {
+	if _, err := s.uploadRepo.FindByID(ctx, id, userID); err != nil {
+		return errors.New("upload não encontrado")
+	}
+	unlock, ok := s.tryLock(id)
+	if !ok {
+		return errors.New("upload em uso por outra requisição")
+	}
+	defer unlock()
+
+	upload, err := s.uploadRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		s.locks.Delete(id)
+		return errors.New("upload não encontrado")
+	}
+	return s.deleteUpload(ctx, upload)
+}
+
+// DeleteUserUploads remove os uploads de um usuário e seus arquivos parciais
+func (s *UploadService) DeleteUserUploads(ctx context.Context, userID uint) error {
+	uploads, err := s.uploadRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, upload := range uploads {
+		if err := s.removeData(upload.ID); err != nil {
+			return err
+		}
+		s.locks.Delete(upload.ID)
+	}
+	return s.uploadRepo.DeleteByUserID(ctx, userID)
+}
+
+// finish cria o livro a partir do arquivo completo. Se a criação falhar
+// (ex.: livro duplicado), o upload é descartado e o erro é retornado.
+func (s *UploadService) finish(ctx context.Context, upload *domain.Upload) error {
+	file, err := os.Open(s.dataPath(upload.ID))
+	if err != nil {
+		return fmt.Errorf("erro ao abrir upload: %w", err)
+	}
+
+	book, err := s.books.createBook(ctx, upload.UserID, upload.Filename, file, upload.AllowDuplicate)
+	file.Close()
+	if err != nil {
+		if deleteErr := s.deleteUpload(ctx, upload); deleteErr != nil {
+			log.Printf("Erro ao remover upload %s: %v", upload.ID, deleteErr)
+		}
+		return err
+	}
+
+	// O registro é mantido até expirar para que o cliente consulte o livro criado
+	upload.BookID = &book.ID
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return err
+	}
+	if err := s.removeData(upload.ID); err != nil {
+		log.Printf("Erro ao remover arquivo do upload %s: %v", upload.ID, err)
+	}
+
+	return nil
+}
+
+// appendData grava o corpo no fim do arquivo parcial, limitado ao tamanho declarado
+func (s *UploadService) appendData(upload *domain.Upload, body io.Reader) (int64, error) {
+	file, err := os.OpenFile(s.dataPath(upload.ID), os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	// Descarta bytes de uma escrita anterior que não chegou a ser registrada
+	if err := file.Truncate(upload.Offset); err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(upload.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(file, io.LimitReader(body, upload.Length-upload.Offset))
+	if syncErr := file.Sync(); err == nil {
+		err = syncErr
+	}
+	return written, err
+}
+
+// findActive busca um upload que ainda não expirou
+func (s *UploadService) findActive(ctx context.Context, userID uint, id string) (*domain.Upload, error) {
+	upload, err := s.uploadRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, errors.New("upload não encontrado")
+	}
+	if upload.IsExpired(time.Now()) {
+		return nil, errors.New("upload expirado")
+	}
+	return upload, nil
+}
+
+// deleteExpired remove os uploads abandonados e os registros de uploads concluídos
+func (s *UploadService) deleteExpired(ctx context.Context) {
+	uploads, err := s.uploadRepo.FindExpired(ctx, time.Now())
+	if err != nil {
+		log.Printf("Erro ao buscar uploads expirados: %v", err)
+		return
+	}
+	for _, upload := range uploads {
+		if err := s.deleteUpload(ctx, upload); err != nil {
+			log.Printf("Erro ao remover upload %s: %v", upload.ID, err)
+		}
+	}
+}
+
+// deleteUpload remove o arquivo parcial e o registro de um upload
+func (s *UploadService) deleteUpload(ctx context.Context, upload *domain.Upload) error {
+	if err := s.removeData(upload.ID); err != nil {
+		return err
+	}
+	s.locks.Delete(upload.ID)
+	return s.uploadRepo.Delete(ctx, upload.ID)
+}
+
+// removeData remove o arquivo parcial, se existir
+func (s *UploadService) removeData(id string) error {
+	if err := os.Remove(s.dataPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("erro ao deletar arquivo: %w", err)
+	}
+	return nil
+}
+
+// dataPath retorna o caminho do arquivo parcial de um upload
+func (s *UploadService) dataPath(id string) string {
+	return filepath.Join(s.uploadDir, id)
+}
+
+// tryLock obtém o lock do upload sem bloquear. Só deve ser chamado para
+// uploads existentes: a entrada do mapa é removida junto com o upload.
+func (s *UploadService) tryLock(id string) (func(), bool) {
+	value, _ := s.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	if !mu.TryLock() {
+		return nil, false
+	}
+	return mu.Unlock, true
+}
+
+// toUploadResponse converte o upload para a resposta
+func toUploadResponse(upload *domain.Upload) *UploadResponse {
+	return &UploadResponse{
+		ID:        upload.ID,
+		Filename:  upload.Filename,
+		Length:    upload.Length,
+		Offset:    upload.Offset,
+		Metadata:  upload.Metadata,
+		BookID:    upload.BookID,
+		ExpiresAt: upload.ExpiresAt,
+	}
+}
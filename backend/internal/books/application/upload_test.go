@@ -0,0 +1,251 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"cloud-reader/backend/internal/shared/config"
+)
+
+// newTestUploadService cria um UploadService sobre o BookService de teste
+func newTestUploadService(t *testing.T, tb *testBooks, maxSize int64) (*UploadService, *fakeUploadRepo) {
+	t.Helper()
+	uploads := &fakeUploadRepo{uploads: make(map[string]domain.Upload)}
+	return NewUploadService(tb.service, uploads, &config.Config{
+		UploadDir:     t.TempDir(),
+		UploadMaxSize: maxSize,
+		UploadTTL:     time.Hour,
+	}), uploads
+}
+
+// failingReader entrega os bytes de data e então falha, como uma conexão que cai
+type failingReader struct {
+	data *bytes.Reader
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("conexão interrompida")
+	}
+	return n, err
+}
+
+func TestCreateUploadValidation(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 100)
+	service, _ := newTestUploadService(t, tb, 1000)
+
+	tests := []struct {
+		name string
+		req  CreateUploadRequest
+	}{
+		{name: "tamanho zero", req: CreateUploadRequest{Length: 0, Filename: "a.org"}},
+		{name: "acima do máximo", req: CreateUploadRequest{Length: 1001, Filename: "a.org"}},
+		{name: "sem nome", req: CreateUploadRequest{Length: 10}},
+		{name: "extensão não permitida", req: CreateUploadRequest{Length: 10, Filename: "a.exe"}},
+		{name: "acima da cota", req: CreateUploadRequest{Length: 101, Filename: "a.org"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateUpload(ctx, 1, &tt.req); err == nil {
+				t.Error("upload criado, esperado erro")
+			}
+		})
+	}
+
+	upload, err := service.CreateUpload(ctx, 1, &CreateUploadRequest{Length: 10, Filename: "../dir/a.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload.Offset != 0 || upload.Filename != "a.org" {
+		t.Errorf("upload criado = %+v", upload)
+	}
+}
+
+func TestUploadOffsets(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	service, uploads := newTestUploadService(t, tb, 1000)
+	content := orgContent("Retomado", 300)
+
+	upload, err := service.CreateUpload(ctx, 1, &CreateUploadRequest{Length: int64(len(content)), Filename: "livro.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := service.WriteChunk(ctx, 1, upload.ID, 0, bytes.NewReader(content[:100]))
+	if err != nil || resp.Offset != 100 {
+		t.Fatalf("primeiro bloco = %+v, %v", resp, err)
+	}
+
+	// Offset diferente do registrado é recusado sem alterar o upload
+	for _, offset := range []int64{0, 50, 150} {
+		if _, err := service.WriteChunk(ctx, 1, upload.ID, offset, bytes.NewReader(content[offset:])); err == nil {
+			t.Errorf("bloco no offset %d aceito com o upload em 100", offset)
+		}
+	}
+
+	// Outro usuário não encontra o upload
+	if _, err := service.WriteChunk(ctx, 2, upload.ID, 100, bytes.NewReader(content[100:])); err == nil {
+		t.Error("bloco aceito de outro usuário")
+	}
+
+	// Os bytes recebidos antes da conexão cair são registrados
+	_, err = service.WriteChunk(ctx, 1, upload.ID, 100, &failingReader{data: bytes.NewReader(content[100:180])})
+	if err == nil {
+		t.Fatal("escrita interrompida sem erro")
+	}
+	state, err := service.GetUpload(ctx, 1, upload.ID)
+	if err != nil || state.Offset != 180 {
+		t.Fatalf("estado após a interrupção = %+v, %v; esperado offset 180", state, err)
+	}
+
+	// Bytes além do tamanho declarado são ignorados; o último bloco conclui o upload
+	body := append(append([]byte{}, content[180:]...), []byte("lixo depois do fim")...)
+	resp, err = service.WriteChunk(ctx, 1, upload.ID, 180, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Offset != int64(len(content)) || resp.BookID == nil {
+		t.Fatalf("último bloco = %+v", resp)
+	}
+
+	book, err := tb.service.OpenBookFile(ctx, *resp.BookID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer book.Content.Close()
+	if got, _ := io.ReadAll(book.Content); !bytes.Equal(got, content) {
+		t.Errorf("conteúdo do livro difere do enviado (%d bytes, esperado %d)", len(got), len(content))
+	}
+
+	// Concluído, o upload não aceita mais bytes e o arquivo parcial é removido
+	if _, err := service.WriteChunk(ctx, 1, upload.ID, resp.Offset, bytes.NewReader([]byte("x"))); err == nil {
+		t.Error("bloco aceito após a conclusão")
+	}
+	if _, err := os.Stat(service.dataPath(upload.ID)); !os.IsNotExist(err) {
+		t.Errorf("arquivo parcial mantido após a conclusão: %v", err)
+	}
+	if stored, _ := uploads.FindByID(ctx, upload.ID, 1); stored == nil || stored.BookID == nil {
+		t.Error("registro do upload concluído sem o livro")
+	}
+}
+
+func TestUploadDiscardsUnrecordedBytes(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	service, _ := newTestUploadService(t, tb, 1000)
+	content := orgContent("Parcial", 100)
+
+	upload, err := service.CreateUpload(ctx, 1, &CreateUploadRequest{Length: int64(len(content)), Filename: "livro.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.WriteChunk(ctx, 1, upload.ID, 0, bytes.NewReader(content[:40])); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bytes gravados no arquivo parcial sem chegar ao registro (ex.: queda do servidor)
+	file, err := os.OpenFile(service.dataPath(upload.ID), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("bytes que o cliente vai reenviar")
+	file.Close()
+
+	resp, err := service.WriteChunk(ctx, 1, upload.ID, 40, bytes.NewReader(content[40:]))
+	if err != nil || resp.BookID == nil {
+		t.Fatalf("conclusão = %+v, %v", resp, err)
+	}
+	book, err := tb.service.OpenBookFile(ctx, *resp.BookID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer book.Content.Close()
+	if got, _ := io.ReadAll(book.Content); !bytes.Equal(got, content) {
+		t.Error("bytes não registrados chegaram ao livro")
+	}
+}
+
+func TestUploadConcurrentChunk(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	service, _ := newTestUploadService(t, tb, 1000)
+
+	upload, err := service.CreateUpload(ctx, 1, &CreateUploadRequest{Length: 100, Filename: "livro.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Com o upload bloqueado por outra requisição, o PATCH é recusado
+	unlock, ok := service.tryLock(upload.ID)
+	if !ok {
+		t.Fatal("lock indisponível")
+	}
+	if _, err := service.WriteChunk(ctx, 1, upload.ID, 0, bytes.NewReader([]byte("x"))); err == nil {
+		t.Error("bloco aceito com o upload em uso")
+	}
+	unlock()
+
+	if resp, err := service.WriteChunk(ctx, 1, upload.ID, 0, bytes.NewReader([]byte("#+TITLE: x\n"))); err != nil || resp.Offset != 11 {
+		t.Errorf("bloco após o lock = %+v, %v", resp, err)
+	}
+}
+
+func TestUploadExpired(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	service, uploads := newTestUploadService(t, tb, 1000)
+
+	upload, err := service.CreateUpload(ctx, 1, &CreateUploadRequest{Length: 100, Filename: "livro.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := uploads.FindByID(ctx, upload.ID, 1)
+	stored.ExpiresAt = time.Now().Add(-time.Minute)
+	uploads.Update(ctx, stored)
+
+	if _, err := service.WriteChunk(ctx, 1, upload.ID, 0, bytes.NewReader([]byte("x"))); err == nil {
+		t.Error("bloco aceito em upload expirado")
+	}
+
+	service.deleteExpired(ctx)
+	if _, err := uploads.FindByID(ctx, upload.ID, 1); err == nil {
+		t.Error("upload expirado mantido")
+	}
+	if _, err := os.Stat(service.dataPath(upload.ID)); !os.IsNotExist(err) {
+		t.Errorf("arquivo parcial do upload expirado mantido: %v", err)
+	}
+}
+
+func TestUploadFinishDuplicate(t *testing.T) {
+	ctx := context.Background()
+	tb := newTestBookService(t, 0)
+	service, uploads := newTestUploadService(t, tb, 1000)
+	content := orgContent("Repetido", 100)
+	existing := tb.mustUpload(t, 1, content, false)
+
+	upload, err := service.CreateUpload(ctx, 1, &CreateUploadRequest{Length: int64(len(content)), Filename: "livro.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var duplicate *DuplicateBookError
+	if _, err := service.WriteChunk(ctx, 1, upload.ID, 0, bytes.NewReader(content)); !errors.As(err, &duplicate) || duplicate.ExistingBookID != existing.ID {
+		t.Fatalf("conclusão de livro repetido = %v, esperado DuplicateBookError", err)
+	}
+
+	// O upload recusado é descartado
+	if _, err := uploads.FindByID(ctx, upload.ID, 1); err == nil {
+		t.Error("upload recusado mantido")
+	}
+	if _, err := os.Stat(service.dataPath(upload.ID)); !os.IsNotExist(err) {
+		t.Errorf("arquivo parcial do upload recusado mantido: %v", err)
+	}
+}
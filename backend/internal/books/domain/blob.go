@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+// Blob representa um conteúdo armazenado uma única vez e compartilhado pelos
+// livros com o mesmo hash SHA-256 (armazenamento endereçado por conteúdo)
+type Blob struct {
+	Hash       string    `gorm:"primaryKey;size:64" json:"hash"` // SHA-256 em hexadecimal
+	StorageKey string    `gorm:"not null" json:"-"`
+	Size       int64     `gorm:"not null" json:"size"`
+	RefCount   int       `gorm:"not null;default:0" json:"ref_count"` // Livros que apontam para o conteúdo
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (Blob) TableName() string {
+	return "blobs"
+}
@@ -17,13 +17,27 @@ type Book struct {
 	Title              string  `gorm:"not null" json:"title"`
 	Filename           string  `gorm:"not null" json:"filename"`
 	FilePath           string  `gorm:"not null" json:"file_path"`
+	ContentHash        string  `gorm:"size:64;index" json:"content_hash"`      // SHA-256 do conteúdo (vazio em livros antigos)
+	Duplicate          bool    `gorm:"default:false" json:"-"`                 // Cópia enviada com allow_duplicate (fora do índice único por conteúdo)
 	FileSize           int64   `gorm:"not null" json:"file_size"`              // Tamanho em bytes
 	Format             string  `gorm:"not null" json:"format"`                 // pdf, epub, org
+	MimeType           string  `gorm:"size:100" json:"mime_type"`              // Tipo MIME detectado pelo conteúdo (vazio em livros antigos)
+	PageCount          int     `gorm:"default:0" json:"page_count"`            // Total de páginas (0 = desconhecido ou sem páginas fixas)
+	HasCover           bool    `gorm:"default:false" json:"has_cover"`         // Miniaturas de capa geradas no upload
 	CurrentPage        int     `gorm:"default:0" json:"current_page"`          // Página atual (0 = não iniciado)
 	ProgressPercentage float64 `gorm:"default:0.0" json:"progress_percentage"` // Porcentagem de progresso (0-100)
+
+	Metadata *BookMetadata `gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE" json:"metadata,omitempty"`
 }
 
 // TableName define o nome da tabela no banco de dados
 func (Book) TableName() string {
 	return "books"
 }
+
+// StorageUsage representa o espaço ocupado pelos livros de um usuário
+type StorageUsage struct {
+	UserID uint
+	Books  int64
+	Bytes  int64
+}
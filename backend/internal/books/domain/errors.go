@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrBookNotFound indica que o livro não existe ou pertence a outro usuário
+	ErrBookNotFound = errors.New("livro não encontrado")
+
+	// ErrDuplicateBook indica que o usuário já tem um livro com o mesmo conteúdo
+	// (violação do índice único de user_id e content_hash)
+	ErrDuplicateBook = errors.New("livro com o mesmo conteúdo já existe")
+)
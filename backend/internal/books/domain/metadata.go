@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// BookMetadata guarda os metadados bibliográficos extraídos do arquivo do livro
+type BookMetadata struct {
+	BookID        uint          `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Creators      []Creator     `gorm:"serializer:json;type:jsonb" json:"creators"`
+	Language      string        `gorm:"size:35" json:"language,omitempty"`
+	Publisher     string        `json:"publisher,omitempty"`
+	PublishedDate string        `gorm:"size:32" json:"published_date,omitempty"` // Como está no arquivo (AAAA, AAAA-MM-DD...)
+	ISBN          string        `gorm:"size:13;index" json:"isbn,omitempty"`
+	Identifiers   []Identifier  `gorm:"serializer:json;type:jsonb" json:"identifiers"`
+	Description   string        `gorm:"type:text" json:"description,omitempty"`
+	Subjects      []string      `gorm:"serializer:json;type:jsonb" json:"subjects"`
+	Series        string        `json:"series,omitempty"`
+	SeriesIndex   *float64      `json:"series_index,omitempty"`
+	CreationDate  *time.Time    `json:"creation_date,omitempty"`             // Data de criação do arquivo (PDF)
+	Outline       []OutlineItem `gorm:"serializer:json;type:jsonb" json:"-"` // Sumário (PDF), servido em rota própria
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (BookMetadata) TableName() string {
+	return "book_metadata"
+}
+
+// Creator é um autor, editor, tradutor etc. (role usa os códigos MARC: aut, edt, trl...)
+type Creator struct {
+	Name   string `json:"name"`
+	Role   string `json:"role,omitempty"`
+	FileAs string `json:"file_as,omitempty"`
+}
+
+// Identifier é um identificador do livro (scheme: isbn, uuid, asin, doi...)
+type Identifier struct {
+	Scheme string `json:"scheme,omitempty"`
+	Value  string `json:"value"`
+}
+
+// OutlineItem é uma entrada do sumário do livro. Page começa em 1 (0 = destino desconhecido).
+type OutlineItem struct {
+	Title    string        `json:"title"`
+	Page     int           `json:"page,omitempty"`
+	Children []OutlineItem `json:"children,omitempty"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+)
+
+// UserStorage guarda o espaço usado por um usuário e a cota definida pelo administrador
+type UserStorage struct {
+	UserID     uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	UsedBytes  int64     `gorm:"not null;default:0" json:"used_bytes"` // Soma do tamanho dos livros
+	QuotaBytes *int64    `json:"quota_bytes"`                          // nil = padrão do servidor; 0 = sem limite
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (UserStorage) TableName() string {
+	return "user_storage"
+}
+
+// EffectiveQuota retorna a cota do usuário, ou a padrão se não houver uma definida (0 = sem limite)
+func (u *UserStorage) EffectiveQuota(defaultQuota int64) int64 {
+	if u.QuotaBytes != nil {
+		return *u.QuotaBytes
+	}
+	return defaultQuota
+}
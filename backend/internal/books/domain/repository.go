@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // BookRepository define a interface do repositório de livros (port)
@@ -9,10 +10,13 @@ type BookRepository interface {
 	// Create cria um novo livro
 	Create(ctx context.Context, book *Book) error
 
-	// FindByID busca um livro pelo ID e UserID (valida ownership)
+	// FindByID busca um livro pelo ID e UserID (valida ownership), com os metadados
 	FindByID(ctx context.Context, id uint, userID uint) (*Book, error)
 
-	// FindByUserID busca todos os livros de um usuário
+	// FindByUserAndHash busca um livro do usuário com o conteúdo informado
+	FindByUserAndHash(ctx context.Context, userID uint, contentHash string) (*Book, error)
+
+	// FindByUserID busca todos os livros de um usuário, com os metadados
 	FindByUserID(ctx context.Context, userID uint) ([]*Book, error)
 
 	// Delete remove um livro (valida ownership)
@@ -20,4 +24,89 @@ type BookRepository interface {
 
 	// UpdateProgress atualiza o progresso de leitura de um livro
 	UpdateProgress(ctx context.Context, id uint, userID uint, currentPage int, progressPercentage float64) error
+
+	// Update salva o título, o arquivo e os metadados de um livro editado. Só
+	// grava se o hash do conteúdo ainda for contentHash (edição concorrente).
+	Update(ctx context.Context, book *Book, contentHash string) error
+
+	// UsageByUser soma a quantidade e o tamanho dos livros por usuário
+	// (userIDs vazio = todos os usuários)
+	UsageByUser(ctx context.Context, userIDs []uint) ([]StorageUsage, error)
+
+	// DeleteByUserID remove definitivamente todos os livros de um usuário
+	DeleteByUserID(ctx context.Context, userID uint) error
+
+	// FindAllFiles busca o arquivo (caminho e hash) de todos os livros não removidos
+	FindAllFiles(ctx context.Context) ([]*Book, error)
+}
+
+// BlobRepository define a interface do repositório de blobs com contagem de referências (port)
+type BlobRepository interface {
+	// Acquire cria o blob com uma referência ou incrementa a contagem se já existir
+	Acquire(ctx context.Context, blob *Blob) (*Blob, error)
+
+	// Release decrementa a contagem de referências. Ao chegar a zero, chama
+	// deleteContent com o blob ainda bloqueado e remove o registro; se
+	// deleteContent falhar, a referência é mantida.
+	Release(ctx context.Context, hash string, deleteContent func(blob *Blob) error) error
+
+	// FindByHash busca um blob pelo hash
+	FindByHash(ctx context.Context, hash string) (*Blob, error)
+
+	// FindAll busca todos os blobs
+	FindAll(ctx context.Context) ([]*Blob, error)
+
+	// DeleteUnreferenced remove um blob que nenhum livro referencia. Com o registro
+	// bloqueado, confere que não há livros com o hash e que o registro não foi
+	// alterado depois de cutoff, chama deleteContent e remove o registro. Se não
+	// houver registro, o conteúdo em storageKey é tratado como órfão. Retorna
+	// false se o blob ainda estiver em uso.
+	DeleteUnreferenced(ctx context.Context, hash, storageKey string, cutoff time.Time, deleteContent func(blob *Blob) error) (bool, error)
+}
+
+// UploadRepository define a interface do repositório de uploads resumíveis (port)
+type UploadRepository interface {
+	// Create registra um novo upload
+	Create(ctx context.Context, upload *Upload) error
+
+	// Update salva o estado do upload
+	Update(ctx context.Context, upload *Upload) error
+
+	// FindByID busca um upload pelo ID e UserID (valida ownership)
+	FindByID(ctx context.Context, id string, userID uint) (*Upload, error)
+
+	// FindExpired busca os uploads cujo prazo terminou
+	FindExpired(ctx context.Context, now time.Time) ([]*Upload, error)
+
+	// Delete remove um upload
+	Delete(ctx context.Context, id string) error
+
+	// FindByUserID busca os uploads de um usuário
+	FindByUserID(ctx context.Context, userID uint) ([]*Upload, error)
+
+	// DeleteByUserID remove todos os uploads de um usuário
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
+
+// UserStorageRepository define a interface do repositório de uso e cota por usuário (port)
+type UserStorageRepository interface {
+	// Get retorna o uso do usuário. O registro é criado na primeira consulta
+	// a partir dos livros já existentes.
+	Get(ctx context.Context, userID uint) (*UserStorage, error)
+
+	// FindByUserIDs busca os registros existentes dos usuários informados
+	FindByUserIDs(ctx context.Context, userIDs []uint) ([]*UserStorage, error)
+
+	// Reserve soma bytes ao uso se couberem na cota (defaultQuota quando o usuário
+	// não tem cota própria; 0 = sem limite). Retorna false se a cota for excedida.
+	Reserve(ctx context.Context, userID uint, bytes int64, defaultQuota int64) (bool, error)
+
+	// Release subtrai bytes do uso
+	Release(ctx context.Context, userID uint, bytes int64) error
+
+	// SetQuota define a cota do usuário (nil volta para a cota padrão)
+	SetQuota(ctx context.Context, userID uint, quotaBytes *int64) error
+
+	// Delete remove o registro do usuário
+	Delete(ctx context.Context, userID uint) error
 }
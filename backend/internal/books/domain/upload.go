@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+)
+
+// Upload representa um upload resumível (protocolo tus) em andamento.
+// Os bytes recebidos ficam em um arquivo parcial até o upload ser concluído,
+// quando o livro é criado e BookID é preenchido.
+type Upload struct {
+	ID        string    `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Filename       string    `gorm:"not null" json:"filename"`
+	Length         int64     `gorm:"not null" json:"length"`           // Tamanho total declarado (Upload-Length)
+	Offset         int64     `gorm:"not null;default:0" json:"offset"` // Bytes já recebidos (Upload-Offset)
+	Metadata       string    `json:"-"`                                // Upload-Metadata original
+	AllowDuplicate bool      `gorm:"not null;default:false" json:"-"`
+	BookID         *uint     `json:"book_id,omitempty"`
+	ExpiresAt      time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// TableName define o nome da tabela no banco de dados
+func (Upload) TableName() string {
+	return "book_uploads"
+}
+
+// IsComplete verifica se todos os bytes foram recebidos
+func (u *Upload) IsComplete() bool {
+	return u.Offset >= u.Length
+}
+
+// IsExpired verifica se o prazo do upload terminou
+func (u *Upload) IsExpired(now time.Time) bool {
+	return now.After(u.ExpiresAt)
+}
@@ -1,11 +1,17 @@
 package http
 
 import (
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"cloud-reader/backend/internal/books/application"
+	"cloud-reader/backend/internal/shared/middleware"
+	"cloud-reader/backend/internal/shared/storage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,29 +28,21 @@ func NewBookHandler(bookService *application.BookService) *BookHandler {
 	}
 }
 
-// getUserID obtém o userID da requisição
-// TODO: Quando JWT for implementado, extrair do token
-// Por enquanto, usa header X-User-ID temporário
+// getUserID obtém o userID do usuário autenticado pelo middleware de autenticação
 func (h *BookHandler) getUserID(c *gin.Context) (uint, error) {
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		return 0, fmt.Errorf("user ID não fornecido")
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return 0, fmt.Errorf("usuário não autenticado")
 	}
-
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		return 0, fmt.Errorf("user ID inválido: %w", err)
-	}
-
-	return uint(userID), nil
+	return userID, nil
 }
 
 // UploadBook lida com upload de livros
 func (h *BookHandler) UploadBook(c *gin.Context) {
 	userID, err := h.getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user ID não fornecido ou inválido",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
 		})
 		return
 	}
@@ -70,9 +68,16 @@ func (h *BookHandler) UploadBook(c *gin.Context) {
 	}
 	defer src.Close()
 
+	// ?allow_duplicate=true mantém uma segunda cópia de um livro já existente
+	allowDuplicate, _ := strconv.ParseBool(c.Query("allow_duplicate"))
+
 	// Faz upload do livro
-	resp, err := h.bookService.UploadBook(c.Request.Context(), userID, file, src)
+	resp, err := h.bookService.UploadBook(c.Request.Context(), userID, file, src, allowDuplicate)
 	if err != nil {
+		if respondBookError(c, err) {
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "arquivo muito grande. Tamanho máximo: 50 MB" ||
 			err.Error() == "tipo de arquivo não permitido. Tipos permitidos: .pdf,.epub,.org" {
@@ -91,8 +96,8 @@ func (h *BookHandler) UploadBook(c *gin.Context) {
 func (h *BookHandler) ListBooks(c *gin.Context) {
 	userID, err := h.getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user ID não fornecido ou inválido",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
 		})
 		return
 	}
@@ -112,8 +117,8 @@ func (h *BookHandler) ListBooks(c *gin.Context) {
 func (h *BookHandler) GetBook(c *gin.Context) {
 	userID, err := h.getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user ID não fornecido ou inválido",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
 		})
 		return
 	}
@@ -142,12 +147,85 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetOutline retorna o sumário de um livro
+func (h *BookHandler) GetOutline(c *gin.Context) {
+	userID, err := h.getUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	resp, err := h.bookService.GetOutline(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "livro não encontrado" {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetOrgDocument retorna um documento Org renderizado em HTML, com a árvore de títulos
+func (h *BookHandler) GetOrgDocument(c *gin.Context) {
+	userID, err := h.getUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	resp, err := h.bookService.GetOrgDocument(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "livro não encontrado", "arquivo não encontrado":
+			statusCode = http.StatusNotFound
+		case "o livro não é um documento Org":
+			statusCode = http.StatusBadRequest
+		case "documento Org grande demais para exibir":
+			statusCode = http.StatusUnprocessableEntity
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // DeleteBook remove um livro
 func (h *BookHandler) DeleteBook(c *gin.Context) {
 	userID, err := h.getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user ID não fornecido ou inválido",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
 		})
 		return
 	}
@@ -181,8 +259,8 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 func (h *BookHandler) DownloadBook(c *gin.Context) {
 	userID, err := h.getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user ID não fornecido ou inválido",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
 		})
 		return
 	}
@@ -196,7 +274,7 @@ func (h *BookHandler) DownloadBook(c *gin.Context) {
 		return
 	}
 
-	filePath, err := h.bookService.GetBookFile(c.Request.Context(), uint(id), userID)
+	file, err := h.bookService.OpenBookFile(c.Request.Context(), uint(id), userID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "livro não encontrado" || err.Error() == "arquivo não encontrado" {
@@ -208,16 +286,102 @@ func (h *BookHandler) DownloadBook(c *gin.Context) {
 		return
 	}
 
-	// Envia o arquivo
-	c.File(filePath)
+	defer file.Content.Close()
+
+	// ?download=true força o download; por padrão o arquivo abre no navegador (pdf.js)
+	disposition := "inline"
+	if download, _ := strconv.ParseBool(c.Query("download")); download {
+		disposition = "attachment"
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", file.ContentType)
+	header.Set("Content-Disposition", contentDisposition(disposition, file.Filename))
+	// O conteúdo é privado e o cliente deve revalidar (barato com ETag/304)
+	header.Set("Cache-Control", "private, no-cache")
+	if file.ContentHash != "" {
+		// ETag forte: o mesmo hash garante os mesmos bytes
+		header.Set("ETag", `"`+file.ContentHash+`"`)
+	}
+
+	// ServeContent trata Range/If-Range, If-None-Match e If-Modified-Since
+	// sobre qualquer armazenamento, pois só depende de Read e Seek
+	http.ServeContent(c.Writer, c.Request, file.Filename, file.ModTime, file.Content)
+}
+
+// GetCover serve a miniatura de capa de um livro (?size=small|medium|large)
+func (h *BookHandler) GetCover(c *gin.Context) {
+	userID, err := h.getUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	cover, err := h.bookService.GetCover(c.Request.Context(), uint(id), userID, c.Query("size"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "livro não encontrado", "capa não encontrada":
+			statusCode = http.StatusNotFound
+		case "tamanho de capa inválido":
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	defer cover.Content.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "image/jpeg")
+	// A miniatura de um conteúdo nunca muda; o cliente pode reaproveitá-la por
+	// um dia sem revalidar e depois revalida com o ETag
+	header.Set("Cache-Control", "private, max-age=86400")
+	header.Set("ETag", `"`+cover.ETag+`"`)
+
+	http.ServeContent(c.Writer, c.Request, "cover.jpg", cover.ModTime, cover.Content)
+}
+
+// GetUsage retorna o uso de armazenamento e a cota do usuário
+func (h *BookHandler) GetUsage(c *gin.Context) {
+	userID, err := h.getUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.bookService.GetUsage(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // UpdateProgress atualiza o progresso de leitura de um livro
 func (h *BookHandler) UpdateProgress(c *gin.Context) {
 	userID, err := h.getUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user ID não fornecido ou inválido",
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
 		})
 		return
 	}
@@ -242,8 +406,11 @@ func (h *BookHandler) UpdateProgress(c *gin.Context) {
 
 	if err := h.bookService.UpdateReadingProgress(c.Request.Context(), uint(id), userID, req.CurrentPage, req.ProgressPercentage); err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "livro não encontrado" {
+		switch err.Error() {
+		case "livro não encontrado":
 			statusCode = http.StatusNotFound
+		case "página atual maior que o total de páginas do livro":
+			statusCode = http.StatusBadRequest
 		}
 		c.JSON(statusCode, gin.H{
 			"error": err.Error(),
@@ -256,3 +423,125 @@ func (h *BookHandler) UpdateProgress(c *gin.Context) {
 	})
 }
 
+// UpdateBook edita o título e os metadados de um livro (com write_back, também
+// no arquivo EPUB)
+func (h *BookHandler) UpdateBook(c *gin.Context) {
+	userID, err := h.getUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var req application.UpdateBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.bookService.UpdateBook(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		if respondBookError(c, err) {
+			return
+		}
+		statusCode := http.StatusInternalServerError
+		switch {
+		case err.Error() == "livro não encontrado", err.Error() == "arquivo não encontrado":
+			statusCode = http.StatusNotFound
+		case err.Error() == "livro alterado por outra requisição":
+			statusCode = http.StatusConflict
+		case strings.HasPrefix(err.Error(), "epub inválido"):
+			statusCode = http.StatusUnprocessableEntity
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// contentDisposition monta o header com o nome original do arquivo. Nomes com
+// caracteres não ASCII usam filename* (RFC 6266) com um filename ASCII de fallback.
+func contentDisposition(disposition, filename string) string {
+	ascii := strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= 0x7f || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+	if ascii == filename {
+		return mime.FormatMediaType(disposition, map[string]string{"filename": filename})
+	}
+	return disposition + `; filename="` + ascii + `"; filename*=UTF-8''` + strings.ReplaceAll(url.QueryEscape(filename), "+", "%20")
+}
+
+// respondBookError responde os erros tipados do BookService: 409 para livro
+// duplicado, 413 para cota excedida e 400 para conteúdo que não corresponde
+// à extensão ou EPUB recusado pela inspeção. Retorna false para os demais erros.
+func respondBookError(c *gin.Context, err error) bool {
+	var duplicate *application.DuplicateBookError
+	if errors.As(err, &duplicate) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":            err.Error(),
+			"existing_book_id": duplicate.ExistingBookID,
+		})
+		return true
+	}
+
+	var quota *application.QuotaExceededError
+	if errors.As(err, &quota) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":           err.Error(),
+			"code":            "quota_exceeded",
+			"quota_bytes":     quota.QuotaBytes,
+			"used_bytes":      quota.UsedBytes,
+			"requested_bytes": quota.RequestedBytes,
+		})
+		return true
+	}
+
+	var mismatch *storage.ContentMismatchError
+	if errors.As(err, &mismatch) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "content_mismatch",
+		})
+		return true
+	}
+
+	var invalid *application.InvalidMetadataError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "invalid_metadata",
+			"field": invalid.Field,
+		})
+		return true
+	}
+
+	var archive *storage.ArchiveError
+	if errors.As(err, &archive) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  archive.Code,
+		})
+		return true
+	}
+
+	return false
+}
@@ -1,20 +1,59 @@
 package http
 
 import (
+	"cloud-reader/backend/internal/shared/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
-// RegisterRoutes registra todas as rotas de livros no router
-func RegisterRoutes(router *gin.RouterGroup, handler *BookHandler) {
-	books := router.Group("/books")
+// Escopos exigidos das chaves de API
+const (
+	booksRead     = "books:read"
+	booksWrite    = "books:write"
+	progressWrite = "progress:write"
+)
+
+// RegisterRoutes registra todas as rotas de livros no router.
+// Todas as rotas exigem autenticação via authMiddleware; chaves de API precisam
+// do escopo correspondente.
+func RegisterRoutes(router *gin.RouterGroup, handler *BookHandler, authMiddleware gin.HandlerFunc) {
+	books := router.Group("/books", authMiddleware)
 	{
-		books.POST("/upload", handler.UploadBook)
-		books.GET("", handler.ListBooks)
+		read := middleware.RequireScope(booksRead)
+		write := middleware.RequireScope(booksWrite)
+		progress := middleware.RequireScope(progressWrite)
+
+		books.POST("/upload", write, handler.UploadBook)
+		books.GET("", read, handler.ListBooks)
 		// Rotas específicas devem vir antes das rotas com parâmetros genéricos
-		books.GET("/:id/download", handler.DownloadBook)
-		books.PUT("/:id/progress", handler.UpdateProgress)
+		books.GET("/:id/download", read, handler.DownloadBook)
+		books.HEAD("/:id/download", read, handler.DownloadBook)
+		books.GET("/:id/cover", read, handler.GetCover)
+		books.GET("/:id/outline", read, handler.GetOutline)
+		books.GET("/:id/html", read, handler.GetOrgDocument)
+		books.PUT("/:id/progress", progress, handler.UpdateProgress)
 		// Rotas genéricas por último
-		books.GET("/:id", handler.GetBook)
-		books.DELETE("/:id", handler.DeleteBook)
+		books.GET("/:id", read, handler.GetBook)
+		books.PATCH("/:id", write, handler.UpdateBook)
+		books.DELETE("/:id", write, handler.DeleteBook)
+	}
+
+	// Uso de armazenamento e cota do usuário
+	router.GET("/me/usage", authMiddleware, middleware.RequireScope(booksRead), handler.GetUsage)
+}
+
+// RegisterUploadRoutes registra as rotas de upload resumível (protocolo tus 1.0).
+// O OPTIONS de descoberta é público; as demais rotas exigem autenticação e o
+// header Tus-Resumable.
+func RegisterUploadRoutes(router *gin.RouterGroup, handler *UploadHandler, authMiddleware gin.HandlerFunc) {
+	router.OPTIONS("/uploads", handler.Options)
+	router.OPTIONS("/uploads/:id", handler.Options)
+
+	uploads := router.Group("/uploads", authMiddleware, middleware.RequireScope(booksWrite), requireTusResumable())
+	{
+		uploads.POST("", handler.CreateUpload)
+		uploads.HEAD("/:id", handler.GetUpload)
+		uploads.PATCH("/:id", handler.WriteChunk)
+		uploads.DELETE("/:id", handler.DeleteUpload)
 	}
 }
@@ -0,0 +1,329 @@
+package http
+
+import (
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cloud-reader/backend/internal/books/application"
+	"cloud-reader/backend/internal/shared/config"
+	"cloud-reader/backend/internal/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// tusVersion é a versão do protocolo tus implementada
+	tusVersion = "1.0.0"
+
+	// tusExtensions são as extensões do protocolo suportadas
+	tusExtensions = "creation,termination,expiration"
+
+	// tusContentType é o Content-Type exigido no PATCH
+	tusContentType = "application/offset+octet-stream"
+)
+
+// UploadHandler gerencia os handlers HTTP dos uploads resumíveis (protocolo tus 1.0)
+type UploadHandler struct {
+	uploadService  *application.UploadService
+	trustedProxies []*net.IPNet // Proxies cujo X-Forwarded-Proto é aceito (TRUSTED_PROXIES)
+}
+
+// NewUploadHandler cria uma nova instância do UploadHandler
+func NewUploadHandler(uploadService *application.UploadService, cfg *config.Config) *UploadHandler {
+	return &UploadHandler{
+		uploadService:  uploadService,
+		trustedProxies: parseTrustedProxies(cfg.TrustedProxies),
+	}
+}
+
+// Options informa as capacidades do servidor tus
+func (h *UploadHandler) Options(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.FormatInt(h.uploadService.MaxSize(), 10))
+	c.Status(http.StatusNoContent)
+}
+
+// CreateUpload cria um upload a partir de Upload-Length e Upload-Metadata
+// (o metadado filename é obrigatório)
+func (h *UploadHandler) CreateUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	if c.GetHeader("Upload-Defer-Length") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Upload-Defer-Length não é suportado",
+		})
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Upload-Length inválido",
+		})
+		return
+	}
+
+	rawMetadata := c.GetHeader("Upload-Metadata")
+	metadata, err := parseUploadMetadata(rawMetadata)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Upload-Metadata inválido",
+		})
+		return
+	}
+
+	// A cópia duplicada pode ser pedida pela query (como no upload multipart) ou pelo metadado
+	allowDuplicate, _ := strconv.ParseBool(c.Query("allow_duplicate"))
+	if value, ok := metadata["allow_duplicate"]; ok {
+		allowDuplicate, _ = strconv.ParseBool(value)
+	}
+
+	resp, err := h.uploadService.CreateUpload(c.Request.Context(), userID, &application.CreateUploadRequest{
+		Length:         length,
+		Filename:       metadata["filename"],
+		Metadata:       rawMetadata,
+		AllowDuplicate: allowDuplicate,
+	})
+	if err != nil {
+		if respondBookError(c, err) {
+			return
+		}
+		c.JSON(uploadErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", h.uploadLocation(c, resp.ID))
+	c.Header("Upload-Expires", resp.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// GetUpload informa o offset atual para que o cliente retome o envio
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+
+	resp, err := h.uploadService.GetUpload(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		// Respostas de HEAD não têm corpo
+		c.Status(uploadErrorStatus(err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	setUploadHeaders(c, resp)
+	c.Header("Upload-Length", strconv.FormatInt(resp.Length, 10))
+	if resp.Metadata != "" {
+		c.Header("Upload-Metadata", resp.Metadata)
+	}
+	c.Status(http.StatusOK)
+}
+
+// WriteChunk recebe um trecho do arquivo a partir de Upload-Offset
+func (h *UploadHandler) WriteChunk(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	if c.ContentType() != tusContentType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Content-Type deve ser " + tusContentType,
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Upload-Offset inválido",
+		})
+		return
+	}
+
+	resp, err := h.uploadService.WriteChunk(c.Request.Context(), userID, c.Param("id"), offset, c.Request.Body)
+	if err != nil {
+		if respondBookError(c, err) {
+			return
+		}
+
+		c.JSON(uploadErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	setUploadHeaders(c, resp)
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUpload cancela um upload (extensão termination)
+func (h *UploadHandler) DeleteUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	if err := h.uploadService.DeleteUpload(c.Request.Context(), userID, c.Param("id")); err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireTusResumable exige o header Tus-Resumable com a versão suportada
+// e o inclui em todas as respostas
+func requireTusResumable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", tusVersion)
+
+		if c.GetHeader("Tus-Resumable") != tusVersion {
+			c.Header("Tus-Version", tusVersion)
+			c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+				"error": "versão do protocolo tus não suportada",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setUploadHeaders define o offset, o prazo e, se concluído, o livro criado
+func setUploadHeaders(c *gin.Context, resp *application.UploadResponse) {
+	c.Header("Upload-Offset", strconv.FormatInt(resp.Offset, 10))
+	c.Header("Upload-Expires", resp.ExpiresAt.UTC().Format(http.TimeFormat))
+	if resp.BookID != nil {
+		c.Header("X-Book-ID", strconv.FormatUint(uint64(*resp.BookID), 10))
+	}
+}
+
+// uploadLocation retorna a URL do upload criado. X-Forwarded-Proto só é aceito
+// de um proxy confiável (as mesmas regras do IP do cliente); nos demais casos
+// sem TLS a URL é relativa e o cliente a resolve pela URL que usou.
+func (h *UploadHandler) uploadLocation(c *gin.Context, id string) string {
+	location := strings.TrimSuffix(c.Request.URL.Path, "/") + "/" + id
+
+	scheme := ""
+	switch {
+	case h.isTrustedProxy(c.RemoteIP()):
+		proto, _, _ := strings.Cut(c.GetHeader("X-Forwarded-Proto"), ",")
+		switch proto = strings.ToLower(strings.TrimSpace(proto)); proto {
+		case "http", "https":
+			scheme = proto
+		}
+	case c.Request.TLS != nil:
+		scheme = "https"
+	}
+	if scheme == "" {
+		return location
+	}
+	return scheme + "://" + c.Request.Host + location
+}
+
+// isTrustedProxy informa se a conexão parte de um proxy listado em TRUSTED_PROXIES
+func (h *UploadHandler) isTrustedProxy(remoteIP string) bool {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range h.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies converte os IPs e CIDRs de TRUSTED_PROXIES. Entradas
+// inválidas são ignoradas: o servidor já recusa a configuração na inicialização.
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// parseUploadMetadata decodifica o header Upload-Metadata
+// ("chave valorBase64,chave valorBase64"; o valor pode ser omitido)
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.Fields(pair)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, errors.New("metadado inválido")
+		}
+
+		value := ""
+		if len(parts) == 2 {
+			decoded, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			value = string(decoded)
+		}
+		metadata[parts[0]] = value
+	}
+
+	return metadata, nil
+}
+
+// uploadErrorStatus converte os erros do UploadService em status HTTP
+func uploadErrorStatus(err error) int {
+	switch err.Error() {
+	case "upload não encontrado":
+		return http.StatusNotFound
+	case "upload expirado":
+		return http.StatusGone
+	case "offset do upload não confere":
+		return http.StatusConflict
+	case "upload em uso por outra requisição":
+		return http.StatusLocked
+	case "upload maior que o tamanho máximo permitido":
+		return http.StatusRequestEntityTooLarge
+	case "tamanho do upload inválido", "nome do arquivo não informado",
+		"tipo de arquivo não permitido. Tipos permitidos: .pdf,.epub,.org":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
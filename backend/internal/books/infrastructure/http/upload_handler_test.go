@@ -0,0 +1,49 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUploadLocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &UploadHandler{trustedProxies: parseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16", "::1"})}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		proto      string
+		tls        bool
+		want       string
+	}{
+		{name: "cliente direto", remoteAddr: "203.0.113.5:4000", want: "/api/v1/uploads/abc"},
+		{name: "cliente forja o esquema", remoteAddr: "203.0.113.5:4000", proto: "https", want: "/api/v1/uploads/abc"},
+		{name: "TLS direto", remoteAddr: "203.0.113.5:4000", tls: true, want: "https://reader.example.com/api/v1/uploads/abc"},
+		{name: "proxy confiável", remoteAddr: "10.0.0.1:4000", proto: "https", want: "https://reader.example.com/api/v1/uploads/abc"},
+		{name: "proxy em CIDR", remoteAddr: "192.168.3.4:4000", proto: "http", want: "http://reader.example.com/api/v1/uploads/abc"},
+		{name: "proxy IPv6", remoteAddr: "[::1]:4000", proto: "HTTPS, http", want: "https://reader.example.com/api/v1/uploads/abc"},
+		{name: "proxy sem esquema", remoteAddr: "10.0.0.1:4000", want: "/api/v1/uploads/abc"},
+		{name: "proxy com esquema inválido", remoteAddr: "10.0.0.1:4000", proto: "javascript", want: "/api/v1/uploads/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "http://reader.example.com/api/v1/uploads/", nil)
+			c.Request.RemoteAddr = tt.remoteAddr
+			if tt.proto != "" {
+				c.Request.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			if tt.tls {
+				c.Request.TLS = &tls.ConnectionState{}
+			}
+
+			if got := h.uploadLocation(c, "abc"); got != tt.want {
+				t.Errorf("uploadLocation = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresBlobRepository implementa BlobRepository usando PostgreSQL/GORM
+type postgresBlobRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresBlobRepository cria uma nova instância do repositório de blobs
+func NewPostgresBlobRepository(db *gorm.DB) domain.BlobRepository {
+	return &postgresBlobRepository{
+		db: db,
+	}
+}
+
+// Acquire cria o blob ou incrementa a contagem de referências em uma única instrução,
+// para que uploads simultâneos do mesmo conteúdo não percam referências
+func (r *postgresBlobRepository) Acquire(ctx context.Context, blob *domain.Blob) (*domain.Blob, error) {
+	acquired := &domain.Blob{
+		Hash:       blob.Hash,
+		StorageKey: blob.StorageKey,
+		Size:       blob.Size,
+		RefCount:   1,
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "hash"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"ref_count":  gorm.Expr("blobs.ref_count + 1"),
+					"updated_at": time.Now(),
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(acquired).Error
+	if err != nil {
+		return nil, err
+	}
+	return acquired, nil
+}
+
+// Release decrementa a contagem de referências com o registro bloqueado (SELECT ... FOR UPDATE).
+// Um Acquire simultâneo espera o fim da transação, então o conteúdo nunca é removido
+// enquanto outro livro passa a referenciá-lo.
+func (r *postgresBlobRepository) Release(ctx context.Context, hash string, deleteContent func(blob *domain.Blob) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var blob domain.Blob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(&blob).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if blob.RefCount > 1 {
+			return tx.Model(&domain.Blob{}).
+				Where("hash = ?", hash).
+				Updates(map[string]interface{}{
+					"ref_count":  gorm.Expr("ref_count - 1"),
+					"updated_at": time.Now(),
+				}).Error
+		}
+
+		// Última referência: remove o conteúdo antes do registro
+		if err := deleteContent(&blob); err != nil {
+			return err
+		}
+		return tx.Where("hash = ?", hash).Delete(&domain.Blob{}).Error
+	})
+}
+
+// FindByHash busca um blob pelo hash
+func (r *postgresBlobRepository) FindByHash(ctx context.Context, hash string) (*domain.Blob, error) {
+	var blob domain.Blob
+	if err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&blob).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("blob não encontrado")
+		}
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// FindAll busca todos os blobs
+func (r *postgresBlobRepository) FindAll(ctx context.Context) ([]*domain.Blob, error) {
+	var blobs []*domain.Blob
+	if err := r.db.WithContext(ctx).Order("hash").Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+// DeleteUnreferenced remove um blob sem livros com o registro bloqueado, como no Release.
+// Se o registro não existir, um provisório é inserido para que um Acquire simultâneo
+// do mesmo hash espere a remoção do conteúdo e depois grave o arquivo de novo.
+func (r *postgresBlobRepository) DeleteUnreferenced(ctx context.Context, hash, storageKey string, cutoff time.Time, deleteContent func(blob *domain.Blob) error) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		inserted := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.Blob{
+			Hash:       hash,
+			StorageKey: storageKey,
+		})
+		if inserted.Error != nil {
+			return inserted.Error
+		}
+
+		var blob domain.Blob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(&blob).Error; err != nil {
+			return err
+		}
+		// Um registro alterado recentemente pode ser de um upload ainda em andamento
+		if inserted.RowsAffected == 0 && blob.UpdatedAt.After(cutoff) {
+			return nil
+		}
+
+		var books int64
+		if err := tx.Model(&domain.Book{}).Where("content_hash = ?", hash).Count(&books).Error; err != nil {
+			return err
+		}
+		if books > 0 {
+			return nil
+		}
+
+		if err := deleteContent(&blob); err != nil {
+			return err
+		}
+		if err := tx.Where("hash = ?", hash).Delete(&domain.Blob{}).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
@@ -6,6 +6,7 @@ import (
 
 	"cloud-reader/backend/internal/books/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // postgresBookRepository implementa BookRepository usando PostgreSQL/GORM
@@ -20,9 +21,34 @@ func NewPostgresBookRepository(db *gorm.DB) domain.BookRepository {
 	}
 }
 
+// EnsureContentIndex cria o índice único por usuário e conteúdo, que impede
+// que dois uploads simultâneos do mesmo arquivo criem dois livros. Cópias
+// anteriores ao índice são marcadas como duplicadas, mantendo o livro mais antigo.
+func EnsureContentIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE books SET duplicate = TRUE
+			WHERE deleted_at IS NULL AND NOT duplicate AND content_hash <> ''
+			AND id NOT IN (
+				SELECT MIN(id) FROM books
+				WHERE deleted_at IS NULL AND NOT duplicate AND content_hash <> ''
+				GROUP BY user_id, content_hash
+			)`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_books_user_content
+			ON books (user_id, content_hash)
+			WHERE deleted_at IS NULL AND NOT duplicate AND content_hash <> ''`).Error
+	})
+}
+
 // Create cria um novo livro
 func (r *postgresBookRepository) Create(ctx context.Context, book *domain.Book) error {
 	if err := r.db.WithContext(ctx).Create(book).Error; err != nil {
+		if r.isDuplicateKey(err) {
+			return domain.ErrDuplicateBook
+		}
 		return err
 	}
 	return nil
@@ -31,9 +57,24 @@ func (r *postgresBookRepository) Create(ctx context.Context, book *domain.Book)
 // FindByID busca um livro pelo ID e UserID (valida ownership)
 func (r *postgresBookRepository) FindByID(ctx context.Context, id uint, userID uint) (*domain.Book, error) {
 	var book domain.Book
-	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&book).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Metadata").Where("id = ? AND user_id = ?", id, userID).First(&book).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("livro não encontrado")
+			return nil, domain.ErrBookNotFound
+		}
+		return nil, err
+	}
+	return &book, nil
+}
+
+// FindByUserAndHash busca o livro mais antigo do usuário com o conteúdo informado
+func (r *postgresBookRepository) FindByUserAndHash(ctx context.Context, userID uint, contentHash string) (*domain.Book, error) {
+	var book domain.Book
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND content_hash = ?", userID, contentHash).
+		Order("created_at ASC").
+		First(&book).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrBookNotFound
 		}
 		return nil, err
 	}
@@ -43,7 +84,9 @@ func (r *postgresBookRepository) FindByID(ctx context.Context, id uint, userID u
 // FindByUserID busca todos os livros de um usuário
 func (r *postgresBookRepository) FindByUserID(ctx context.Context, userID uint) ([]*domain.Book, error) {
 	var books []*domain.Book
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&books).Error; err != nil {
+	// O sumário não aparece na listagem e pode ser grande; fica fora da consulta
+	omitOutline := func(db *gorm.DB) *gorm.DB { return db.Omit("outline") }
+	if err := r.db.WithContext(ctx).Preload("Metadata", omitOutline).Where("user_id = ?", userID).Order("created_at DESC").Find(&books).Error; err != nil {
 		return nil, err
 	}
 	return books, nil
@@ -56,7 +99,7 @@ func (r *postgresBookRepository) Delete(ctx context.Context, id uint, userID uin
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("livro não encontrado")
+		return domain.ErrBookNotFound
 	}
 	return nil
 }
@@ -75,8 +118,76 @@ func (r *postgresBookRepository) UpdateProgress(ctx context.Context, id uint, us
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("livro não encontrado")
+		return domain.ErrBookNotFound
 	}
 	return nil
 }
 
+// Update salva o título, o arquivo e os metadados de um livro editado
+func (r *postgresBookRepository) Update(ctx context.Context, book *domain.Book, contentHash string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Book{}).
+			Where("id = ? AND user_id = ? AND COALESCE(content_hash, '') = ?", book.ID, book.UserID, contentHash).
+			Updates(map[string]interface{}{
+				"title":        book.Title,
+				"file_path":    book.FilePath,
+				"content_hash": book.ContentHash,
+				"file_size":    book.FileSize,
+				"mime_type":    book.MimeType,
+				"has_cover":    book.HasCover,
+			})
+		if result.Error != nil {
+			if r.isDuplicateKey(result.Error) {
+				return domain.ErrDuplicateBook
+			}
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("livro alterado por outra requisição")
+		}
+
+		if book.Metadata == nil {
+			return nil
+		}
+		book.Metadata.BookID = book.ID
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(book.Metadata).Error
+	})
+}
+
+// UsageByUser soma a quantidade e o tamanho dos livros por usuário
+func (r *postgresBookRepository) UsageByUser(ctx context.Context, userIDs []uint) ([]domain.StorageUsage, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.Book{}).
+		Select("user_id, COUNT(*) AS books, COALESCE(SUM(file_size), 0) AS bytes").
+		Group("user_id").
+		Order("bytes DESC")
+	if len(userIDs) > 0 {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+
+	var usage []domain.StorageUsage
+	if err := query.Scan(&usage).Error; err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// DeleteByUserID remove definitivamente todos os livros de um usuário
+func (r *postgresBookRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&domain.Book{}).Error
+}
+
+// FindAllFiles busca o arquivo (caminho e hash) de todos os livros não removidos
+func (r *postgresBookRepository) FindAllFiles(ctx context.Context) ([]*domain.Book, error) {
+	var books []*domain.Book
+	if err := r.db.WithContext(ctx).Select("id", "user_id", "file_path", "content_hash").Order("id").Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// isDuplicateKey informa se o erro é uma violação de índice único
+func (r *postgresBookRepository) isDuplicateKey(err error) bool {
+	translator, ok := r.db.Dialector.(gorm.ErrorTranslator)
+	return ok && errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey)
+}
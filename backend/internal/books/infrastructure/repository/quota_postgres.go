@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"gorm.io/gorm"
+)
+
+// postgresUserStorageRepository implementa UserStorageRepository usando PostgreSQL/GORM
+type postgresUserStorageRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUserStorageRepository cria uma nova instância do repositório de uso e cota
+func NewPostgresUserStorageRepository(db *gorm.DB) domain.UserStorageRepository {
+	return &postgresUserStorageRepository{
+		db: db,
+	}
+}
+
+// Get retorna o uso do usuário, criando o registro se necessário
+func (r *postgresUserStorageRepository) Get(ctx context.Context, userID uint) (*domain.UserStorage, error) {
+	if err := r.ensure(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var usage domain.UserStorage
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&usage).Error; err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// FindByUserIDs busca os registros existentes dos usuários informados
+func (r *postgresUserStorageRepository) FindByUserIDs(ctx context.Context, userIDs []uint) ([]*domain.UserStorage, error) {
+	var usage []*domain.UserStorage
+	if len(userIDs) == 0 {
+		return usage, nil
+	}
+	if err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&usage).Error; err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// Reserve soma bytes ao uso em uma única instrução condicional, para que
+// uploads simultâneos não ultrapassem a cota
+func (r *postgresUserStorageRepository) Reserve(ctx context.Context, userID uint, bytes int64, defaultQuota int64) (bool, error) {
+	if err := r.ensure(ctx, userID); err != nil {
+		return false, err
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserStorage{}).
+		Where("user_id = ?", userID).
+		Where("COALESCE(quota_bytes, ?) = 0 OR used_bytes + ? <= COALESCE(quota_bytes, ?)", defaultQuota, bytes, defaultQuota).
+		Updates(map[string]interface{}{
+			"used_bytes": gorm.Expr("used_bytes + ?", bytes),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release subtrai bytes do uso (sem ficar negativo)
+func (r *postgresUserStorageRepository) Release(ctx context.Context, userID uint, bytes int64) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.UserStorage{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"used_bytes": gorm.Expr("GREATEST(used_bytes - ?, 0)", bytes),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// SetQuota define a cota do usuário
+func (r *postgresUserStorageRepository) SetQuota(ctx context.Context, userID uint, quotaBytes *int64) error {
+	if err := r.ensure(ctx, userID); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&domain.UserStorage{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"quota_bytes": quotaBytes,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+// Delete remove o registro do usuário
+func (r *postgresUserStorageRepository) Delete(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.UserStorage{}).Error
+}
+
+// ensure cria o registro do usuário com o tamanho dos livros que ele já tem
+// (usuários anteriores ao controle de cota)
+func (r *postgresUserStorageRepository) ensure(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO user_storage (user_id, used_bytes, updated_at)
+		SELECT ?, COALESCE(SUM(file_size), 0), NOW()
+		FROM books
+		WHERE user_id = ? AND deleted_at IS NULL
+		ON CONFLICT (user_id) DO NOTHING`, userID, userID).Error
+}
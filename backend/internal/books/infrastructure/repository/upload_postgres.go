@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/books/domain"
+	"gorm.io/gorm"
+)
+
+// postgresUploadRepository implementa UploadRepository usando PostgreSQL/GORM
+type postgresUploadRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresUploadRepository cria uma nova instância do repositório de uploads
+func NewPostgresUploadRepository(db *gorm.DB) domain.UploadRepository {
+	return &postgresUploadRepository{
+		db: db,
+	}
+}
+
+// Create registra um novo upload
+func (r *postgresUploadRepository) Create(ctx context.Context, upload *domain.Upload) error {
+	return r.db.WithContext(ctx).Create(upload).Error
+}
+
+// Update salva o estado do upload
+func (r *postgresUploadRepository) Update(ctx context.Context, upload *domain.Upload) error {
+	return r.db.WithContext(ctx).Save(upload).Error
+}
+
+// FindByID busca um upload pelo ID e UserID (valida ownership)
+func (r *postgresUploadRepository) FindByID(ctx context.Context, id string, userID uint) (*domain.Upload, error) {
+	var upload domain.Upload
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&upload).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("upload não encontrado")
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// FindExpired busca os uploads cujo prazo terminou
+func (r *postgresUploadRepository) FindExpired(ctx context.Context, now time.Time) ([]*domain.Upload, error) {
+	var uploads []*domain.Upload
+	if err := r.db.WithContext(ctx).Where("expires_at < ?", now).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// Delete remove um upload
+func (r *postgresUploadRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.Upload{}).Error
+}
+
+// FindByUserID busca os uploads de um usuário
+func (r *postgresUploadRepository) FindByUserID(ctx context.Context, userID uint) ([]*domain.Upload, error) {
+	var uploads []*domain.Upload
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// DeleteByUserID remove todos os uploads de um usuário
+func (r *postgresUploadRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.Upload{}).Error
+}
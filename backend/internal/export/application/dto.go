@@ -0,0 +1,18 @@
+package application
+
+// ExportJobResponse representa o estado de uma exportação
+type ExportJobResponse struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	FileSize    int64  `json:"file_size,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"` // Preenchido enquanto o download estiver disponível
+}
+
+// ListExportsResponse representa a lista de exportações do usuário
+type ListExportsResponse struct {
+	Exports []ExportJobResponse `json:"exports"`
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	authApplication "cloud-reader/backend/internal/auth/application"
+	bookApplication "cloud-reader/backend/internal/books/application"
+	"cloud-reader/backend/internal/export/domain"
+	"cloud-reader/backend/internal/shared/config"
+)
+
+// newTestExportService cria um ExportService sobre fakes, gerando os arquivos em um diretório temporário
+func newTestExportService(t *testing.T, repo *fakeExportRepo, library *fakeLibrary) *ExportService {
+	t.Helper()
+	return NewExportService(repo, fakeAccounts{}, library, &config.Config{
+		ExportDir: t.TempDir(),
+		ExportTTL: time.Hour,
+	})
+}
+
+// fakeExportRepo guarda as exportações em memória e, como o índice único
+// parcial do PostgreSQL, aceita uma só exportação ativa por usuário
+type fakeExportRepo struct {
+	mu   sync.Mutex
+	jobs map[string]domain.ExportJob
+}
+
+func newFakeExportRepo() *fakeExportRepo {
+	return &fakeExportRepo{jobs: make(map[string]domain.ExportJob)}
+}
+
+func (r *fakeExportRepo) Create(ctx context.Context, job *domain.ExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, other := range r.jobs {
+		if other.UserID == job.UserID && other.IsActive() {
+			return domain.ErrExportInProgress
+		}
+	}
+	job.CreatedAt = time.Now()
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *fakeExportRepo) Update(ctx context.Context, job *domain.ExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *fakeExportRepo) FindByID(ctx context.Context, id string, userID uint) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.UserID != userID {
+		return nil, errors.New("exportação não encontrada")
+	}
+	return &job, nil
+}
+
+func (r *fakeExportRepo) FindByUserID(ctx context.Context, userID uint) ([]*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []*domain.ExportJob
+	for _, job := range r.jobs {
+		if job.UserID == userID {
+			job := job
+			jobs = append(jobs, &job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs, nil
+}
+
+func (r *fakeExportRepo) FindExpired(ctx context.Context, now time.Time) ([]*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []*domain.ExportJob
+	for _, job := range r.jobs {
+		if job.ExpiresAt != nil && job.ExpiresAt.Before(now) {
+			job := job
+			jobs = append(jobs, &job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeExportRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.jobs, id)
+	return nil
+}
+
+func (r *fakeExportRepo) FailUnfinished(ctx context.Context, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, job := range r.jobs {
+		if job.IsActive() {
+			job.Status = domain.ExportStatusFailed
+			job.Error = reason
+			r.jobs[id] = job
+		}
+	}
+	return nil
+}
+
+// fakeAccounts devolve um perfil fixo para qualquer usuário
+type fakeAccounts struct{}
+
+func (fakeAccounts) GetProfile(ctx context.Context, userID uint) (*authApplication.ProfileResponse, error) {
+	return &authApplication.ProfileResponse{
+		UserResponse: authApplication.UserResponse{ID: userID, Email: "ana@example.com"},
+	}, nil
+}
+
+// fakeLibrary serve livros em memória. Com gate definido, OpenBookFile avisa
+// em opened e só devolve o arquivo quando gate é fechado (ou o contexto termina).
+type fakeLibrary struct {
+	books  map[uint][]byte
+	gate   chan struct{}
+	opened chan struct{}
+}
+
+func (l *fakeLibrary) ListBooks(ctx context.Context, userID uint) (*bookApplication.ListBooksResponse, error) {
+	resp := &bookApplication.ListBooksResponse{}
+	for id, content := range l.books {
+		resp.Books = append(resp.Books, bookApplication.BookResponse{
+			ID:       id,
+			UserID:   userID,
+			Title:    "Livro",
+			Filename: "livro.pdf",
+			Format:   "pdf",
+			FileSize: int64(len(content)),
+		})
+	}
+	resp.Total = len(resp.Books)
+	return resp, nil
+}
+
+func (l *fakeLibrary) OpenBookFile(ctx context.Context, id uint, userID uint) (*bookApplication.BookFile, error) {
+	if l.gate != nil {
+		select {
+		case l.opened <- struct{}{}:
+		default:
+		}
+		select {
+		case <-l.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	content, ok := l.books[id]
+	if !ok {
+		return nil, errors.New("arquivo não encontrado")
+	}
+	return &bookApplication.BookFile{
+		Content:  nopCloser{bytes.NewReader(content)},
+		Filename: "livro.pdf",
+		Format:   "pdf",
+		Size:     int64(len(content)),
+	}, nil
+}
+
+type nopCloser struct{ *bytes.Reader }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,27 @@
+package application
+
+import (
+	authApplication "cloud-reader/backend/internal/auth/application"
+)
+
+// exportManifest é o conteúdo do manifest.json incluído no arquivo exportado
+type exportManifest struct {
+	ExportedAt string                           `json:"exported_at"`
+	Account    *authApplication.ProfileResponse `json:"account"`
+	Books      []manifestBook                   `json:"books"`
+}
+
+// manifestBook descreve um livro e o progresso de leitura no manifest
+type manifestBook struct {
+	ID                 uint    `json:"id"`
+	Title              string  `json:"title"`
+	Filename           string  `json:"filename"`
+	Format             string  `json:"format"`
+	FileSize           int64   `json:"file_size"`
+	CurrentPage        int     `json:"current_page"`
+	ProgressPercentage float64 `json:"progress_percentage"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
+	File               string  `json:"file,omitempty"`         // Caminho do arquivo dentro do zip
+	FileMissing        bool    `json:"file_missing,omitempty"` // Arquivo não encontrado no armazenamento
+}
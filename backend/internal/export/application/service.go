@@ -0,0 +1,462 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	authApplication "cloud-reader/backend/internal/auth/application"
+	bookApplication "cloud-reader/backend/internal/books/application"
+	"cloud-reader/backend/internal/export/domain"
+	"cloud-reader/backend/internal/shared/config"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxConcurrentExports limita quantas exportações são geradas ao mesmo tempo
+	maxConcurrentExports = 2
+
+	// exportTimeout é o tempo máximo de geração de uma exportação
+	exportTimeout = 30 * time.Minute
+
+	// exportCleanupInterval é o intervalo da remoção de exportações expiradas
+	exportCleanupInterval = time.Hour
+)
+
+// AccountReader é a parte do serviço de autenticação usada na exportação
+type AccountReader interface {
+	GetProfile(ctx context.Context, userID uint) (*authApplication.ProfileResponse, error)
+}
+
+// LibraryReader é a parte do serviço de livros usada na exportação
+type LibraryReader interface {
+	ListBooks(ctx context.Context, userID uint) (*bookApplication.ListBooksResponse, error)
+	OpenBookFile(ctx context.Context, id uint, userID uint) (*bookApplication.BookFile, error)
+}
+
+// ExportService define os casos de uso da exportação completa da conta.
+// O arquivo zip é gerado em segundo plano e fica disponível por tempo limitado.
+type ExportService struct {
+	exportRepo  domain.ExportRepository
+	authService AccountReader
+	bookService LibraryReader
+
+	exportDir string
+	ttl       time.Duration
+	slots     chan struct{}
+
+	mu     sync.Mutex
+	active map[uint]*activeExport // Exportação em andamento de cada usuário
+}
+
+// activeExport permite cancelar uma exportação em andamento e esperar seu fim
+type activeExport struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewExportService cria uma nova instância do ExportService
+func NewExportService(
+	exportRepo domain.ExportRepository,
+	authService AccountReader,
+	bookService LibraryReader,
+	cfg *config.Config,
+) *ExportService {
+	return &ExportService{
+		exportRepo:  exportRepo,
+		authService: authService,
+		bookService: bookService,
+		exportDir:   cfg.ExportDir,
+		ttl:         cfg.ExportTTL,
+		slots:       make(chan struct{}, maxConcurrentExports),
+		active:      make(map[uint]*activeExport),
+	}
+}
+
+// Start marca como falhas as exportações interrompidas por um reinício e inicia a
+// remoção periódica das exportações expiradas
+func (s *ExportService) Start(ctx context.Context) {
+	if err := s.exportRepo.FailUnfinished(ctx, "exportação interrompida"); err != nil {
+		log.Printf("Erro ao marcar exportações interrompidas: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(exportCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			s.deleteExpired(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RequestExport agenda a exportação dos dados do usuário. O índice único de
+// exportações ativas garante uma só por usuário, mesmo com pedidos simultâneos
+// (domain.ErrExportInProgress).
+func (s *ExportService) RequestExport(ctx context.Context, userID uint) (*ExportJobResponse, error) {
+	// Registrada antes do registro no banco, para que uma remoção simultânea
+	// dos dados do usuário a encontre
+	runCtx, cancel := context.WithCancel(context.Background())
+	active := &activeExport{cancel: cancel, done: make(chan struct{})}
+	s.mu.Lock()
+	if s.active[userID] != nil {
+		s.mu.Unlock()
+		cancel()
+		return nil, domain.ErrExportInProgress
+	}
+	s.active[userID] = active
+	s.mu.Unlock()
+
+	job := &domain.ExportJob{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		Status: domain.ExportStatusPending,
+	}
+	if err := s.exportRepo.Create(ctx, job); err != nil {
+		s.finish(userID, active)
+		return nil, err
+	}
+
+	resp := toExportJobResponse(job, time.Now())
+	go s.run(runCtx, job, active)
+
+	return resp, nil
+}
+
+// ListExports lista as exportações do usuário
+func (s *ExportService) ListExports(ctx context.Context, userID uint) (*ListExportsResponse, error) {
+	jobs, err := s.exportRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]ExportJobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = *toExportJobResponse(job, now)
+	}
+
+	return &ListExportsResponse{
+		Exports: responses,
+	}, nil
+}
+
+// GetExport retorna o estado de uma exportação
+func (s *ExportService) GetExport(ctx context.Context, id string, userID uint) (*ExportJobResponse, error) {
+	job, err := s.exportRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, errors.New("exportação não encontrada")
+	}
+	return toExportJobResponse(job, time.Now()), nil
+}
+
+// GetExportFile retorna o caminho do arquivo para download, se ainda estiver disponível
+func (s *ExportService) GetExportFile(ctx context.Context, id string, userID uint) (string, error) {
+	job, err := s.exportRepo.FindByID(ctx, id, userID)
+	if err != nil {
+		return "", errors.New("exportação não encontrada")
+	}
+	if !job.IsDownloadable(time.Now()) {
+		return "", errors.New("exportação indisponível ou expirada")
+	}
+	if _, err := os.Stat(job.FilePath); err != nil {
+		return "", errors.New("exportação indisponível ou expirada")
+	}
+	return job.FilePath, nil
+}
+
+// DeleteUserExports remove as exportações de um usuário e seus arquivos. Uma
+// exportação em andamento é cancelada antes, e a remoção espera o seu fim para
+// que ela não grave o arquivo nem o registro depois.
+func (s *ExportService) DeleteUserExports(ctx context.Context, userID uint) error {
+	s.mu.Lock()
+	active := s.active[userID]
+	s.mu.Unlock()
+	if active != nil {
+		active.cancel()
+		select {
+		case <-active.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	jobs, err := s.exportRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := s.deleteJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run gera o arquivo de uma exportação em segundo plano. Cancelada (pela
+// remoção dos dados do usuário), a exportação termina como falha e sem arquivo.
+func (s *ExportService) run(ctx context.Context, job *domain.ExportJob, active *activeExport) {
+	defer s.finish(job.UserID, active)
+
+	// Os registros são gravados mesmo depois do cancelamento
+	saveCtx := context.WithoutCancel(ctx)
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		s.fail(saveCtx, job, "exportação cancelada")
+		return
+	}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	job.Status = domain.ExportStatusRunning
+	if err := s.exportRepo.Update(saveCtx, job); err != nil {
+		log.Printf("Erro ao atualizar exportação %s: %v", job.ID, err)
+		return
+	}
+
+	filePath, size, err := s.build(ctx, job)
+	if err == nil && ctx.Err() != nil {
+		// Cancelada depois de gravar o arquivo
+		os.Remove(filePath)
+		err = ctx.Err()
+	}
+	if err != nil {
+		log.Printf("Erro ao gerar exportação %s: %v", job.ID, err)
+		job.Status = domain.ExportStatusFailed
+		job.Error = "erro ao gerar exportação"
+		if errors.Is(err, context.Canceled) {
+			job.Error = "exportação cancelada"
+		}
+	} else {
+		now := time.Now()
+		expiresAt := now.Add(s.ttl)
+		job.Status = domain.ExportStatusCompleted
+		job.FilePath = filePath
+		job.FileSize = size
+		job.CompletedAt = &now
+		job.ExpiresAt = &expiresAt
+	}
+
+	if err := s.exportRepo.Update(saveCtx, job); err != nil {
+		log.Printf("Erro ao atualizar exportação %s: %v", job.ID, err)
+		// Sem o registro, nada removeria o arquivo
+		if job.FilePath != "" {
+			os.Remove(job.FilePath)
+		}
+	}
+}
+
+// finish encerra o registro de uma exportação em andamento
+func (s *ExportService) finish(userID uint, active *activeExport) {
+	s.mu.Lock()
+	if s.active[userID] == active {
+		delete(s.active, userID)
+	}
+	s.mu.Unlock()
+	active.cancel()
+	close(active.done)
+}
+
+// fail registra a falha de uma exportação que não chegou a ser gerada
+func (s *ExportService) fail(ctx context.Context, job *domain.ExportJob, reason string) {
+	job.Status = domain.ExportStatusFailed
+	job.Error = reason
+	if err := s.exportRepo.Update(ctx, job); err != nil {
+		log.Printf("Erro ao atualizar exportação %s: %v", job.ID, err)
+	}
+}
+
+// build monta o zip com os arquivos dos livros e o manifest.json
+func (s *ExportService) build(ctx context.Context, job *domain.ExportJob) (string, int64, error) {
+	profile, err := s.authService.GetProfile(ctx, job.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+	books, err := s.bookService.ListBooks(ctx, job.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	dir := filepath.Join(s.exportDir, fmt.Sprintf("%d", job.UserID))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	finalPath := filepath.Join(dir, job.ID+".zip")
+	tmpPath := finalPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("erro ao criar arquivo: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	manifest := exportManifest{
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Account:    profile,
+		Books:      make([]manifestBook, 0, len(books.Books)),
+	}
+
+	archive := zip.NewWriter(file)
+	for _, book := range books.Books {
+		if err := ctx.Err(); err != nil {
+			file.Close()
+			return "", 0, err
+		}
+
+		entry := manifestBook{
+			ID:                 book.ID,
+			Title:              book.Title,
+			Filename:           book.Filename,
+			Format:             book.Format,
+			FileSize:           book.FileSize,
+			CurrentPage:        book.CurrentPage,
+			ProgressPercentage: book.ProgressPercentage,
+			CreatedAt:          book.CreatedAt,
+			UpdatedAt:          book.UpdatedAt,
+			File:               path.Join("books", fmt.Sprintf("%d_%s", book.ID, safeFilename(book.Filename))),
+		}
+
+		if err := s.addBookFile(ctx, archive, entry.File, job.UserID, book.ID); err != nil {
+			if err.Error() != "arquivo não encontrado" {
+				file.Close()
+				return "", 0, err
+			}
+			entry.File = ""
+			entry.FileMissing = true
+		}
+
+		manifest.Books = append(manifest.Books, entry)
+	}
+
+	w, err := archive.Create("manifest.json")
+	if err != nil {
+		file.Close()
+		return "", 0, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		file.Close()
+		return "", 0, err
+	}
+
+	if err := archive.Close(); err != nil {
+		file.Close()
+		return "", 0, err
+	}
+	if err := file.Close(); err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		return "", 0, err
+	}
+
+	return finalPath, info.Size(), nil
+}
+
+// deleteExpired remove as exportações cujo prazo de download terminou
+func (s *ExportService) deleteExpired(ctx context.Context) {
+	jobs, err := s.exportRepo.FindExpired(ctx, time.Now())
+	if err != nil {
+		log.Printf("Erro ao buscar exportações expiradas: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		if err := s.deleteJob(ctx, job); err != nil {
+			log.Printf("Erro ao remover exportação %s: %v", job.ID, err)
+		}
+	}
+}
+
+// deleteJob remove o arquivo e o registro de uma exportação
+func (s *ExportService) deleteJob(ctx context.Context, job *domain.ExportJob) error {
+	if job.FilePath != "" {
+		if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("erro ao deletar arquivo: %w", err)
+		}
+	}
+	return s.exportRepo.Delete(ctx, job.ID)
+}
+
+// addBookFile copia o arquivo do livro do armazenamento para o zip. PDF e EPUB
+// já são comprimidos, então são armazenados sem nova compressão.
+func (s *ExportService) addBookFile(ctx context.Context, archive *zip.Writer, name string, userID, bookID uint) error {
+	src, err := s.bookService.OpenBookFile(ctx, bookID, userID)
+	if err != nil {
+		return err
+	}
+	defer src.Content.Close()
+
+	method := zip.Deflate
+	if src.Format == "pdf" || src.Format == "epub" {
+		method = zip.Store
+	}
+
+	w, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   method,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, src.Content)
+	return err
+}
+
+// safeFilename remove separadores de diretório do nome original do arquivo
+func safeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Base(name)
+	if name == "." || name == "/" || name == ".." {
+		return "arquivo"
+	}
+	return name
+}
+
+// toExportJobResponse converte a exportação para a resposta da API
+func toExportJobResponse(job *domain.ExportJob, now time.Time) *ExportJobResponse {
+	resp := &ExportJobResponse{
+		ID:        job.ID,
+		Status:    job.Status,
+		Error:     job.Error,
+		FileSize:  job.FileSize,
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.CompletedAt != nil {
+		resp.CompletedAt = job.CompletedAt.Format(time.RFC3339)
+	}
+	if job.ExpiresAt != nil {
+		resp.ExpiresAt = job.ExpiresAt.Format(time.RFC3339)
+	}
+	if job.IsDownloadable(now) {
+		resp.DownloadURL = "/api/v1/me/exports/" + job.ID + "/download"
+	}
+	return resp
+}
@@ -0,0 +1,178 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloud-reader/backend/internal/export/domain"
+)
+
+// waitForStatus espera a exportação chegar ao estado informado
+func waitForStatus(t *testing.T, repo *fakeExportRepo, id string, userID uint, status string) *domain.ExportJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := repo.FindByID(context.Background(), id, userID)
+		if err == nil && job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("exportação %s não chegou a %q (atual: %+v, %v)", id, status, job, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestExportLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeExportRepo()
+	service := newTestExportService(t, repo, &fakeLibrary{books: map[uint][]byte{1: []byte("%PDF-1.4 conteúdo")}})
+
+	resp, err := service.RequestExport(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != domain.ExportStatusPending {
+		t.Errorf("estado inicial = %q", resp.Status)
+	}
+	job := waitForStatus(t, repo, resp.ID, 7, domain.ExportStatusCompleted)
+	if job.ExpiresAt == nil || job.FileSize == 0 {
+		t.Errorf("exportação concluída sem prazo ou tamanho: %+v", job)
+	}
+
+	filePath, err := service.GetExportFile(ctx, resp.ID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	contents := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		contents[f.Name] = string(data)
+	}
+	if contents["books/1_livro.pdf"] != "%PDF-1.4 conteúdo" {
+		t.Errorf("arquivo do livro = %q", contents["books/1_livro.pdf"])
+	}
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(contents["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	for _, key := range []string{"exported_at", "account", "books"} {
+		if _, ok := manifest[key]; !ok {
+			t.Errorf("manifest sem %q", key)
+		}
+	}
+
+	// Outro usuário não acessa a exportação
+	if _, err := service.GetExportFile(ctx, resp.ID, 8); err == nil {
+		t.Error("exportação acessível por outro usuário")
+	}
+
+	// Concluída, não impede uma nova exportação
+	if _, err := service.RequestExport(ctx, 7); err != nil {
+		t.Errorf("nova exportação depois da concluída: %v", err)
+	}
+}
+
+func TestRequestExportInProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeExportRepo()
+	library := &fakeLibrary{books: map[uint][]byte{1: []byte("livro")}, gate: make(chan struct{}), opened: make(chan struct{}, 1)}
+	service := newTestExportService(t, repo, library)
+
+	first, err := service.RequestExport(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RequestExport(ctx, 7); !errors.Is(err, domain.ErrExportInProgress) {
+		t.Errorf("segunda exportação = %v, esperado ErrExportInProgress", err)
+	}
+
+	// Outra instância do servidor esbarra no índice de exportações ativas
+	other := newTestExportService(t, repo, library)
+	if _, err := other.RequestExport(ctx, 7); !errors.Is(err, domain.ErrExportInProgress) {
+		t.Errorf("exportação por outra instância = %v, esperado ErrExportInProgress", err)
+	}
+
+	// Outro usuário não é afetado
+	if _, err := other.RequestExport(ctx, 8); err != nil {
+		t.Errorf("exportação de outro usuário: %v", err)
+	}
+
+	close(library.gate)
+	waitForStatus(t, repo, first.ID, 7, domain.ExportStatusCompleted)
+}
+
+func TestDeleteUserExportsCancelsRunning(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeExportRepo()
+	library := &fakeLibrary{books: map[uint][]byte{1: []byte("livro")}, gate: make(chan struct{}), opened: make(chan struct{}, 1)}
+	service := newTestExportService(t, repo, library)
+
+	if _, err := service.RequestExport(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	<-library.opened
+
+	if err := service.DeleteUserExports(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	// A exportação cancelada não grava o registro nem o arquivo depois da remoção
+	if jobs, _ := repo.FindByUserID(ctx, 7); len(jobs) != 0 {
+		t.Errorf("exportações restantes: %+v", jobs)
+	}
+	entries, err := os.ReadDir(filepath.Join(service.exportDir, "7"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("arquivos restantes: %v", entries)
+	}
+
+	// O usuário fica livre para uma nova exportação
+	library.gate = nil
+	resp, err := service.RequestExport(ctx, 7)
+	if err != nil {
+		t.Fatalf("exportação depois da remoção: %v", err)
+	}
+	waitForStatus(t, repo, resp.ID, 7, domain.ExportStatusCompleted)
+}
+
+func TestDeleteUserExportsRemovesFiles(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeExportRepo()
+	service := newTestExportService(t, repo, &fakeLibrary{books: map[uint][]byte{1: []byte("livro")}})
+
+	resp, err := service.RequestExport(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := waitForStatus(t, repo, resp.ID, 7, domain.ExportStatusCompleted)
+
+	if err := service.DeleteUserExports(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(job.FilePath); !os.IsNotExist(err) {
+		t.Errorf("arquivo da exportação não removido: %v", err)
+	}
+	if jobs, _ := repo.FindByUserID(ctx, 7); len(jobs) != 0 {
+		t.Errorf("exportações restantes: %+v", jobs)
+	}
+}
@@ -0,0 +1,6 @@
+package domain
+
+import "errors"
+
+// ErrExportInProgress indica que o usuário já tem uma exportação na fila ou em execução
+var ErrExportInProgress = errors.New("já existe uma exportação em andamento")
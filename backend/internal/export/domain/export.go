@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// Estados de uma exportação
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// ExportJob representa a geração de um arquivo com todos os dados de um usuário
+type ExportJob struct {
+	ID        string    `gorm:"primarykey;type:varchar(36)" json:"id"` // UUID
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Status      string     `gorm:"not null;index" json:"status"`
+	Error       string     `json:"error,omitempty"`
+	FilePath    string     `json:"-"`
+	FileSize    int64      `json:"file_size"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"` // Fim da janela de download
+}
+
+// IsActive retorna true se a exportação ainda está na fila ou em execução
+func (j *ExportJob) IsActive() bool {
+	return j.Status == ExportStatusPending || j.Status == ExportStatusRunning
+}
+
+// IsDownloadable retorna true se o arquivo está pronto e dentro do prazo
+func (j *ExportJob) IsDownloadable(now time.Time) bool {
+	return j.Status == ExportStatusCompleted && j.ExpiresAt != nil && now.Before(*j.ExpiresAt)
+}
+
+// TableName define o nome da tabela no banco de dados
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ExportRepository define a interface do repositório de exportações (port)
+type ExportRepository interface {
+	// Create registra uma nova exportação. Retorna ErrExportInProgress se o
+	// usuário já tiver uma exportação ativa.
+	Create(ctx context.Context, job *ExportJob) error
+
+	// Update salva o estado da exportação
+	Update(ctx context.Context, job *ExportJob) error
+
+	// FindByID busca uma exportação pelo ID e UserID (valida ownership)
+	FindByID(ctx context.Context, id string, userID uint) (*ExportJob, error)
+
+	// FindByUserID busca as exportações de um usuário, da mais recente para a mais antiga
+	FindByUserID(ctx context.Context, userID uint) ([]*ExportJob, error)
+
+	// FindExpired busca as exportações concluídas cujo prazo de download terminou
+	FindExpired(ctx context.Context, now time.Time) ([]*ExportJob, error)
+
+	// Delete remove uma exportação
+	Delete(ctx context.Context, id string) error
+
+	// FailUnfinished marca como falhas as exportações interrompidas (ex.: reinício do servidor)
+	FailUnfinished(ctx context.Context, reason string) error
+}
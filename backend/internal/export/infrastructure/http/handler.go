@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud-reader/backend/internal/export/application"
+	"cloud-reader/backend/internal/export/domain"
+	"cloud-reader/backend/internal/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler gerencia os handlers HTTP da exportação de dados da conta
+type ExportHandler struct {
+	exportService *application.ExportService
+}
+
+// NewExportHandler cria uma nova instância do ExportHandler
+func NewExportHandler(exportService *application.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestExport agenda a geração de uma nova exportação
+func (h *ExportHandler) RequestExport(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.exportService.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrExportInProgress) {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// ListExports lista as exportações do usuário
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.exportService.ListExports(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetExport retorna o estado de uma exportação
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	resp, err := h.exportService.GetExport(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DownloadExport envia o arquivo zip de uma exportação concluída
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "usuário não autenticado",
+		})
+		return
+	}
+
+	filePath, err := h.exportService.GetExportFile(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if err.Error() == "exportação indisponível ou expirada" {
+			statusCode = http.StatusGone
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.FileAttachment(filePath, fmt.Sprintf("cloud-reader-export-%s.zip", time.Now().Format("2006-01-02")))
+}
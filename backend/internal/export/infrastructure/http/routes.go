@@ -0,0 +1,19 @@
+package http
+
+import (
+	"cloud-reader/backend/internal/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registra as rotas de exportação de dados da conta (/me/exports).
+// Exigem autenticação por sessão (não aceitam chave de API).
+func RegisterRoutes(router *gin.RouterGroup, handler *ExportHandler, authMiddleware gin.HandlerFunc) {
+	exports := router.Group("/me/exports", authMiddleware, middleware.RequireSession())
+	{
+		exports.POST("", handler.RequestExport)
+		exports.GET("", handler.ListExports)
+		exports.GET("/:id/download", handler.DownloadExport)
+		exports.GET("/:id", handler.GetExport)
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud-reader/backend/internal/export/domain"
+	"gorm.io/gorm"
+)
+
+// postgresExportRepository implementa ExportRepository usando PostgreSQL/GORM
+type postgresExportRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresExportRepository cria uma nova instância do repositório PostgreSQL
+func NewPostgresExportRepository(db *gorm.DB) domain.ExportRepository {
+	return &postgresExportRepository{
+		db: db,
+	}
+}
+
+// EnsureActiveIndex cria o índice único parcial que permite uma só exportação
+// ativa por usuário. Exportações ativas duplicadas de versões anteriores são
+// marcadas como falhas antes (fica a mais recente).
+func EnsureActiveIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE export_jobs SET status = ?, error = ?
+			WHERE status IN ? AND EXISTS (
+				SELECT 1 FROM export_jobs newer
+				WHERE newer.user_id = export_jobs.user_id AND newer.status IN ?
+				AND (newer.created_at, newer.id) > (export_jobs.created_at, export_jobs.id)
+			)`,
+			domain.ExportStatusFailed, "exportação duplicada", activeStatuses, activeStatuses,
+		).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_export_jobs_active_user
+			ON export_jobs (user_id) WHERE status IN ('pending', 'running')`).Error
+	})
+}
+
+// activeStatuses são os estados cobertos pelo índice de exportação ativa
+var activeStatuses = []string{domain.ExportStatusPending, domain.ExportStatusRunning}
+
+// Create registra uma nova exportação. Retorna domain.ErrExportInProgress se o
+// usuário já tiver uma exportação ativa.
+func (r *postgresExportRepository) Create(ctx context.Context, job *domain.ExportJob) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		if r.isDuplicateKey(err) {
+			return domain.ErrExportInProgress
+		}
+		return err
+	}
+	return nil
+}
+
+// Update salva o estado da exportação
+func (r *postgresExportRepository) Update(ctx context.Context, job *domain.ExportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// FindByID busca uma exportação pelo ID e UserID (valida ownership)
+func (r *postgresExportRepository) FindByID(ctx context.Context, id string, userID uint) (*domain.ExportJob, error) {
+	var job domain.ExportJob
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("exportação não encontrada")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindByUserID busca as exportações de um usuário
+func (r *postgresExportRepository) FindByUserID(ctx context.Context, userID uint) ([]*domain.ExportJob, error) {
+	var jobs []*domain.ExportJob
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// FindExpired busca as exportações concluídas cujo prazo de download terminou
+func (r *postgresExportRepository) FindExpired(ctx context.Context, now time.Time) ([]*domain.ExportJob, error) {
+	var jobs []*domain.ExportJob
+	if err := r.db.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at < ?", now).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Delete remove uma exportação
+func (r *postgresExportRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.ExportJob{}).Error
+}
+
+// FailUnfinished marca como falhas as exportações interrompidas
+func (r *postgresExportRepository) FailUnfinished(ctx context.Context, reason string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ExportJob{}).
+		Where("status IN ?", activeStatuses).
+		Updates(map[string]interface{}{
+			"status": domain.ExportStatusFailed,
+			"error":  reason,
+		}).Error
+}
+
+// isDuplicateKey informa se o erro é uma violação de índice único
+func (r *postgresExportRepository) isDuplicateKey(err error) bool {
+	translator, ok := r.db.Dialector.(gorm.ErrorTranslator)
+	return ok && errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey)
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +19,93 @@ type Config struct {
 	DatabaseName     string
 	ServerPort       string
 	Environment      string
+	TrustedProxies   []string // Proxies cujo X-Forwarded-For é aceito (vazio = nenhum)
+
+	// Autenticação
+	JWTAlgorithm       string        // HS256, RS256 ou EdDSA
+	JWTSecret          string        // Segredo usado no HS256
+	JWTPrivateKeyPath  string        // Chave privada PEM usada no RS256/EdDSA
+	JWTPublicKeyPath   string        // Chave pública PEM (opcional, derivada da privada se vazia)
+	JWTIssuer          string        // Valor do claim "iss"
+	AccessTokenTTL     time.Duration // Validade do token de acesso
+	RefreshTokenTTL    time.Duration // Validade do refresh token (renovada a cada rotação)
+	AllowDevUserHeader bool          // Aceita o header X-User-ID (somente em desenvolvimento)
+	AdminEmails        []string      // Emails promovidos a administrador na inicialização e no registro
+
+	// Recuperação de senha e verificação de email
+	AppBaseURL           string        // URL do frontend usada nos links enviados por email
+	PasswordResetTTL     time.Duration // Validade do link de redefinição de senha
+	EmailVerificationTTL time.Duration // Validade do link de verificação de email
+
+	// Autenticação em dois fatores
+	TwoFactorIssuer       string        // Nome exibido nos aplicativos autenticadores
+	TwoFactorChallengeTTL time.Duration // Tempo para concluir o segundo passo do login
+
+	// Proteção contra força bruta no login
+	LoginAttemptStore    string        // memory ou postgres
+	LoginBaseDelay       time.Duration // Espera após a primeira falha (dobra a cada falha)
+	LoginMaxDelay        time.Duration // Espera máxima do backoff
+	LoginMaxFailures     int           // Falhas por conta até o bloqueio temporário
+	LoginIPMaxFailures   int           // Falhas por IP até o bloqueio temporário
+	LoginLockoutDuration time.Duration // Duração do bloqueio temporário
+	LoginFailureWindow   time.Duration // Janela após a qual as falhas são esquecidas
+
+	// Login via OpenID Connect (habilitado quando OIDCIssuerURL é definido)
+	OIDCIssuerURL            string
+	OIDCClientID             string
+	OIDCClientSecret         string
+	OIDCRedirectURL          string   // Callback do backend registrado no provedor
+	OIDCScopes               []string // Escopos solicitados (openid é obrigatório)
+	OIDCProviderName         string   // Nome exibido no frontend
+	OIDCAutoProvision        bool     // Cria a conta no primeiro login se o email não existir
+	OIDCRequireVerifiedEmail bool     // Exige o claim email_verified para vincular/criar contas
+
+	// Armazenamento de arquivos
+	StorageDriver    string // local ou s3
+	StorageLocalRoot string // Diretório raiz do driver local
+	S3Endpoint       string // host:porta do serviço compatível com S3 (ex.: localhost:9000)
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3UseSSL         bool
+
+	// Criptografia em repouso dos arquivos (opcional; chave AES-256 em base64)
+	StorageEncryptionKey     string
+	StorageEncryptionKeyFile string // Arquivo com a chave (tem precedência sobre a variável)
+
+	// Cota de armazenamento padrão por usuário, em bytes (0 = sem limite)
+	StorageQuotaBytes int64
+
+	// Verificação de consistência do armazenamento
+	StorageCheckInterval time.Duration // Intervalo da verificação em segundo plano (0 = desativada)
+	StorageOrphanGrace   time.Duration // Idade mínima de um arquivo órfão para ser removido
+	StorageDeleteOrphans bool          // Remove os arquivos órfãos na verificação em segundo plano
+
+	// Uploads resumíveis (protocolo tus)
+	UploadDir     string        // Diretório local dos arquivos parciais
+	UploadMaxSize int64         // Tamanho máximo de um upload resumível, em bytes
+	UploadTTL     time.Duration // Prazo para concluir um upload desde a última atividade
+
+	// Inspeção de segurança dos EPUBs enviados (zip bomb, caminhos e entradas)
+	EPUBMaxUncompressedSize int64         // Soma máxima dos tamanhos descompactados, em bytes
+	EPUBMaxCompressionRatio int64         // Razão máxima descompactado/compactado de uma entrada
+	EPUBMaxEntries          int           // Quantidade máxima de entradas no zip
+	EPUBInspectTimeout      time.Duration // Tempo máximo da inspeção
+
+	// Exportação de dados da conta
+	ExportDir string        // Diretório onde os arquivos exportados são gerados
+	ExportTTL time.Duration // Tempo em que o arquivo fica disponível para download
+
+	// Email
+	MailDriver    string // smtp ou log
+	MailFrom      string
+	MailOutputDir string // Diretório onde o driver log grava os .eml (opcional)
+	SMTPHost      string
+	SMTPPort      string
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPTLS       string // none, starttls ou tls
 }
 
 // Load carrega as configurações do ambiente
@@ -31,8 +121,85 @@ func Load() *Config {
 		DatabaseName:     getEnv("DB_NAME", "cloud_reader"),
 		ServerPort:       getEnv("SERVER_PORT", "8080"),
 		Environment:      getEnv("ENVIRONMENT", "development"),
+
+		JWTAlgorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+		JWTSecret:         getEnv("JWT_SECRET", ""),
+		JWTPrivateKeyPath: getEnv("JWT_PRIVATE_KEY_PATH", ""),
+		JWTPublicKeyPath:  getEnv("JWT_PUBLIC_KEY_PATH", ""),
+		JWTIssuer:         getEnv("JWT_ISSUER", "cloud-reader"),
+		AccessTokenTTL:    getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:   getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
 	}
 
+	config.AppBaseURL = getEnv("APP_BASE_URL", "http://localhost:3000")
+	config.PasswordResetTTL = getEnvDuration("PASSWORD_RESET_TTL", time.Hour)
+	config.EmailVerificationTTL = getEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+
+	config.TwoFactorIssuer = getEnv("TWO_FACTOR_ISSUER", "Cloud Reader")
+	config.TwoFactorChallengeTTL = getEnvDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute)
+
+	config.LoginAttemptStore = getEnv("LOGIN_ATTEMPT_STORE", "memory")
+	config.LoginBaseDelay = getEnvDuration("LOGIN_BASE_DELAY", time.Second)
+	config.LoginMaxDelay = getEnvDuration("LOGIN_MAX_DELAY", 30*time.Second)
+	config.LoginMaxFailures = getEnvInt("LOGIN_MAX_FAILURES", 10)
+	config.LoginIPMaxFailures = getEnvInt("LOGIN_IP_MAX_FAILURES", 50)
+	config.LoginLockoutDuration = getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	config.LoginFailureWindow = getEnvDuration("LOGIN_FAILURE_WINDOW", 30*time.Minute)
+
+	config.OIDCIssuerURL = getEnv("OIDC_ISSUER_URL", "")
+	config.OIDCClientID = getEnv("OIDC_CLIENT_ID", "")
+	config.OIDCClientSecret = getEnv("OIDC_CLIENT_SECRET", "")
+	config.OIDCRedirectURL = getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/v1/auth/oidc/callback")
+	config.OIDCScopes = strings.Fields(getEnv("OIDC_SCOPES", "openid email profile"))
+	config.OIDCProviderName = getEnv("OIDC_PROVIDER_NAME", "SSO")
+	config.OIDCAutoProvision = getEnvBool("OIDC_AUTO_PROVISION", true)
+	config.OIDCRequireVerifiedEmail = getEnvBool("OIDC_REQUIRE_VERIFIED_EMAIL", true)
+
+	config.StorageDriver = getEnv("STORAGE_DRIVER", "local")
+	config.StorageLocalRoot = getEnv("STORAGE_LOCAL_ROOT", "uploads")
+	config.S3Endpoint = getEnv("S3_ENDPOINT", "localhost:9000")
+	config.S3Region = getEnv("S3_REGION", "us-east-1")
+	config.S3Bucket = getEnv("S3_BUCKET", "cloud-reader")
+	config.S3AccessKey = getEnv("S3_ACCESS_KEY", "")
+	config.S3SecretKey = getEnv("S3_SECRET_KEY", "")
+	config.S3UseSSL = getEnvBool("S3_USE_SSL", false)
+	config.StorageEncryptionKey = getEnv("STORAGE_ENCRYPTION_KEY", "")
+	config.StorageEncryptionKeyFile = getEnv("STORAGE_ENCRYPTION_KEY_FILE", "")
+	config.StorageQuotaBytes = int64(getEnvInt("STORAGE_QUOTA_MB", 1024)) * 1024 * 1024
+	config.StorageCheckInterval = getEnvDuration("STORAGE_CHECK_INTERVAL", 0)
+	config.StorageOrphanGrace = getEnvDuration("STORAGE_ORPHAN_GRACE", 24*time.Hour)
+	config.StorageDeleteOrphans = getEnvBool("STORAGE_DELETE_ORPHANS", false)
+
+	config.UploadDir = getEnv("UPLOAD_DIR", "uploads/tus")
+	config.UploadMaxSize = int64(getEnvInt("UPLOAD_MAX_SIZE_MB", 2048)) * 1024 * 1024
+	config.UploadTTL = getEnvDuration("UPLOAD_TTL", 24*time.Hour)
+
+	config.EPUBMaxUncompressedSize = int64(getEnvInt("EPUB_MAX_UNCOMPRESSED_MB", 1024)) * 1024 * 1024
+	config.EPUBMaxCompressionRatio = int64(getEnvInt("EPUB_MAX_COMPRESSION_RATIO", 100))
+	config.EPUBMaxEntries = getEnvInt("EPUB_MAX_ENTRIES", 10000)
+	config.EPUBInspectTimeout = getEnvDuration("EPUB_INSPECT_TIMEOUT", 30*time.Second)
+
+	config.ExportDir = getEnv("EXPORT_DIR", "uploads/exports")
+	config.ExportTTL = getEnvDuration("EXPORT_TTL", 24*time.Hour)
+
+	config.MailDriver = getEnv("MAIL_DRIVER", "log")
+	config.MailFrom = getEnv("MAIL_FROM", "Cloud Reader <no-reply@cloud-reader.local>")
+	config.MailOutputDir = getEnv("MAIL_OUTPUT_DIR", "")
+	config.SMTPHost = getEnv("SMTP_HOST", "localhost")
+	config.SMTPPort = getEnv("SMTP_PORT", "1025")
+	config.SMTPUsername = getEnv("SMTP_USERNAME", "")
+	config.SMTPPassword = getEnv("SMTP_PASSWORD", "")
+	config.SMTPTLS = getEnv("SMTP_TLS", "none")
+
+	config.AdminEmails = getEnvList("ADMIN_EMAILS")
+
+	// Sem proxies confiáveis, o IP do cliente é o da conexão: X-Forwarded-For
+	// poderia ser trocado a cada requisição para escapar dos limites por IP
+	config.TrustedProxies = getEnvList("TRUSTED_PROXIES")
+
+	// O header X-User-ID só é aceito com a flag explícita e fora de produção
+	config.AllowDevUserHeader = getEnvBool("AUTH_ALLOW_DEV_USER_HEADER", false) && config.IsDevelopment()
+
 	// Constrói a URL de conexão se não fornecida diretamente
 	if config.DatabaseURL == "" {
 		config.DatabaseURL = getEnv("DATABASE_URL", "")
@@ -63,6 +230,60 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Aviso: valor inválido para %s (%q), usando padrão %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Aviso: valor inválido para %s (%q), usando padrão %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Aviso: valor inválido para %s (%q), usando padrão %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// OIDCEnabled retorna true se o login via OpenID Connect está configurado
+func (c *Config) OIDCEnabled() bool {
+	return c.OIDCIssuerURL != "" && c.OIDCClientID != ""
+}
+
 // IsDevelopment retorna true se estiver em ambiente de desenvolvimento
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
@@ -82,4 +303,21 @@ func (c *Config) Log() {
 	log.Printf("  Database Name: %s", c.DatabaseName)
 	log.Printf("  Server Port: %s", c.ServerPort)
 	log.Printf("  Environment: %s", c.Environment)
+	if len(c.TrustedProxies) > 0 {
+		log.Printf("  Trusted Proxies: %s", strings.Join(c.TrustedProxies, ", "))
+	}
+	log.Printf("  JWT Algorithm: %s", c.JWTAlgorithm)
+	log.Printf("  Access Token TTL: %s", c.AccessTokenTTL)
+	log.Printf("  Refresh Token TTL: %s", c.RefreshTokenTTL)
+	log.Printf("  Login Attempt Store: %s", c.LoginAttemptStore)
+	if c.OIDCEnabled() {
+		log.Printf("  OIDC Issuer: %s", c.OIDCIssuerURL)
+	}
+	log.Printf("  Mail Driver: %s", c.MailDriver)
+	if c.MailDriver == "smtp" {
+		log.Printf("  SMTP: %s:%s (TLS: %s)", c.SMTPHost, c.SMTPPort, c.SMTPTLS)
+	}
+	if c.AllowDevUserHeader {
+		log.Printf("  Aviso: header X-User-ID habilitado (apenas para desenvolvimento)")
+	}
 }
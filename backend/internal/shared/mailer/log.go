@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// logMailer implementa Mailer registrando as mensagens no log e, opcionalmente,
+// gravando-as como arquivos .eml em um diretório (útil em desenvolvimento e testes)
+type logMailer struct {
+	outputDir string
+	from      string
+}
+
+// NewLogMailer cria uma nova instância do Mailer de log.
+// Se outputDir for vazio, as mensagens são apenas registradas no log.
+func NewLogMailer(outputDir, from string) Mailer {
+	return &logMailer{
+		outputDir: outputDir,
+		from:      from,
+	}
+}
+
+// Send registra a mensagem no log e grava o arquivo .eml
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("Email para %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+
+	if m.outputDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.outputDir, 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de emails: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405"), uuid.New().String())
+	if err := os.WriteFile(filepath.Join(m.outputDir, name), buildMessage(m.from, msg), 0644); err != nil {
+		return fmt.Errorf("erro ao gravar email: %w", err)
+	}
+
+	return nil
+}
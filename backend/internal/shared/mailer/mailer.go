@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"cloud-reader/backend/internal/shared/config"
+)
+
+// Message representa um email a ser enviado
+type Message struct {
+	To      string
+	Subject string
+	Body    string // Texto puro (UTF-8)
+}
+
+// Mailer define a interface de envio de emails (port)
+type Mailer interface {
+	// Send envia a mensagem
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New cria o Mailer configurado em MAIL_DRIVER (smtp ou log)
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.MailDriver {
+	case "smtp":
+		return NewSMTPMailer(cfg), nil
+	case "log":
+		return NewLogMailer(cfg.MailOutputDir, cfg.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("driver de email não suportado: %s", cfg.MailDriver)
+	}
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey é a chave usada para armazenar o usuário autenticado no contexto do Gin
+const principalKey = "auth.principal"
+
+// Principal representa o usuário autenticado na requisição
+type Principal struct {
+	UserID    uint
+	SessionID string   // Vazio quando a credencial não está vinculada a uma sessão
+	APIKeyID  uint     // Preenchido quando autenticado por chave de API
+	Role      string   // Papel do usuário (user ou admin)
+	Scopes    []string // nil = acesso total; chaves de API têm escopos restritos
+}
+
+// HasScope retorna true se o principal tem acesso ao escopo
+func (p *Principal) HasScope(scope string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator valida a credencial enviada no header Authorization
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// AuthMiddleware exige um token Bearer válido e popula o usuário autenticado no contexto.
+// Quando allowDevHeader é true, o header X-User-ID é aceito na ausência de token
+// (apenas para desenvolvimento local).
+func AuthMiddleware(authenticator Authenticator, allowDevHeader bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			if allowDevHeader {
+				if principal, ok := devHeaderPrincipal(c); ok {
+					c.Set(principalKey, principal)
+					c.Next()
+					return
+				}
+			}
+			abortUnauthorized(c, "token de acesso não fornecido")
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			abortUnauthorized(c, "token de acesso inválido ou expirado")
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// RequireScope exige que o usuário autenticado tenha o escopo informado.
+// Deve ser usado após AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			abortUnauthorized(c, "usuário não autenticado")
+			return
+		}
+		if !principal.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "escopo insuficiente",
+				"scope": scope,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession rejeita credenciais de escopo restrito (chaves de API).
+// Usado nas rotas de gerenciamento da conta. Deve ser usado após AuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			abortUnauthorized(c, "usuário não autenticado")
+			return
+		}
+		if principal.Scopes != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "operação não permitida com chave de API",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole exige que o usuário autenticado tenha o papel informado.
+// Deve ser usado após AuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok {
+			abortUnauthorized(c, "usuário não autenticado")
+			return
+		}
+		if principal.Role != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "acesso negado",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetPrincipal retorna o usuário autenticado da requisição
+func GetPrincipal(c *gin.Context) (*Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*Principal)
+	return principal, ok
+}
+
+// GetUserID retorna o ID do usuário autenticado da requisição
+func GetUserID(c *gin.Context) (uint, bool) {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		return 0, false
+	}
+	return principal.UserID, true
+}
+
+// bearerToken extrai o token do header Authorization
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return "", false
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// devHeaderPrincipal lê o usuário do header X-User-ID (apenas desenvolvimento)
+func devHeaderPrincipal(c *gin.Context) (*Principal, bool) {
+	userIDStr := c.GetHeader("X-User-ID")
+	if userIDStr == "" {
+		return nil, false
+	}
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil || userID == 0 {
+		return nil, false
+	}
+	return &Principal{UserID: uint(userID)}, true
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="cloud-reader"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"error": message,
+	})
+}
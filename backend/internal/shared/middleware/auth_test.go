@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveWithPrincipal executa o handler de autorização com o principal já autenticado
+func serveWithPrincipal(principal *Principal, guard gin.HandlerFunc) int {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		if principal != nil {
+			c.Set(principalKey, principal)
+		}
+	}, guard, func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w.Code
+}
+
+func TestRequireScope(t *testing.T) {
+	session := &Principal{UserID: 1, SessionID: "s"}
+	reader := &Principal{UserID: 1, APIKeyID: 1, Scopes: []string{"books:read"}}
+	noScopes := &Principal{UserID: 1, APIKeyID: 2, Scopes: []string{}}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		scope     string
+		want      int
+	}{
+		{name: "sessão tem acesso total", principal: session, scope: "books:write", want: http.StatusNoContent},
+		{name: "chave com o escopo", principal: reader, scope: "books:read", want: http.StatusNoContent},
+		{name: "chave sem o escopo", principal: reader, scope: "books:write", want: http.StatusForbidden},
+		{name: "chave sem escopos", principal: noScopes, scope: "books:read", want: http.StatusForbidden},
+		{name: "sem autenticação", principal: nil, scope: "books:read", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveWithPrincipal(tt.principal, RequireScope(tt.scope)); got != tt.want {
+				t.Errorf("status = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	if got := serveWithPrincipal(&Principal{UserID: 1, SessionID: "s"}, RequireSession()); got != http.StatusNoContent {
+		t.Errorf("sessão: status = %d", got)
+	}
+	if got := serveWithPrincipal(&Principal{UserID: 1, APIKeyID: 1, Scopes: []string{"books:read", "books:write", "progress:write"}}, RequireSession()); got != http.StatusForbidden {
+		t.Errorf("chave de API com todos os escopos: status = %d, esperado 403", got)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *Principal
+		want      int
+	}{
+		{name: "administrador", principal: &Principal{UserID: 1, Role: "admin"}, want: http.StatusNoContent},
+		{name: "usuário comum", principal: &Principal{UserID: 2, Role: "user"}, want: http.StatusForbidden},
+		{name: "sem papel", principal: &Principal{UserID: 3}, want: http.StatusForbidden},
+		{name: "sem autenticação", principal: nil, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveWithPrincipal(tt.principal, RequireRole("admin")); got != tt.want {
+				t.Errorf("status = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ratioCheckThreshold é o tamanho a partir do qual a taxa de compressão de uma
+// entrada é verificada (arquivos pequenos e repetitivos comprimem muito sem perigo)
+const ratioCheckThreshold = 1 << 20
+
+// Códigos dos erros de ArchiveError
+const (
+	ArchiveTooLarge         = "archive_too_large"
+	ArchiveCompressionRatio = "archive_compression_ratio"
+	ArchiveTooManyEntries   = "archive_too_many_entries"
+	ArchiveUnsafePath       = "archive_unsafe_path"
+	ArchiveInvalid          = "archive_invalid"
+	ArchiveTimeout          = "archive_inspection_timeout"
+)
+
+// ArchiveLimits são os limites aplicados a arquivos compactados (EPUB) enviados
+type ArchiveLimits struct {
+	MaxUncompressedSize int64         // Soma máxima dos tamanhos descompactados, em bytes
+	MaxCompressionRatio int64         // Razão máxima entre tamanho descompactado e compactado
+	MaxEntries          int           // Quantidade máxima de entradas
+	Timeout             time.Duration // Tempo máximo da inspeção
+}
+
+// ArchiveError indica que um arquivo compactado foi recusado pela inspeção de segurança
+type ArchiveError struct {
+	Code   string
+	Reason string
+}
+
+// Error implementa a interface error
+func (e *ArchiveError) Error() string {
+	return "arquivo compactado recusado: " + e.Reason
+}
+
+// InspectArchive percorre um zip verificando caminhos, quantidade de entradas,
+// tamanho descompactado e taxa de compressão. O conteúdo é de fato descompactado,
+// pois os tamanhos declarados no diretório central podem ser falsos.
+func InspectArchive(ctx context.Context, r io.ReaderAt, size int64, limits ArchiveLimits) error {
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return &ArchiveError{Code: ArchiveInvalid, Reason: "zip corrompido"}
+	}
+
+	if limits.MaxEntries > 0 && len(archive.File) > limits.MaxEntries {
+		return &ArchiveError{
+			Code:   ArchiveTooManyEntries,
+			Reason: fmt.Sprintf("mais de %d entradas", limits.MaxEntries),
+		}
+	}
+
+	// Confere os caminhos e os tamanhos declarados antes de descompactar qualquer coisa
+	var declared uint64
+	for _, file := range archive.File {
+		if !safeEntryName(file.Name) {
+			return &ArchiveError{Code: ArchiveUnsafePath, Reason: fmt.Sprintf("caminho inválido %q", file.Name)}
+		}
+		declared += file.UncompressedSize64
+		if err := checkArchiveSize(declared, limits); err != nil {
+			return err
+		}
+		if err := checkCompressionRatio(file, file.UncompressedSize64, limits); err != nil {
+			return err
+		}
+	}
+
+	var total uint64
+	for _, file := range archive.File {
+		if err := ctx.Err(); err != nil {
+			return inspectionError(err)
+		}
+
+		written, err := inflateEntry(ctx, file)
+		if err != nil {
+			if ctx.Err() != nil {
+				return inspectionError(ctx.Err())
+			}
+			return &ArchiveError{Code: ArchiveInvalid, Reason: fmt.Sprintf("entrada %q corrompida", file.Name)}
+		}
+
+		total += written
+		if err := checkArchiveSize(total, limits); err != nil {
+			return err
+		}
+		if err := checkCompressionRatio(file, written, limits); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inflateEntry descompacta uma entrada descartando o conteúdo e retorna o
+// tamanho real (o leitor do zip falha se passar do tamanho declarado)
+func inflateEntry(ctx context.Context, file *zip.File) (uint64, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	written, err := io.Copy(io.Discard, readerWithContext(ctx, rc))
+	return uint64(written), err
+}
+
+// safeEntryName recusa caminhos absolutos, com letra de unidade ou que saiam da raiz do zip
+func safeEntryName(name string) bool {
+	if len(name) >= 2 && name[1] == ':' {
+		return false
+	}
+	_, err := cleanKey(name)
+	return err == nil
+}
+
+// checkArchiveSize verifica o limite de tamanho descompactado
+func checkArchiveSize(total uint64, limits ArchiveLimits) error {
+	if limits.MaxUncompressedSize > 0 && total > uint64(limits.MaxUncompressedSize) {
+		return &ArchiveError{
+			Code:   ArchiveTooLarge,
+			Reason: fmt.Sprintf("conteúdo descompactado maior que %d bytes", limits.MaxUncompressedSize),
+		}
+	}
+	return nil
+}
+
+// checkCompressionRatio verifica a taxa de compressão de uma entrada
+func checkCompressionRatio(file *zip.File, uncompressed uint64, limits ArchiveLimits) error {
+	if limits.MaxCompressionRatio <= 0 || uncompressed < ratioCheckThreshold {
+		return nil
+	}
+	if file.CompressedSize64 == 0 || uncompressed/file.CompressedSize64 > uint64(limits.MaxCompressionRatio) {
+		return &ArchiveError{
+			Code:   ArchiveCompressionRatio,
+			Reason: fmt.Sprintf("taxa de compressão acima de %d:1 em %q", limits.MaxCompressionRatio, file.Name),
+		}
+	}
+	return nil
+}
+
+// inspectionError converte o fim do contexto em erro da inspeção
+func inspectionError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &ArchiveError{Code: ArchiveTimeout, Reason: "tempo de inspeção esgotado"}
+	}
+	return err
+}
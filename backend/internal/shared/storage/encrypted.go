@@ -0,0 +1,433 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cloud-reader/backend/internal/shared/config"
+)
+
+// Formato de um objeto cifrado:
+//
+//	magic (6) | tamanho do bloco (4) | prefixo do nonce (7) | nonce da chave (12) | chave de dados cifrada (48)
+//	bloco 0 | bloco 1 | ... (cada bloco: até encryptionChunkSize bytes + tag de 16)
+//
+// Cada arquivo tem sua própria chave de dados (AES-256), cifrada com a chave
+// mestra (envelope). O nonce de cada bloco é prefixo + índice + marcador de
+// último bloco, o que impede reordenar ou truncar blocos sem detecção. Como os
+// blocos têm tamanho fixo, um Seek só precisa decifrar o bloco onde cai.
+const (
+	encryptionMagic     = "CRENC1"
+	encryptionChunkSize = 64 * 1024
+
+	noncePrefixSize  = 7
+	keySize          = 32
+	gcmNonceSize     = 12
+	gcmTagSize       = 16
+	keyHeaderSize    = len(encryptionMagic) + 4 + noncePrefixSize
+	encryptionHeader = keyHeaderSize + gcmNonceSize + keySize + gcmTagSize
+)
+
+// errCorruptedObject indica um objeto cifrado adulterado ou truncado
+var errCorruptedObject = errors.New("arquivo cifrado corrompido")
+
+// encryptedStorage cifra os objetos de outro Storage (decorator)
+type encryptedStorage struct {
+	inner     Storage
+	masterKey cipher.AEAD
+}
+
+// NewEncryptedStorage cria um Storage que cifra o conteúdo gravado no inner.
+// Objetos gravados antes da criptografia continuam legíveis sem alteração.
+func NewEncryptedStorage(inner Storage, masterKey []byte) (Storage, error) {
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("chave mestra inválida: %w", err)
+	}
+	return &encryptedStorage{inner: inner, masterKey: aead}, nil
+}
+
+// LoadMasterKey lê a chave mestra de STORAGE_ENCRYPTION_KEY ou do arquivo em
+// STORAGE_ENCRYPTION_KEY_FILE (32 bytes em base64). Retorna nil se nenhuma estiver configurada.
+func LoadMasterKey(cfg *config.Config) ([]byte, error) {
+	encoded := cfg.StorageEncryptionKey
+	if cfg.StorageEncryptionKeyFile != "" {
+		data, err := os.ReadFile(cfg.StorageEncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler chave de criptografia: %w", err)
+		}
+		encoded = string(data)
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("chave de criptografia deve estar em base64: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("chave de criptografia deve ter %d bytes", keySize)
+	}
+	return key, nil
+}
+
+// EncryptInPlace cifra um objeto gravado antes da criptografia ser ativada.
+// Retorna false se o objeto já estava cifrado.
+func EncryptInPlace(ctx context.Context, store Storage, key string) (bool, error) {
+	s, ok := store.(*encryptedStorage)
+	if !ok {
+		return false, errors.New("criptografia do armazenamento não configurada")
+	}
+
+	content, header, err := s.open(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	defer content.Close()
+	if header != nil {
+		return false, nil
+	}
+
+	info, err := s.inner.Stat(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	// O driver local grava em arquivo temporário e renomeia; o S3 substitui o
+	// objeto só ao fim do envio. Em ambos a leitura do original não é afetada.
+	if err := s.Put(ctx, key, content, info.Size, info.ContentType); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Put cifra o conteúdo em blocos enquanto o envia ao armazenamento
+func (s *encryptedStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	dataKey := make([]byte, keySize)
+	header := make([]byte, encryptionHeader)
+	copy(header, encryptionMagic)
+	binary.BigEndian.PutUint32(header[len(encryptionMagic):], encryptionChunkSize)
+
+	keyNonce := header[keyHeaderSize : keyHeaderSize+gcmNonceSize]
+	for _, random := range [][]byte{dataKey, header[len(encryptionMagic)+4 : keyHeaderSize], keyNonce} {
+		if _, err := rand.Read(random); err != nil {
+			return fmt.Errorf("erro ao gerar chave: %w", err)
+		}
+	}
+	wrappedKey := s.masterKey.Seal(nil, keyNonce, dataKey, header[:keyHeaderSize])
+	copy(header[keyHeaderSize+gcmNonceSize:], wrappedKey)
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+
+	encryptedSize := int64(-1)
+	if size >= 0 {
+		encryptedSize = encryptedLength(size)
+	}
+
+	return s.inner.Put(ctx, key, &encryptingReader{
+		aead:   aead,
+		header: header,
+		source: bufio.NewReaderSize(r, encryptionChunkSize),
+		buf:    bytes.NewReader(header),
+	}, encryptedSize, "application/octet-stream")
+}
+
+// Get abre o objeto decifrando os blocos sob demanda
+func (s *encryptedStorage) Get(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	content, header, err := s.open(ctx, key)
+	if err != nil || header == nil {
+		return content, err
+	}
+
+	info, err := s.inner.Stat(ctx, key)
+	if err != nil {
+		content.Close()
+		return nil, err
+	}
+
+	reader, err := s.newDecryptingReader(content, header, info.Size)
+	if err != nil {
+		content.Close()
+		return nil, err
+	}
+	return reader, nil
+}
+
+// Stat retorna as informações com o tamanho do conteúdo decifrado
+func (s *encryptedStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := s.inner.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	content, header, err := s.open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	content.Close()
+
+	if header != nil {
+		plainSize, err := plainLength(info.Size)
+		if err != nil {
+			return nil, err
+		}
+		info.Size = plainSize
+		info.ContentType = ""
+	}
+	return info, nil
+}
+
+// Delete remove o objeto
+func (s *encryptedStorage) Delete(ctx context.Context, key string) error {
+	return s.inner.Delete(ctx, key)
+}
+
+// List lista os objetos. Os tamanhos são os gravados (cifrados), sem ler cada objeto.
+func (s *encryptedStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return s.inner.List(ctx, prefix)
+}
+
+// open abre o objeto e lê o cabeçalho. Para objetos não cifrados o header é
+// nil e o reader volta ao início.
+func (s *encryptedStorage) open(ctx context.Context, key string) (io.ReadSeekCloser, []byte, error) {
+	content, err := s.inner.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make([]byte, encryptionHeader)
+	n, err := io.ReadFull(content, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		content.Close()
+		return nil, nil, err
+	}
+	if n == encryptionHeader && bytes.HasPrefix(header, []byte(encryptionMagic)) {
+		return content, header, nil
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		content.Close()
+		return nil, nil, err
+	}
+	return content, nil, nil
+}
+
+// newDecryptingReader abre a chave de dados do cabeçalho
+func (s *encryptedStorage) newDecryptingReader(content io.ReadSeekCloser, header []byte, storedSize int64) (*decryptingReader, error) {
+	if binary.BigEndian.Uint32(header[len(encryptionMagic):]) != encryptionChunkSize {
+		return nil, errCorruptedObject
+	}
+	plainSize, err := plainLength(storedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	keyNonce := header[keyHeaderSize : keyHeaderSize+gcmNonceSize]
+	dataKey, err := s.masterKey.Open(nil, keyNonce, header[keyHeaderSize+gcmNonceSize:], header[:keyHeaderSize])
+	if err != nil {
+		return nil, errors.New("não foi possível decifrar a chave do arquivo (chave mestra incorreta?)")
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptingReader{
+		inner:     content,
+		aead:      aead,
+		header:    header,
+		plainSize: plainSize,
+		chunks:    chunkCount(plainSize),
+		chunk:     -1,
+		innerPos:  int64(encryptionHeader),
+	}, nil
+}
+
+// encryptingReader produz o cabeçalho seguido dos blocos cifrados
+type encryptingReader struct {
+	aead   cipher.AEAD
+	header []byte
+	source *bufio.Reader
+	buf    *bytes.Reader
+	plain  []byte
+	index  uint32
+	done   bool
+}
+
+// Read implementa io.Reader
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.nextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	return r.buf.Read(p)
+}
+
+// nextChunk cifra o próximo bloco; o último é o que termina junto com a origem
+func (r *encryptingReader) nextChunk() error {
+	if r.plain == nil {
+		r.plain = make([]byte, encryptionChunkSize)
+	}
+	n, err := io.ReadFull(r.source, r.plain)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	if _, peekErr := r.source.Peek(1); peekErr == io.EOF {
+		r.done = true
+	} else if peekErr != nil {
+		return peekErr
+	}
+
+	sealed := r.aead.Seal(nil, chunkNonce(r.header, uint64(r.index), r.done), r.plain[:n], r.header)
+	r.buf.Reset(sealed)
+	r.index++
+	return nil
+}
+
+// decryptingReader decifra o bloco em que está a posição atual
+type decryptingReader struct {
+	inner     io.ReadSeekCloser
+	aead      cipher.AEAD
+	header    []byte
+	plainSize int64
+	chunks    int64
+
+	pos      int64  // posição no conteúdo decifrado
+	chunk    int64  // índice do bloco em plain (-1 = nenhum)
+	plain    []byte // conteúdo decifrado do bloco atual
+	innerPos int64  // posição atual no objeto cifrado
+}
+
+// Read implementa io.Reader
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	if r.pos >= r.plainSize {
+		return 0, io.EOF
+	}
+
+	index := r.pos / encryptionChunkSize
+	if index != r.chunk {
+		if err := r.load(index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plain[r.pos-index*encryptionChunkSize:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+// Seek implementa io.Seeker sobre o conteúdo decifrado
+func (r *decryptingReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.plainSize
+	default:
+		return 0, errors.New("whence inválido")
+	}
+	if offset < 0 {
+		return 0, errors.New("posição negativa")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// Close fecha o objeto subjacente
+func (r *decryptingReader) Close() error {
+	return r.inner.Close()
+}
+
+// load lê e decifra um bloco, evitando Seek quando a leitura é sequencial
+func (r *decryptingReader) load(index int64) error {
+	offset := int64(encryptionHeader) + index*(encryptionChunkSize+gcmTagSize)
+	if offset != r.innerPos {
+		if _, err := r.inner.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		r.innerPos = offset
+	}
+
+	size := min(encryptionChunkSize, r.plainSize-index*encryptionChunkSize) + gcmTagSize
+	sealed := make([]byte, size)
+	n, err := io.ReadFull(r.inner, sealed)
+	r.innerPos += int64(n)
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errCorruptedObject
+		}
+		return err
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], chunkNonce(r.header, uint64(index), index == r.chunks-1), sealed, r.header)
+	if err != nil {
+		r.chunk = -1
+		return errCorruptedObject
+	}
+	r.plain = plain
+	r.chunk = index
+	return nil
+}
+
+// newGCM cria o AES-256-GCM para a chave
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("a chave deve ter %d bytes", keySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce monta o nonce do bloco: prefixo | índice | marcador de último bloco
+func chunkNonce(header []byte, index uint64, last bool) []byte {
+	nonce := make([]byte, gcmNonceSize)
+	copy(nonce, header[len(encryptionMagic)+4:keyHeaderSize])
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], uint32(index))
+	if last {
+		nonce[gcmNonceSize-1] = 1
+	}
+	return nonce
+}
+
+// chunkCount retorna quantos blocos um conteúdo ocupa (vazio ocupa um bloco)
+func chunkCount(plainSize int64) int64 {
+	return max(1, (plainSize+encryptionChunkSize-1)/encryptionChunkSize)
+}
+
+// encryptedLength retorna o tamanho gravado para um conteúdo de plainSize bytes
+func encryptedLength(plainSize int64) int64 {
+	return int64(encryptionHeader) + plainSize + chunkCount(plainSize)*gcmTagSize
+}
+
+// plainLength é o inverso de encryptedLength
+func plainLength(storedSize int64) (int64, error) {
+	body := storedSize - int64(encryptionHeader)
+	chunks := (body + encryptionChunkSize + gcmTagSize - 1) / (encryptionChunkSize + gcmTagSize)
+	plainSize := body - chunks*gcmTagSize
+	if body < gcmTagSize || plainSize < 0 || encryptedLength(plainSize) != storedSize {
+		return 0, errCorruptedObject
+	}
+	return plainSize, nil
+}
@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+// newEncryptedTestStorage cria um armazenamento cifrado sobre um diretório
+// temporário e retorna também o armazenamento sem criptografia
+func newEncryptedTestStorage(t *testing.T) (*encryptedStorage, Storage) {
+	t.Helper()
+	inner, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewEncryptedStorage(inner, randomBytes(t, keySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store.(*encryptedStorage), inner
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// readObject lê o objeto inteiro, com o erro de abertura ou de leitura
+func readObject(store Storage, key string) ([]byte, error) {
+	content, err := store.Get(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	return io.ReadAll(content)
+}
+
+// rawObject lê os bytes gravados (cifrados)
+func rawObject(t *testing.T, inner Storage, key string) []byte {
+	t.Helper()
+	data, err := readObject(inner, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func putRaw(t *testing.T, inner Storage, key string, data []byte) {
+	t.Helper()
+	if err := inner.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, inner := newEncryptedTestStorage(t)
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 17} {
+		plain := randomBytes(t, size)
+		if err := store.Put(ctx, "livro", bytes.NewReader(plain), int64(size), "application/pdf"); err != nil {
+			t.Fatalf("Put(%d): %v", size, err)
+		}
+
+		raw := rawObject(t, inner, "livro")
+		if int64(len(raw)) != encryptedLength(int64(size)) {
+			t.Errorf("tamanho gravado = %d, esperado %d", len(raw), encryptedLength(int64(size)))
+		}
+		if size >= 16 && bytes.Contains(raw, plain[:16]) {
+			t.Errorf("conteúdo de %d bytes gravado sem cifrar", size)
+		}
+
+		got, err := readObject(store, "livro")
+		if err != nil {
+			t.Fatalf("leitura de %d bytes: %v", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("conteúdo de %d bytes diferente do gravado", size)
+		}
+
+		info, err := store.Stat(ctx, "livro")
+		if err != nil || info.Size != int64(size) {
+			t.Errorf("Stat = %+v, %v; esperado tamanho %d", info, err, size)
+		}
+	}
+}
+
+func TestEncryptedSeek(t *testing.T) {
+	store, _ := newEncryptedTestStorage(t)
+	plain := randomBytes(t, 3*encryptionChunkSize+100)
+	if err := store.Put(context.Background(), "livro", bytes.NewReader(plain), int64(len(plain)), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := store.Get(context.Background(), "livro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer content.Close()
+
+	// Leituras que atravessam blocos, para frente e para trás
+	for _, offset := range []int64{2*encryptionChunkSize - 10, 5, encryptionChunkSize, int64(len(plain)) - 3, 0} {
+		if _, err := content.Seek(offset, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 20)
+		n, err := io.ReadFull(content, buf)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			t.Fatalf("leitura em %d: %v", offset, err)
+		}
+		if !bytes.Equal(buf[:n], plain[offset:offset+int64(n)]) {
+			t.Errorf("conteúdo diferente na posição %d", offset)
+		}
+	}
+
+	if end, err := content.Seek(0, io.SeekEnd); err != nil || end != int64(len(plain)) {
+		t.Errorf("Seek(0, SeekEnd) = %d, %v", end, err)
+	}
+}
+
+func TestEncryptedTamper(t *testing.T) {
+	store, inner := newEncryptedTestStorage(t)
+	plain := randomBytes(t, 2*encryptionChunkSize+100)
+	if err := store.Put(context.Background(), "livro", bytes.NewReader(plain), int64(len(plain)), ""); err != nil {
+		t.Fatal(err)
+	}
+	original := rawObject(t, inner, "livro")
+
+	sealedChunk := encryptionChunkSize + gcmTagSize
+	chunk := func(data []byte, index int) []byte {
+		start := encryptionHeader + index*sealedChunk
+		return data[start:min(start+sealedChunk, len(data))]
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(data []byte) []byte
+	}{
+		{
+			name: "último bloco truncado",
+			tamper: func(data []byte) []byte {
+				return data[:len(data)-10]
+			},
+		},
+		{
+			name: "último bloco removido",
+			tamper: func(data []byte) []byte {
+				return data[:encryptionHeader+2*sealedChunk]
+			},
+		},
+		{
+			name: "blocos reordenados",
+			tamper: func(data []byte) []byte {
+				var out []byte
+				out = append(out, data[:encryptionHeader]...)
+				out = append(out, chunk(data, 1)...)
+				out = append(out, chunk(data, 0)...)
+				return append(out, chunk(data, 2)...)
+			},
+		},
+		{
+			name: "bloco repetido no fim",
+			tamper: func(data []byte) []byte {
+				return append(append([]byte{}, data...), chunk(data, 1)...)
+			},
+		},
+		{
+			name: "marcador de último bloco invertido",
+			tamper: func(data []byte) []byte {
+				// Recifra o último bloco com a chave de dados correta, mas
+				// como se não fosse o último
+				header := data[:encryptionHeader]
+				dataKey, err := store.masterKey.Open(nil, header[keyHeaderSize:keyHeaderSize+gcmNonceSize], header[keyHeaderSize+gcmNonceSize:], header[:keyHeaderSize])
+				if err != nil {
+					t.Fatal(err)
+				}
+				aead, _ := newGCM(dataKey)
+				last, err := aead.Open(nil, chunkNonce(header, 2, true), chunk(data, 2), header)
+				if err != nil {
+					t.Fatal(err)
+				}
+				out := append([]byte{}, data[:encryptionHeader+2*sealedChunk]...)
+				return append(out, aead.Seal(nil, chunkNonce(header, 2, false), last, header)...)
+			},
+		},
+		{
+			name: "bit invertido no conteúdo",
+			tamper: func(data []byte) []byte {
+				data[encryptionHeader+sealedChunk+7] ^= 1
+				return data
+			},
+		},
+		{
+			name: "prefixo do nonce alterado",
+			tamper: func(data []byte) []byte {
+				data[len(encryptionMagic)+4] ^= 1
+				return data
+			},
+		},
+		{
+			name: "tamanho do bloco alterado",
+			tamper: func(data []byte) []byte {
+				data[len(encryptionMagic)+3] ^= 1
+				return data
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			putRaw(t, inner, "livro", tt.tamper(append([]byte{}, original...)))
+			if got, err := readObject(store, "livro"); err == nil {
+				t.Errorf("leitura de %d bytes sem erro, esperado erro", len(got))
+			}
+		})
+	}
+
+	// Depois de restaurado, o objeto volta a ser legível
+	putRaw(t, inner, "livro", original)
+	if got, err := readObject(store, "livro"); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("objeto original ilegível: %v", err)
+	}
+}
+
+func TestEncryptedWrongMasterKey(t *testing.T) {
+	store, inner := newEncryptedTestStorage(t)
+	if err := store.Put(context.Background(), "livro", bytes.NewReader([]byte("conteúdo")), 9, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewEncryptedStorage(inner, randomBytes(t, keySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readObject(other, "livro"); err == nil {
+		t.Error("leitura com outra chave mestra sem erro")
+	}
+}
+
+func TestEncryptedLegacyObject(t *testing.T) {
+	ctx := context.Background()
+	store, inner := newEncryptedTestStorage(t)
+	plain := []byte("%PDF-1.4 gravado antes da criptografia")
+	putRaw(t, inner, "antigo", plain)
+
+	// Objetos sem cabeçalho continuam legíveis
+	if got, err := readObject(store, "antigo"); err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("leitura do objeto antigo: %q, %v", got, err)
+	}
+
+	encrypted, err := EncryptInPlace(ctx, store, "antigo")
+	if err != nil || !encrypted {
+		t.Fatalf("EncryptInPlace = %v, %v", encrypted, err)
+	}
+	if raw := rawObject(t, inner, "antigo"); bytes.Contains(raw, plain) {
+		t.Error("objeto continua sem cifrar")
+	}
+	if got, err := readObject(store, "antigo"); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("leitura depois de cifrar: %q, %v", got, err)
+	}
+
+	if encrypted, err := EncryptInPlace(ctx, store, "antigo"); err != nil || encrypted {
+		t.Errorf("segundo EncryptInPlace = %v, %v; esperado false", encrypted, err)
+	}
+}
+
+func TestPlainLength(t *testing.T) {
+	for _, size := range []int64{0, 1, encryptionChunkSize, 5*encryptionChunkSize + 3} {
+		if got, err := plainLength(encryptedLength(size)); err != nil || got != size {
+			t.Errorf("plainLength(encryptedLength(%d)) = %d, %v", size, got, err)
+		}
+	}
+	for _, stored := range []int64{0, int64(encryptionHeader), int64(encryptionHeader) + gcmTagSize - 1, encryptedLength(encryptionChunkSize) + 1} {
+		if _, err := plainLength(stored); !errors.Is(err, errCorruptedObject) {
+			t.Errorf("plainLength(%d) = %v, esperado errCorruptedObject", stored, err)
+		}
+	}
+}
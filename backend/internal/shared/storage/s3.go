@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud-reader/backend/internal/shared/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Storage implementa Storage em um bucket compatível com S3 (AWS, MinIO, etc.)
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage cria o armazenamento S3 e garante que o bucket exista
+func NewS3Storage(cfg *config.Config) (Storage, error) {
+	if cfg.S3Bucket == "" {
+		return nil, errors.New("S3_BUCKET não configurado")
+	}
+
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar cliente S3: %w", err)
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, cfg.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar bucket S3: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.S3Bucket, minio.MakeBucketOptions{Region: cfg.S3Region}); err != nil {
+			return nil, fmt.Errorf("erro ao criar bucket S3: %w", err)
+		}
+	}
+
+	return &s3Storage{
+		client: client,
+		bucket: cfg.S3Bucket,
+	}, nil
+}
+
+// Put envia o conteúdo para o bucket (multipart quando o tamanho é desconhecido ou grande)
+func (s *s3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar arquivo: %w", err)
+	}
+	return nil
+}
+
+// Get abre o objeto para leitura
+func (s *s3Storage) Get(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject é preguiçoso; o Stat confirma a existência antes de retornar.
+	// O objeto implementa Seek com requisições Range ao servidor.
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s.translateError(err)
+	}
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, s.translateError(err)
+	}
+	return object, nil
+}
+
+// Stat retorna as informações do objeto
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s.translateError(err)
+	}
+
+	return &ObjectInfo{
+		Key:         info.Key,
+		Size:        info.Size,
+		ModTime:     info.LastModified,
+		ContentType: info.ContentType,
+	}, nil
+}
+
+// Delete remove o objeto (o S3 não retorna erro para objetos inexistentes)
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("erro ao deletar arquivo: %w", err)
+	}
+	return nil
+}
+
+// List lista os objetos cujas chaves começam com o prefixo
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("erro ao listar arquivos: %w", object.Err)
+		}
+		objects = append(objects, ObjectInfo{
+			Key:         object.Key,
+			Size:        object.Size,
+			ModTime:     object.LastModified,
+			ContentType: object.ContentType,
+		})
+	}
+
+	return objects, nil
+}
+
+// translateError converte o erro "NoSuchKey" do S3 em ErrNotFound
+func (s *s3Storage) translateError(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// pdfHeaderWindow é onde o cabeçalho %PDF- deve aparecer (leitores aceitam
+	// bytes antes dele dentro do primeiro KB)
+	pdfHeaderWindow = 1024
+
+	// epubMimetype é o conteúdo obrigatório da entrada mimetype do EPUB
+	epubMimetype = "application/epub+zip"
+
+	// epubContainerPath é o arquivo que aponta para o pacote OPF do EPUB
+	epubContainerPath = "META-INF/container.xml"
+)
+
+// ContentMismatchError indica que o conteúdo do arquivo não corresponde ao formato da extensão
+type ContentMismatchError struct {
+	Format string
+	Reason string
+}
+
+// Error implementa a interface error
+func (e *ContentMismatchError) Error() string {
+	return fmt.Sprintf("o conteúdo do arquivo não é um %s válido: %s", e.Format, e.Reason)
+}
+
+// DetectContentType verifica pelos bytes do arquivo se o conteúdo corresponde ao
+// formato indicado pela extensão e retorna o tipo MIME detectado
+func DetectContentType(r io.ReaderAt, size int64, format string) (string, error) {
+	switch format {
+	case "pdf":
+		if err := sniffPDF(r, size); err != nil {
+			return "", err
+		}
+	case "epub":
+		if err := sniffEPUB(r, size); err != nil {
+			return "", err
+		}
+	case "org":
+		if err := sniffText(r, size); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("tipo de arquivo não permitido. Tipos permitidos: %s", AllowedExtensions)
+	}
+	return ContentType(format), nil
+}
+
+// sniffPDF procura o cabeçalho %PDF- no início do arquivo
+func sniffPDF(r io.ReaderAt, size int64) error {
+	head := make([]byte, min(size, pdfHeaderWindow))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return err
+	}
+	if !bytes.Contains(head, []byte("%PDF-")) {
+		return &ContentMismatchError{Format: "PDF", Reason: "cabeçalho %PDF- ausente"}
+	}
+	return nil
+}
+
+// sniffEPUB exige um zip cuja primeira entrada seja mimetype (sem compressão,
+// com application/epub+zip) e que contenha META-INF/container.xml
+func sniffEPUB(r io.ReaderAt, size int64) error {
+	// Cabeçalho local da primeira entrada: assinatura, nome e conteúdo não comprimido
+	headSize := 30 + len("mimetype") + len(epubMimetype)
+	head := make([]byte, min(size, int64(headSize)))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return err
+	}
+	if !bytes.HasPrefix(head, []byte("PK\x03\x04")) {
+		return &ContentMismatchError{Format: "EPUB", Reason: "o arquivo não é um zip"}
+	}
+	if len(head) < headSize || !bytes.Equal(head[30:38], []byte("mimetype")) || !bytes.HasSuffix(head, []byte(epubMimetype)) {
+		return &ContentMismatchError{Format: "EPUB", Reason: "a primeira entrada deve ser mimetype com " + epubMimetype}
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return &ContentMismatchError{Format: "EPUB", Reason: "zip corrompido"}
+	}
+	for _, file := range archive.File {
+		if file.Name == epubContainerPath {
+			return nil
+		}
+	}
+	return &ContentMismatchError{Format: "EPUB", Reason: epubContainerPath + " ausente"}
+}
+
+// sniffText exige texto UTF-8 válido e sem bytes nulos (típicos de binários)
+func sniffText(r io.ReaderAt, size int64) error {
+	reader := io.NewSectionReader(r, 0, size)
+	buf := make([]byte, 32*1024)
+	var carry []byte
+
+	for {
+		n, err := reader.Read(buf[len(carry):])
+		chunk := buf[:len(carry)+n]
+
+		if bytes.IndexByte(chunk, 0) >= 0 {
+			return &ContentMismatchError{Format: "arquivo Org", Reason: "conteúdo binário"}
+		}
+
+		// Uma sequência UTF-8 pode ter sido cortada no fim do bloco
+		valid := len(chunk)
+		if err == nil {
+			for i := len(chunk) - 1; i >= 0 && i >= len(chunk)-utf8.UTFMax; i-- {
+				if utf8.RuneStart(chunk[i]) {
+					if !utf8.FullRune(chunk[i:]) {
+						valid = i
+					}
+					break
+				}
+			}
+		}
+		if !utf8.Valid(chunk[:valid]) {
+			return &ContentMismatchError{Format: "arquivo Org", Reason: "o texto não está em UTF-8"}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		carry = append(carry[:0], chunk[valid:]...)
+		copy(buf, carry)
+	}
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+// SpooledFile é uma cópia temporária de um upload em disco, com o SHA-256
+// calculado durante a própria cópia (o conteúdo é lido uma única vez)
+type SpooledFile struct {
+	file *os.File
+	Hash string
+	Size int64
+}
+
+// Spool copia o reader para um arquivo temporário calculando o hash
+func Spool(r io.Reader) (*SpooledFile, error) {
+	file, err := os.CreateTemp("", "cloud-reader-upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hasher), r)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("erro ao ler arquivo: %w", err)
+	}
+
+	return &SpooledFile{
+		file: file,
+		Hash: hex.EncodeToString(hasher.Sum(nil)),
+		Size: size,
+	}, nil
+}
+
+// Reader retorna o conteúdo do início
+func (f *SpooledFile) Reader() (io.Reader, error) {
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return f.file, nil
+}
+
+// ReaderAt retorna o conteúdo para leitura em posições arbitrárias (ex.: zip)
+func (f *SpooledFile) ReaderAt() io.ReaderAt {
+	return f.file
+}
+
+// DetectContentType confere os bytes do arquivo com o formato e retorna o tipo MIME
+func (f *SpooledFile) DetectContentType(format string) (string, error) {
+	return DetectContentType(f.file, f.Size, format)
+}
+
+// InspectArchive aplica a inspeção de segurança de zip ao arquivo
+func (f *SpooledFile) InspectArchive(ctx context.Context, limits ArchiveLimits) error {
+	return InspectArchive(ctx, f.file, f.Size, limits)
+}
+
+// Close fecha e remove o arquivo temporário
+func (f *SpooledFile) Close() error {
+	f.file.Close()
+	return os.Remove(f.file.Name())
+}
+
+// BlobKey retorna a chave de um conteúdo endereçado pelo SHA-256. Os dois
+// primeiros caracteres formam um subdiretório para não concentrar tudo em um só.
+func BlobKey(hash string) string {
+	return path.Join("blobs", "sha256", hash[:2], hash)
+}
+
+// CoverPrefix retorna o prefixo das miniaturas de capa de um conteúdo. Como o
+// arquivo, as capas são endereçadas pelo hash e compartilhadas entre os livros.
+func CoverPrefix(hash string) string {
+	return path.Join("covers", "sha256", hash[:2], hash) + "/"
+}
+
+// CoverKey retorna a chave da miniatura de capa de um conteúdo no tamanho informado
+func CoverKey(hash, size string) string {
+	return CoverPrefix(hash) + size + ".jpg"
+}
@@ -2,14 +2,9 @@ package storage
 
 import (
 	"fmt"
-	"io"
 	"mime/multipart"
-	"os"
 	"path/filepath"
 	"strings"
-	"time"
-
-	"github.com/google/uuid"
 )
 
 const (
@@ -27,8 +22,12 @@ func ValidateFile(fileHeader *multipart.FileHeader) error {
 		return fmt.Errorf("arquivo muito grande. Tamanho máximo: %d MB", MaxFileSize/(1024*1024))
 	}
 
-	// Valida extensão
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	return ValidateExtension(fileHeader.Filename)
+}
+
+// ValidateExtension valida se a extensão do arquivo é permitida
+func ValidateExtension(filename string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
 	allowed := strings.Split(AllowedExtensions, ",")
 	
 	isAllowed := false
@@ -46,46 +45,15 @@ func ValidateFile(fileHeader *multipart.FileHeader) error {
 	return nil
 }
 
-// SaveFile salva o arquivo no diretório de uploads
-func SaveFile(file multipart.File, userID uint, originalFilename string) (string, string, error) {
-	// Valida extensão
-	ext := strings.ToLower(filepath.Ext(originalFilename))
-	
-	// Gera nome único para o arquivo
-	uniqueName := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
-	
-	// Define caminho do diretório do usuário
-	userDir := filepath.Join("uploads", "books", fmt.Sprintf("%d", userID))
-	
-	// Cria diretório se não existir
-	if err := os.MkdirAll(userDir, 0755); err != nil {
-		return "", "", fmt.Errorf("erro ao criar diretório: %w", err)
-	}
-
-	// Define caminho completo do arquivo
-	filePath := filepath.Join(userDir, uniqueName)
-
-	// Cria arquivo no sistema de arquivos
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return "", "", fmt.Errorf("erro ao criar arquivo: %w", err)
-	}
-	defer dst.Close()
-
-	// Copia conteúdo do arquivo
-	if _, err := io.Copy(dst, file); err != nil {
-		return "", "", fmt.Errorf("erro ao salvar arquivo: %w", err)
-	}
-
-	return filePath, uniqueName, nil
+// UserBooksPrefix retorna o prefixo onde ficavam os arquivos de um usuário antes da deduplicação
+func UserBooksPrefix(userID uint) string {
+	return fmt.Sprintf("books/%d/", userID)
 }
 
-// DeleteFile remove um arquivo do sistema de arquivos
-func DeleteFile(filePath string) error {
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("erro ao deletar arquivo: %w", err)
-	}
-	return nil
+// KeyFromPath converte o FilePath salvo no livro em chave do armazenamento.
+// Registros antigos guardam o caminho relativo ao diretório de trabalho (uploads/books/...).
+func KeyFromPath(filePath string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filePath), "uploads/")
 }
 
 // GetFileFormat retorna o formato do arquivo baseado na extensão
@@ -109,3 +77,16 @@ func ExtractTitle(filename string) string {
 	return strings.TrimSuffix(name, filepath.Ext(name))
 }
 
+// ContentType retorna o tipo MIME correspondente ao formato do livro
+func ContentType(format string) string {
+	switch format {
+	case "pdf":
+		return "application/pdf"
+	case "epub":
+		return "application/epub+zip"
+	case "org":
+		return "text/org; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
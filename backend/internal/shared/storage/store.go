@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"cloud-reader/backend/internal/shared/config"
+)
+
+// ErrNotFound indica que o objeto não existe no armazenamento
+var ErrNotFound = errors.New("arquivo não encontrado")
+
+// ObjectInfo descreve um objeto armazenado
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ModTime     time.Time
+	ContentType string
+}
+
+// Storage define a interface do armazenamento de arquivos (port).
+// As chaves são caminhos relativos separados por "/" (ex.: books/1/arquivo.pdf).
+type Storage interface {
+	// Put grava o conteúdo do reader na chave informada. size pode ser -1 se desconhecido.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get abre o objeto para leitura com suporte a Seek (usado para servir
+	// intervalos de bytes). Retorna ErrNotFound se não existir.
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, error)
+
+	// Stat retorna as informações do objeto. Retorna ErrNotFound se não existir.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// Delete remove o objeto. Remover um objeto inexistente não é erro.
+	Delete(ctx context.Context, key string) error
+
+	// List lista os objetos cujas chaves começam com o prefixo
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// New cria o armazenamento configurado em STORAGE_DRIVER (local ou s3), com
+// criptografia em repouso se uma chave mestra estiver configurada
+func New(cfg *config.Config) (Storage, error) {
+	store, err := newDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	masterKey, err := LoadMasterKey(cfg)
+	if err != nil || masterKey == nil {
+		return store, err
+	}
+	return NewEncryptedStorage(store, masterKey)
+}
+
+// newDriver cria o driver de armazenamento configurado
+func newDriver(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "local", "":
+		return NewLocalStorage(cfg.StorageLocalRoot)
+	case "s3":
+		return NewS3Storage(cfg)
+	default:
+		return nil, fmt.Errorf("driver de armazenamento inválido: %s", cfg.StorageDriver)
+	}
+}
+
+// DeletePrefix remove todos os objetos cujas chaves começam com o prefixo
+func DeletePrefix(ctx context.Context, store Storage, prefix string) error {
+	objects, err := store.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if err := store.Delete(ctx, object.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanKey valida e normaliza uma chave, rejeitando caminhos absolutos ou com ".."
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("chave de armazenamento inválida: %q", key)
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("chave de armazenamento inválida: %q", key)
+	}
+	return cleaned, nil
+}
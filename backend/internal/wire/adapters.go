@@ -0,0 +1,20 @@
+package wire
+
+import (
+	authDomain "cloud-reader/backend/internal/auth/domain"
+	bookApplication "cloud-reader/backend/internal/books/application"
+	exportApplication "cloud-reader/backend/internal/export/application"
+)
+
+// newUserDataRemover combina a remoção dos dados de cada módulo na exclusão de uma conta
+func newUserDataRemover(
+	bookService *bookApplication.BookService,
+	uploadService *bookApplication.UploadService,
+	exportService *exportApplication.ExportService,
+) authDomain.UserDataRemover {
+	return authDomain.UserDataRemovers{
+		authDomain.UserDataRemoverFunc(exportService.DeleteUserExports),
+		authDomain.UserDataRemoverFunc(uploadService.DeleteUserUploads),
+		authDomain.UserDataRemoverFunc(bookService.DeleteUserBooks),
+	}
+}
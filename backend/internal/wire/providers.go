@@ -4,32 +4,136 @@
 package wire
 
 import (
+	adminApplication "cloud-reader/backend/internal/admin/application"
+	adminHttp "cloud-reader/backend/internal/admin/infrastructure/http"
 	authApplication "cloud-reader/backend/internal/auth/application"
+	authDomain "cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/auth/infrastructure/attempts"
 	authHttp "cloud-reader/backend/internal/auth/infrastructure/http"
+	"cloud-reader/backend/internal/auth/infrastructure/oidc"
 	"cloud-reader/backend/internal/auth/infrastructure/repository"
+	"cloud-reader/backend/internal/auth/infrastructure/token"
 	bookApplication "cloud-reader/backend/internal/books/application"
 	bookHttp "cloud-reader/backend/internal/books/infrastructure/http"
 	bookRepo "cloud-reader/backend/internal/books/infrastructure/repository"
+	exportApplication "cloud-reader/backend/internal/export/application"
+	exportHttp "cloud-reader/backend/internal/export/infrastructure/http"
+	exportRepo "cloud-reader/backend/internal/export/infrastructure/repository"
 	"cloud-reader/backend/internal/shared/config"
 	"cloud-reader/backend/internal/shared/database"
+	"cloud-reader/backend/internal/shared/mailer"
+	"cloud-reader/backend/internal/shared/middleware"
+	"cloud-reader/backend/internal/shared/storage"
 
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// InitializeAuthHandler inicializa o handler de autenticação (implementação manual sem Wire)
-func InitializeAuthHandler(db *gorm.DB) *authHttp.AuthHandler {
+// InitializeAuthService inicializa o serviço de autenticação (implementação manual sem Wire)
+func InitializeAuthService(db *gorm.DB, cfg *config.Config) (*authApplication.AuthService, error) {
 	userRepo := repository.NewPostgresUserRepository(db)
-	authService := authApplication.NewAuthService(userRepo)
+	sessionRepo := repository.NewPostgresSessionRepository(db)
+	userTokenRepo := repository.NewPostgresUserTokenRepository(db)
+	recoveryCodeRepo := repository.NewPostgresRecoveryCodeRepository(db)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(db)
+	tokenService, err := token.NewJWTTokenService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	loginTracker, err := attempts.New(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return authApplication.NewAuthService(userRepo, sessionRepo, userTokenRepo, recoveryCodeRepo, apiKeyRepo, tokenService, mail, loginTracker, cfg), nil
+}
+
+// InitializeAuthHandler inicializa o handler de autenticação (implementação manual sem Wire)
+func InitializeAuthHandler(authService *authApplication.AuthService) *authHttp.AuthHandler {
 	return authHttp.NewAuthHandler(authService)
 }
 
-// InitializeBookHandler inicializa o handler de livros (implementação manual sem Wire)
-func InitializeBookHandler(db *gorm.DB) *bookHttp.BookHandler {
+// InitializeOIDCHandler inicializa o handler do login OpenID Connect (implementação manual sem Wire)
+func InitializeOIDCHandler(db *gorm.DB, cfg *config.Config, authService *authApplication.AuthService) *authHttp.OIDCHandler {
+	identityRepo := repository.NewPostgresUserIdentityRepository(db)
+	stateRepo := repository.NewPostgresOIDCStateRepository(db)
+	provider := oidc.NewOIDCProvider(cfg)
+	oidcService := authApplication.NewOIDCService(authService, identityRepo, stateRepo, provider, cfg)
+	return authHttp.NewOIDCHandler(oidcService)
+}
+
+// InitializeAccountHandler inicializa o handler da conta do usuário (implementação manual sem Wire)
+func InitializeAccountHandler(authService *authApplication.AuthService, dataRemover authDomain.UserDataRemover) *authHttp.AccountHandler {
+	accountService := authApplication.NewAccountService(authService, dataRemover)
+	return authHttp.NewAccountHandler(accountService)
+}
+
+// InitializeAuthMiddleware inicializa o middleware que protege as rotas autenticadas
+func InitializeAuthMiddleware(authService *authApplication.AuthService, cfg *config.Config) gin.HandlerFunc {
+	return middleware.AuthMiddleware(authHttp.NewAuthenticator(authService), cfg.AllowDevUserHeader)
+}
+
+// InitializeStorage inicializa o armazenamento de arquivos configurado (local ou S3)
+func InitializeStorage(cfg *config.Config) (storage.Storage, error) {
+	return storage.New(cfg)
+}
+
+// InitializeBookService inicializa o serviço de livros (implementação manual sem Wire)
+func InitializeBookService(db *gorm.DB, cfg *config.Config, store storage.Storage) *bookApplication.BookService {
 	bookRepository := bookRepo.NewPostgresBookRepository(db)
-	bookService := bookApplication.NewBookService(bookRepository)
+	blobRepository := bookRepo.NewPostgresBlobRepository(db)
+	usageRepository := bookRepo.NewPostgresUserStorageRepository(db)
+	return bookApplication.NewBookService(bookRepository, blobRepository, usageRepository, store, cfg)
+}
+
+// InitializeBookHandler inicializa o handler de livros (implementação manual sem Wire)
+func InitializeBookHandler(bookService *bookApplication.BookService) *bookHttp.BookHandler {
 	return bookHttp.NewBookHandler(bookService)
 }
 
+// InitializeUploadService inicializa o serviço de uploads resumíveis (implementação manual sem Wire)
+func InitializeUploadService(db *gorm.DB, cfg *config.Config, bookService *bookApplication.BookService) *bookApplication.UploadService {
+	uploadRepository := bookRepo.NewPostgresUploadRepository(db)
+	return bookApplication.NewUploadService(bookService, uploadRepository, cfg)
+}
+
+// InitializeStorageCheckService inicializa a verificação de consistência do armazenamento (implementação manual sem Wire)
+func InitializeStorageCheckService(db *gorm.DB, cfg *config.Config, store storage.Storage) *bookApplication.StorageCheckService {
+	bookRepository := bookRepo.NewPostgresBookRepository(db)
+	blobRepository := bookRepo.NewPostgresBlobRepository(db)
+	return bookApplication.NewStorageCheckService(bookRepository, blobRepository, store, cfg)
+}
+
+// InitializeUploadHandler inicializa o handler de uploads resumíveis (implementação manual sem Wire)
+func InitializeUploadHandler(uploadService *bookApplication.UploadService, cfg *config.Config) *bookHttp.UploadHandler {
+	return bookHttp.NewUploadHandler(uploadService, cfg)
+}
+
+// InitializeExportService inicializa o serviço de exportação de dados (implementação manual sem Wire)
+func InitializeExportService(db *gorm.DB, cfg *config.Config, authService *authApplication.AuthService, bookService *bookApplication.BookService) *exportApplication.ExportService {
+	exportRepository := exportRepo.NewPostgresExportRepository(db)
+	return exportApplication.NewExportService(exportRepository, authService, bookService, cfg)
+}
+
+// InitializeExportHandler inicializa o handler de exportação de dados (implementação manual sem Wire)
+func InitializeExportHandler(exportService *exportApplication.ExportService) *exportHttp.ExportHandler {
+	return exportHttp.NewExportHandler(exportService)
+}
+
+// InitializeUserDataRemover inicializa a remoção dos dados dos módulos na exclusão de contas
+func InitializeUserDataRemover(bookService *bookApplication.BookService, uploadService *bookApplication.UploadService, exportService *exportApplication.ExportService) authDomain.UserDataRemover {
+	return newUserDataRemover(bookService, uploadService, exportService)
+}
+
+// InitializeAdminHandler inicializa o handler de administração (implementação manual sem Wire)
+func InitializeAdminHandler(authService *authApplication.AuthService, bookService *bookApplication.BookService, dataRemover authDomain.UserDataRemover) *adminHttp.AdminHandler {
+	adminService := adminApplication.NewAdminService(authService, bookService, dataRemover)
+	return adminHttp.NewAdminHandler(adminService)
+}
+
 // InitializeConfig inicializa as configurações
 func InitializeConfig() *config.Config {
 	return config.Load()
@@ -4,11 +4,25 @@
 package wire
 
 import (
+	adminApplication "cloud-reader/backend/internal/admin/application"
+	adminHttp "cloud-reader/backend/internal/admin/infrastructure/http"
 	"cloud-reader/backend/internal/auth/application"
+	"cloud-reader/backend/internal/auth/domain"
+	"cloud-reader/backend/internal/auth/infrastructure/attempts"
 	authHttp "cloud-reader/backend/internal/auth/infrastructure/http"
+	"cloud-reader/backend/internal/auth/infrastructure/oidc"
 	"cloud-reader/backend/internal/auth/infrastructure/repository"
+	"cloud-reader/backend/internal/auth/infrastructure/token"
+	bookApplication "cloud-reader/backend/internal/books/application"
+	bookHttp "cloud-reader/backend/internal/books/infrastructure/http"
+	bookRepo "cloud-reader/backend/internal/books/infrastructure/repository"
+	exportApplication "cloud-reader/backend/internal/export/application"
+	exportHttp "cloud-reader/backend/internal/export/infrastructure/http"
+	exportRepo "cloud-reader/backend/internal/export/infrastructure/repository"
 	"cloud-reader/backend/internal/shared/config"
 	"cloud-reader/backend/internal/shared/database"
+	"cloud-reader/backend/internal/shared/mailer"
+	"cloud-reader/backend/internal/shared/storage"
 
 	"github.com/google/wire"
 	"gorm.io/gorm"
@@ -26,13 +40,112 @@ func InitializeDatabase(cfg *config.Config) (*gorm.DB, error) {
 	return nil, nil
 }
 
-// InitializeAuthHandler inicializa o handler de autenticação com todas as dependências
-func InitializeAuthHandler(db *gorm.DB) (*authHttp.AuthHandler, error) {
+// InitializeAuthService inicializa o serviço de autenticação com todas as dependências
+func InitializeAuthService(db *gorm.DB, cfg *config.Config) (*application.AuthService, error) {
 	wire.Build(
 		repository.NewPostgresUserRepository,
+		repository.NewPostgresSessionRepository,
+		repository.NewPostgresUserTokenRepository,
+		repository.NewPostgresRecoveryCodeRepository,
+		repository.NewPostgresAPIKeyRepository,
+		token.NewJWTTokenService,
+		mailer.New,
+		attempts.New,
 		application.NewAuthService,
-		authHttp.NewAuthHandler,
 	)
 	return nil, nil
 }
 
+// InitializeAuthHandler inicializa o handler de autenticação
+func InitializeAuthHandler(authService *application.AuthService) *authHttp.AuthHandler {
+	wire.Build(authHttp.NewAuthHandler)
+	return nil
+}
+
+// InitializeOIDCHandler inicializa o handler do login OpenID Connect
+func InitializeOIDCHandler(db *gorm.DB, cfg *config.Config, authService *application.AuthService) *authHttp.OIDCHandler {
+	wire.Build(
+		repository.NewPostgresUserIdentityRepository,
+		repository.NewPostgresOIDCStateRepository,
+		oidc.NewOIDCProvider,
+		application.NewOIDCService,
+		authHttp.NewOIDCHandler,
+	)
+	return nil
+}
+
+// InitializeStorage inicializa o armazenamento de arquivos configurado
+func InitializeStorage(cfg *config.Config) (storage.Storage, error) {
+	wire.Build(storage.New)
+	return nil, nil
+}
+
+// InitializeBookService inicializa o serviço de livros
+func InitializeBookService(db *gorm.DB, cfg *config.Config, store storage.Storage) *bookApplication.BookService {
+	wire.Build(
+		bookRepo.NewPostgresBookRepository,
+		bookRepo.NewPostgresBlobRepository,
+		bookRepo.NewPostgresUserStorageRepository,
+		bookApplication.NewBookService,
+	)
+	return nil
+}
+
+// InitializeBookHandler inicializa o handler de livros
+func InitializeBookHandler(bookService *bookApplication.BookService) *bookHttp.BookHandler {
+	wire.Build(bookHttp.NewBookHandler)
+	return nil
+}
+
+// InitializeUploadService inicializa o serviço de uploads resumíveis
+func InitializeUploadService(db *gorm.DB, cfg *config.Config, bookService *bookApplication.BookService) *bookApplication.UploadService {
+	wire.Build(bookRepo.NewPostgresUploadRepository, bookApplication.NewUploadService)
+	return nil
+}
+
+// InitializeStorageCheckService inicializa a verificação de consistência do armazenamento
+func InitializeStorageCheckService(db *gorm.DB, cfg *config.Config, store storage.Storage) *bookApplication.StorageCheckService {
+	wire.Build(bookRepo.NewPostgresBookRepository, bookRepo.NewPostgresBlobRepository, bookApplication.NewStorageCheckService)
+	return nil
+}
+
+// InitializeUploadHandler inicializa o handler de uploads resumíveis
+func InitializeUploadHandler(uploadService *bookApplication.UploadService, cfg *config.Config) *bookHttp.UploadHandler {
+	wire.Build(bookHttp.NewUploadHandler)
+	return nil
+}
+
+// InitializeExportService inicializa o serviço de exportação de dados
+func InitializeExportService(db *gorm.DB, cfg *config.Config, authService *application.AuthService, bookService *bookApplication.BookService) *exportApplication.ExportService {
+	wire.Build(
+		exportRepo.NewPostgresExportRepository,
+		wire.Bind(new(exportApplication.AccountReader), new(*application.AuthService)),
+		wire.Bind(new(exportApplication.LibraryReader), new(*bookApplication.BookService)),
+		exportApplication.NewExportService,
+	)
+	return nil
+}
+
+// InitializeExportHandler inicializa o handler de exportação de dados
+func InitializeExportHandler(exportService *exportApplication.ExportService) *exportHttp.ExportHandler {
+	wire.Build(exportHttp.NewExportHandler)
+	return nil
+}
+
+// InitializeUserDataRemover inicializa a remoção dos dados dos módulos na exclusão de contas
+func InitializeUserDataRemover(bookService *bookApplication.BookService, uploadService *bookApplication.UploadService, exportService *exportApplication.ExportService) domain.UserDataRemover {
+	wire.Build(newUserDataRemover)
+	return nil
+}
+
+// InitializeAdminHandler inicializa o handler de administração
+func InitializeAdminHandler(authService *application.AuthService, bookService *bookApplication.BookService, dataRemover domain.UserDataRemover) *adminHttp.AdminHandler {
+	wire.Build(adminApplication.NewAdminService, adminHttp.NewAdminHandler)
+	return nil
+}
+
+// InitializeAccountHandler inicializa o handler da conta do usuário
+func InitializeAccountHandler(authService *application.AuthService, dataRemover domain.UserDataRemover) *authHttp.AccountHandler {
+	wire.Build(application.NewAccountService, authHttp.NewAccountHandler)
+	return nil
+}
@@ -0,0 +1,392 @@
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ContainerPath é o arquivo que aponta para o pacote OPF
+	ContainerPath = "META-INF/container.xml"
+
+	// maxXMLSize limita a leitura do container e do OPF
+	maxXMLSize = 8 << 20
+)
+
+// Creator é um autor, editor, tradutor etc. (role usa os códigos MARC: aut, edt, trl...)
+type Creator struct {
+	Name   string `json:"name"`
+	Role   string `json:"role,omitempty"`
+	FileAs string `json:"file_as,omitempty"`
+}
+
+// Identifier é um identificador do livro (scheme: isbn, uuid, asin, doi...)
+type Identifier struct {
+	Scheme string `json:"scheme,omitempty"`
+	Value  string `json:"value"`
+}
+
+// Metadata são os metadados Dublin Core do OPF e a série (Calibre ou EPUB 3)
+type Metadata struct {
+	Title       string
+	Creators    []Creator
+	Language    string
+	Publisher   string
+	Date        string // Data de publicação como está no arquivo (AAAA, AAAA-MM-DD...)
+	Identifiers []Identifier
+	Description string
+	Subjects    []string
+	Series      string
+	SeriesIndex *float64
+}
+
+// ISBN retorna o primeiro ISBN entre os identificadores, sem hífens
+func (m *Metadata) ISBN() string {
+	for _, identifier := range m.Identifiers {
+		if identifier.Scheme == "isbn" {
+			return identifier.Value
+		}
+	}
+	return ""
+}
+
+// ManifestItem é um recurso declarado no manifest do OPF
+type ManifestItem struct {
+	ID         string
+	Path       string // Caminho dentro do zip (href resolvido a partir do OPF)
+	MediaType  string
+	Properties []string
+}
+
+// Package é o pacote OPF de um EPUB
+type Package struct {
+	Path     string // Caminho do OPF dentro do zip
+	Version  string
+	Metadata Metadata
+	Manifest []ManifestItem
+
+	coverID string // meta name="cover" (EPUB 2)
+	archive *zip.Reader
+}
+
+// Cover retorna a imagem de capa: o item com a propriedade cover-image (EPUB 3)
+// ou o indicado por meta name="cover" (EPUB 2). Retorna nil se não houver.
+func (p *Package) Cover() *ManifestItem {
+	for i := range p.Manifest {
+		for _, property := range p.Manifest[i].Properties {
+			if property == "cover-image" {
+				return &p.Manifest[i]
+			}
+		}
+	}
+	for i := range p.Manifest {
+		item := &p.Manifest[i]
+		if p.coverID != "" && item.ID == p.coverID && strings.HasPrefix(item.MediaType, "image/") {
+			return item
+		}
+	}
+	return nil
+}
+
+// OpenItem abre um recurso do manifest para leitura
+func (p *Package) OpenItem(item *ManifestItem) (io.ReadCloser, error) {
+	file, err := p.archive.Open(item.Path)
+	if err != nil {
+		return nil, fmt.Errorf("epub sem %s", item.Path)
+	}
+	return file, nil
+}
+
+// Open lê o container e o OPF de um EPUB
+func Open(r io.ReaderAt, size int64) (*Package, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("epub inválido: %w", err)
+	}
+	return Read(archive)
+}
+
+// Read lê o container e o OPF de um zip já aberto
+func Read(archive *zip.Reader) (*Package, error) {
+	var container struct {
+		Rootfiles []struct {
+			FullPath  string `xml:"full-path,attr"`
+			MediaType string `xml:"media-type,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeEntry(archive, ContainerPath, &container); err != nil {
+		return nil, err
+	}
+
+	opfPath := ""
+	for _, rootfile := range container.Rootfiles {
+		if rootfile.MediaType == "" || rootfile.MediaType == "application/oebps-package+xml" {
+			opfPath = rootfile.FullPath
+			break
+		}
+	}
+	if opfPath == "" {
+		return nil, errors.New("epub sem pacote OPF")
+	}
+
+	var opf opfPackage
+	if err := decodeEntry(archive, opfPath, &opf); err != nil {
+		return nil, err
+	}
+
+	pkg := opf.toPackage(opfPath)
+	pkg.archive = archive
+	return pkg, nil
+}
+
+// opfPackage é a estrutura XML do OPF. Os nomes não têm namespace: o
+// encoding/xml aceita dc:title, opf:role etc. pelo nome local.
+type opfPackage struct {
+	Version  string `xml:"version,attr"`
+	Metadata struct {
+		Titles      []opfText `xml:"title"`
+		Creators    []opfText `xml:"creator"`
+		Languages   []opfText `xml:"language"`
+		Publishers  []opfText `xml:"publisher"`
+		Dates       []opfText `xml:"date"`
+		Identifiers []opfText `xml:"identifier"`
+		Description []opfText `xml:"description"`
+		Subjects    []opfText `xml:"subject"`
+		Metas       []opfMeta `xml:"meta"`
+	} `xml:"metadata"`
+	Items []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+}
+
+// opfText é um elemento Dublin Core com os atributos do EPUB 2 (opf:role, opf:scheme...)
+type opfText struct {
+	ID     string `xml:"id,attr"`
+	Role   string `xml:"role,attr"`
+	FileAs string `xml:"file-as,attr"`
+	Scheme string `xml:"scheme,attr"`
+	Event  string `xml:"event,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// opfMeta cobre o meta do EPUB 2 (name/content) e o do EPUB 3 (property/refines)
+type opfMeta struct {
+	Name     string `xml:"name,attr"`
+	Content  string `xml:"content,attr"`
+	Property string `xml:"property,attr"`
+	Refines  string `xml:"refines,attr"`
+	ID       string `xml:"id,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// toPackage converte o XML em Package, aplicando os refinamentos do EPUB 3
+func (o *opfPackage) toPackage(opfPath string) *Package {
+	refines := make(map[string]map[string]string)
+	for _, meta := range o.Metadata.Metas {
+		if meta.Refines == "" || meta.Property == "" {
+			continue
+		}
+		id := strings.TrimPrefix(meta.Refines, "#")
+		if refines[id] == nil {
+			refines[id] = make(map[string]string)
+		}
+		refines[id][meta.Property] = clean(meta.Value)
+	}
+
+	pkg := &Package{Path: opfPath, Version: o.Version}
+	metadata := &pkg.Metadata
+
+	metadata.Title = mainTitle(o.Metadata.Titles, refines)
+	metadata.Language = first(o.Metadata.Languages)
+	metadata.Publisher = first(o.Metadata.Publishers)
+	metadata.Date = publicationDate(o.Metadata.Dates)
+	metadata.Description = first(o.Metadata.Description)
+
+	for _, creator := range o.Metadata.Creators {
+		name := clean(creator.Value)
+		if name == "" {
+			continue
+		}
+		role, fileAs := creator.Role, creator.FileAs
+		if refined := refines[creator.ID]; refined != nil {
+			role = firstNonEmpty(refined["role"], role)
+			fileAs = firstNonEmpty(refined["file-as"], fileAs)
+		}
+		metadata.Creators = append(metadata.Creators, Creator{Name: name, Role: role, FileAs: fileAs})
+	}
+
+	for _, identifier := range o.Metadata.Identifiers {
+		scheme := identifier.Scheme
+		if refined := refines[identifier.ID]; refined != nil {
+			scheme = firstNonEmpty(refined["identifier-type"], scheme)
+		}
+		if parsed, ok := parseIdentifier(scheme, clean(identifier.Value)); ok {
+			metadata.Identifiers = append(metadata.Identifiers, parsed)
+		}
+	}
+
+	for _, subject := range o.Metadata.Subjects {
+		if value := clean(subject.Value); value != "" {
+			metadata.Subjects = append(metadata.Subjects, value)
+		}
+	}
+
+	for _, item := range o.Items {
+		// O href é relativo ao OPF e pode vir codificado como URL
+		href, err := url.PathUnescape(item.Href)
+		if err != nil || item.Href == "" {
+			continue
+		}
+		pkg.Manifest = append(pkg.Manifest, ManifestItem{
+			ID:         item.ID,
+			Path:       path.Join(path.Dir(opfPath), href),
+			MediaType:  strings.TrimSpace(item.MediaType),
+			Properties: strings.Fields(item.Properties),
+		})
+	}
+
+	for _, meta := range o.Metadata.Metas {
+		switch {
+		case meta.Name == "cover":
+			pkg.coverID = strings.TrimSpace(meta.Content)
+		case meta.Name == "calibre:series":
+			metadata.Series = clean(meta.Content)
+		case meta.Name == "calibre:series_index":
+			metadata.SeriesIndex = parseIndex(meta.Content)
+		case meta.Property == "belongs-to-collection" && metadata.Series == "":
+			// EPUB 3: coleção do tipo series, com a posição em group-position
+			refined := refines[meta.ID]
+			if refined["collection-type"] == "" || refined["collection-type"] == "series" {
+				metadata.Series = clean(meta.Value)
+				metadata.SeriesIndex = parseIndex(refined["group-position"])
+			}
+		}
+	}
+
+	return pkg
+}
+
+// mainTitle prefere o título do tipo main (EPUB 3) e usa o primeiro como padrão
+func mainTitle(titles []opfText, refines map[string]map[string]string) string {
+	for _, title := range titles {
+		if refines[title.ID]["title-type"] == "main" {
+			return clean(title.Value)
+		}
+	}
+	return first(titles)
+}
+
+// publicationDate prefere a data do evento publication (EPUB 2). O Calibre grava
+// 0101-01-01 quando a data é desconhecida.
+func publicationDate(dates []opfText) string {
+	for _, event := range []string{"publication", ""} {
+		for _, date := range dates {
+			value := clean(date.Value)
+			if strings.EqualFold(date.Event, event) && value != "" && !strings.HasPrefix(value, "0101-") {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	isbnPattern   = regexp.MustCompile(`^(97[89])?\d{9}[\dX]$`)
+	schemePattern = regexp.MustCompile(`(?i)^(urn:)?([a-z]+):(.+)$`)
+)
+
+// parseIdentifier normaliza o esquema (ISBN, UUID...) a partir do atributo
+// opf:scheme, do refinamento identifier-type ou do prefixo urn:isbn:
+func parseIdentifier(scheme, value string) (Identifier, bool) {
+	if value == "" {
+		return Identifier{}, false
+	}
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+
+	if match := schemePattern.FindStringSubmatch(value); match != nil && (scheme == "" || strings.EqualFold(match[2], scheme)) {
+		if prefix := strings.ToLower(match[2]); prefix != "http" && prefix != "https" {
+			scheme, value = prefix, match[3]
+		}
+	}
+
+	if scheme == "isbn" || scheme == "" {
+		digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(value))
+		if isbnPattern.MatchString(digits) {
+			return Identifier{Scheme: "isbn", Value: digits}, true
+		}
+	}
+	return Identifier{Scheme: scheme, Value: value}, true
+}
+
+// parseIndex converte a posição na série (aceita "2", "2.5")
+func parseIndex(value string) *float64 {
+	index, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil
+	}
+	return &index
+}
+
+// decodeEntry lê um XML do zip
+func decodeEntry(archive *zip.Reader, name string, v interface{}) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("epub sem %s", name)
+	}
+	defer file.Close()
+
+	if err := newDecoder(io.LimitReader(file, maxXMLSize)).Decode(v); err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", name, err)
+	}
+	return nil
+}
+
+// newDecoder cria o decoder XML, aceitando as declarações de encoding comuns
+// (UTF-8 já é o padrão)
+func newDecoder(r io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-8", "utf8", "us-ascii", "ascii":
+			return input, nil
+		}
+		return nil, fmt.Errorf("encoding não suportado: %s", charset)
+	}
+	return decoder
+}
+
+// clean remove espaços extras e quebras de linha
+func clean(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// first retorna o primeiro valor não vazio
+func first(values []opfText) string {
+	for _, value := range values {
+		if cleaned := clean(value.Value); cleaned != "" {
+			return cleaned
+		}
+	}
+	return ""
+}
+
+// firstNonEmpty retorna o primeiro texto não vazio
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
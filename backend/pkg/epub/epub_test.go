@@ -0,0 +1,295 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const containerXML = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
+
+const opf2 = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="isbn">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>  Dom
+      Casmurro </dc:title>
+    <dc:creator opf:role="aut" opf:file-as="Assis, Machado de">Machado de Assis</dc:creator>
+    <dc:language>pt-BR</dc:language>
+    <dc:date opf:event="modification">2020-01-01</dc:date>
+    <dc:date opf:event="publication">1899</dc:date>
+    <dc:identifier id="isbn" opf:scheme="ISBN">978-85-359-0277-8</dc:identifier>
+    <dc:identifier opf:scheme="uuid">urn:uuid:1b4e28ba-2fa1-11d2-883f-0016d3cca427</dc:identifier>
+    <dc:subject>Ficção</dc:subject>
+    <meta name="calibre:series" content="Romances"/>
+    <meta name="calibre:series_index" content="2.5"/>
+    <meta name="cover" content="capa"/>
+  </metadata>
+  <manifest>
+    <item id="capa" href="images/capa%20grande.jpg" media-type="image/jpeg"/>
+    <item id="cap1" href="cap1.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+</package>`
+
+const opf3 = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title id="sub">Um subtítulo</dc:title>
+    <dc:title id="main">Título Principal</dc:title>
+    <meta refines="#main" property="title-type">main</meta>
+    <dc:creator id="c1">Autora</dc:creator>
+    <meta refines="#c1" property="role" scheme="marc:relators">aut</meta>
+    <dc:identifier id="uid">urn:isbn:9788535902778</dc:identifier>
+    <meta property="belongs-to-collection" id="col">Série</meta>
+    <meta refines="#col" property="collection-type">series</meta>
+    <meta refines="#col" property="group-position">3</meta>
+    <meta property="dcterms:modified">2024-01-01T00:00:00Z</meta>
+  </metadata>
+  <manifest>
+    <item id="img" href="capa.png" media-type="image/png" properties="cover-image"/>
+  </manifest>
+</package>`
+
+// buildEPUB monta um EPUB com mimetype, container e as entradas informadas
+// (pares nome, conteúdo)
+func buildEPUB(t testing.TB, entries ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("application/epub+zip"))
+	for i := 0; i+1 < len(entries); i += 2 {
+		w, err := zw.Create(entries[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(entries[i+1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func openBytes(data []byte) (*Package, error) {
+	return Open(bytes.NewReader(data), int64(len(data)))
+}
+
+func TestOpenEPUB2(t *testing.T) {
+	pkg, err := openBytes(buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", opf2))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	index := 2.5
+	want := Metadata{
+		Title:       "Dom Casmurro",
+		Creators:    []Creator{{Name: "Machado de Assis", Role: "aut", FileAs: "Assis, Machado de"}},
+		Language:    "pt-BR",
+		Date:        "1899",
+		Subjects:    []string{"Ficção"},
+		Series:      "Romances",
+		SeriesIndex: &index,
+		Identifiers: []Identifier{
+			{Scheme: "isbn", Value: "9788535902778"},
+			{Scheme: "uuid", Value: "1b4e28ba-2fa1-11d2-883f-0016d3cca427"},
+		},
+	}
+	if !reflect.DeepEqual(pkg.Metadata, want) {
+		t.Errorf("Metadata = %+v\nesperado  %+v", pkg.Metadata, want)
+	}
+	if pkg.Version != "2.0" || pkg.Path != "OEBPS/content.opf" {
+		t.Errorf("Version = %q, Path = %q", pkg.Version, pkg.Path)
+	}
+
+	cover := pkg.Cover()
+	if cover == nil || cover.Path != "OEBPS/images/capa grande.jpg" {
+		t.Errorf("Cover = %+v, esperado OEBPS/images/capa grande.jpg", cover)
+	}
+}
+
+func TestOpenEPUB3(t *testing.T) {
+	pkg, err := openBytes(buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", opf3))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	metadata := pkg.Metadata
+	if metadata.Title != "Título Principal" {
+		t.Errorf("Title = %q", metadata.Title)
+	}
+	if len(metadata.Creators) != 1 || metadata.Creators[0].Role != "aut" {
+		t.Errorf("Creators = %+v", metadata.Creators)
+	}
+	if metadata.ISBN() != "9788535902778" {
+		t.Errorf("ISBN = %q", metadata.ISBN())
+	}
+	if metadata.Series != "Série" || metadata.SeriesIndex == nil || *metadata.SeriesIndex != 3 {
+		t.Errorf("Series = %q, SeriesIndex = %v", metadata.Series, metadata.SeriesIndex)
+	}
+	if cover := pkg.Cover(); cover == nil || cover.ID != "img" {
+		t.Errorf("Cover = %+v", cover)
+	}
+}
+
+func TestOpenMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "vazio", data: nil},
+		{name: "não é zip", data: []byte("PK\x03\x04 isto não é um zip")},
+		{name: "sem container", data: buildEPUB(t, "OEBPS/content.opf", opf2)},
+		{name: "container inválido", data: buildEPUB(t, ContainerPath, "<container><rootfiles>")},
+		{name: "container sem rootfile", data: buildEPUB(t, ContainerPath, "<container/>")},
+		{
+			name: "rootfile de outro tipo",
+			data: buildEPUB(t, ContainerPath, `<container><rootfiles><rootfile full-path="a.pdf" media-type="application/pdf"/></rootfiles></container>`),
+		},
+		{name: "sem OPF", data: buildEPUB(t, ContainerPath, containerXML)},
+		{name: "OPF inválido", data: buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", "<package><metadata>")},
+		{
+			name: "encoding não suportado",
+			data: buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", `<?xml version="1.0" encoding="ISO-8859-1"?><package/>`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := openBytes(tt.data); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
+
+func TestOpenTruncated(t *testing.T) {
+	data := buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", opf2)
+	for size := 0; size < len(data); size++ {
+		// O diretório central fica no fim: qualquer corte invalida o zip
+		if _, err := openBytes(data[:size]); err == nil {
+			t.Fatalf("Open com %d de %d bytes: esperado erro", size, len(data))
+		}
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opf  string
+	}{
+		{name: "EPUB 2", opf: opf2},
+		{name: "EPUB 3", opf: opf3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", tt.opf, "OEBPS/cap1.xhtml", "<html/>")
+			original, err := openBytes(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			archive, _ := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+			// Sem alterações, nada é escrito
+			var buf bytes.Buffer
+			metadata := original.Metadata
+			if err := Rewrite(archive, &buf, &metadata); !errors.Is(err, ErrNotModified) {
+				t.Fatalf("Rewrite sem alterações: %v, esperado ErrNotModified", err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("Rewrite sem alterações escreveu %d bytes", buf.Len())
+			}
+
+			metadata.Title = "Novo <Título> & cia"
+			metadata.Creators = []Creator{{Name: "Outra Autora", Role: "aut"}}
+			metadata.Identifiers = []Identifier{{Scheme: "isbn", Value: "8535902775"}}
+			metadata.Series = ""
+			metadata.SeriesIndex = nil
+			if err := Rewrite(archive, &buf, &metadata); err != nil {
+				t.Fatalf("Rewrite: %v", err)
+			}
+
+			rewritten, err := openBytes(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Open do EPUB regravado: %v", err)
+			}
+			got := rewritten.Metadata
+			if got.Title != metadata.Title || !reflect.DeepEqual(got.Creators, metadata.Creators) {
+				t.Errorf("Title = %q, Creators = %+v", got.Title, got.Creators)
+			}
+			if got.ISBN() != "8535902775" || got.Series != "" || got.Language != original.Metadata.Language {
+				t.Errorf("ISBN = %q, Series = %q, Language = %q", got.ISBN(), got.Series, got.Language)
+			}
+
+			// O mimetype continua a primeira entrada, sem compressão
+			zr, _ := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if first := zr.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+				t.Errorf("primeira entrada = %s (método %d)", first.Name, first.Method)
+			}
+			if len(zr.File) != len(archive.File) {
+				t.Errorf("%d entradas, esperado %d", len(zr.File), len(archive.File))
+			}
+		})
+	}
+}
+
+func TestRewriteMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		opf  string
+	}{
+		{name: "metadata vazio", opf: `<package version="2.0"><metadata/></package>`},
+		{name: "sem metadata", opf: `<package version="2.0"><manifest/></package>`},
+		{name: "OEB 1.x", opf: `<package version="2.0"><metadata><dc-metadata/></metadata></package>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", tt.opf)
+			archive, _ := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			err := Rewrite(archive, &bytes.Buffer{}, &Metadata{Title: "Novo"})
+			if err == nil || errors.Is(err, ErrNotModified) {
+				t.Errorf("Rewrite = %v, esperado erro", err)
+			}
+		})
+	}
+}
+
+func FuzzRead(f *testing.F) {
+	f.Add(opf2)
+	f.Add(opf3)
+	f.Add(`<package version="3.0"><metadata><meta refines="#x" property="role">aut</meta></metadata></package>`)
+
+	f.Fuzz(func(t *testing.T, opf string) {
+		data := buildEPUB(t, ContainerPath, containerXML, "OEBPS/content.opf", opf)
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkg, err := Read(archive)
+		if err != nil {
+			return
+		}
+		pkg.Cover()
+
+		// A regravação também não pode entrar em pânico, e o resultado tem que
+		// ser um EPUB legível
+		metadata := pkg.Metadata
+		metadata.Title = strings.ToUpper(metadata.Title) + " (editado)"
+		var buf bytes.Buffer
+		if err := Rewrite(archive, &buf, &metadata); err != nil {
+			return
+		}
+		if _, err := openBytes(buf.Bytes()); err != nil {
+			t.Errorf("EPUB regravado ilegível: %v", err)
+		}
+	})
+}
@@ -0,0 +1,490 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+	opfNamespace = "http://www.idpf.org/2007/opf"
+)
+
+// ErrNotModified indica que os metadados informados já são os do OPF
+var ErrNotModified = errors.New("metadados do epub sem alterações")
+
+// Rewrite copia o EPUB para w com os metadados do OPF atualizados. Só os
+// grupos que mudaram (título, autores, idioma, editora, data, descrição,
+// assuntos, ISBN e série) são regravados; o restante do OPF e as demais
+// entradas do zip são copiados sem alteração. Retorna ErrNotModified, sem
+// escrever nada, se nenhum grupo mudou.
+func Rewrite(archive *zip.Reader, w io.Writer, metadata *Metadata) error {
+	pkg, err := Read(archive)
+	if err != nil {
+		return err
+	}
+
+	file, err := archive.Open(pkg.Path)
+	if err != nil {
+		return fmt.Errorf("epub sem %s", pkg.Path)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxXMLSize))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	opf, err := rewriteOPF(data, pkg, metadata, time.Now())
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	replaced := false
+	for _, entry := range archive.File {
+		if entry.Name != pkg.Path {
+			// Cópia sem recompressão: o mimetype continua primeiro e sem compressão
+			if err := zw.Copy(entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     entry.Name,
+			Method:   zip.Deflate,
+			Modified: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(opf); err != nil {
+			return err
+		}
+		replaced = true
+	}
+	if !replaced {
+		return fmt.Errorf("epub sem %s", pkg.Path)
+	}
+	return zw.Close()
+}
+
+// opfElement é um elemento filho de <metadata> com a posição no OPF
+type opfElement struct {
+	start, end int64
+	name       xml.Name
+	attrs      map[string]string // Pelo nome local (role, opf:role...)
+	text       string
+}
+
+// changedGroups indica quais grupos de metadados diferem do OPF
+type changedGroups struct {
+	title, creators, language, publisher, date, description, subjects, isbn, series bool
+}
+
+func (c changedGroups) any() bool {
+	return c.title || c.creators || c.language || c.publisher || c.date || c.description || c.subjects || c.isbn || c.series
+}
+
+// rewriteOPF remove os elementos dos grupos alterados e acrescenta os novos no
+// fim de <metadata>, preservando os bytes de todo o resto
+func rewriteOPF(data []byte, pkg *Package, metadata *Metadata, now time.Time) ([]byte, error) {
+	current := &pkg.Metadata
+	changed := changedGroups{
+		title:       current.Title != metadata.Title,
+		creators:    !slices.Equal(current.Creators, metadata.Creators),
+		language:    current.Language != metadata.Language,
+		publisher:   current.Publisher != metadata.Publisher,
+		date:        current.Date != metadata.Date,
+		description: current.Description != metadata.Description,
+		subjects:    !slices.Equal(current.Subjects, metadata.Subjects),
+		isbn:        current.ISBN() != metadata.ISBN(),
+		series:      current.Series != metadata.Series || !equalIndex(current.SeriesIndex, metadata.SeriesIndex),
+	}
+	if !changed.any() {
+		return nil, ErrNotModified
+	}
+
+	doc, err := scanOPF(data)
+	if err != nil {
+		return nil, err
+	}
+	v3 := strings.HasPrefix(pkg.Version, "3")
+
+	// Primeira passagem: elementos removidos e seus ids (os refinamentos
+	// apontando para eles também saem)
+	drop := make([]bool, len(doc.children))
+	dropped := make(map[string]bool)
+	isbnID := ""
+	for i, element := range doc.children {
+		if element.name.Space == dcNamespace {
+			switch element.name.Local {
+			case "title":
+				drop[i] = changed.title
+			case "creator":
+				drop[i] = changed.creators
+			case "language":
+				drop[i] = changed.language
+			case "publisher":
+				drop[i] = changed.publisher
+			case "description":
+				drop[i] = changed.description
+			case "subject":
+				drop[i] = changed.subjects
+			case "date":
+				event := strings.ToLower(element.attrs["event"])
+				drop[i] = changed.date && (event == "" || event == "publication")
+			case "identifier":
+				if !changed.isbn {
+					break
+				}
+				scheme := firstNonEmpty(doc.refines[element.attrs["id"]]["identifier-type"], element.attrs["scheme"])
+				if identifier, ok := parseIdentifier(scheme, clean(element.text)); ok && identifier.Scheme == "isbn" {
+					drop[i] = true
+					// Se o ISBN era o identificador único, o novo herda o id
+					if element.attrs["id"] != "" && element.attrs["id"] == doc.uniqueID {
+						isbnID = doc.uniqueID
+					}
+				}
+			}
+		} else if element.name.Local == "meta" {
+			switch {
+			case element.attrs["name"] == "calibre:series" || element.attrs["name"] == "calibre:series_index":
+				drop[i] = changed.series
+			case element.attrs["property"] == "belongs-to-collection":
+				collectionType := doc.refines[element.attrs["id"]]["collection-type"]
+				drop[i] = changed.series && (collectionType == "" || collectionType == "series")
+			case element.attrs["property"] == "dcterms:modified":
+				drop[i] = v3
+			}
+		}
+		if drop[i] && element.attrs["id"] != "" {
+			dropped[element.attrs["id"]] = true
+		}
+	}
+	for i, element := range doc.children {
+		if refines := strings.TrimPrefix(element.attrs["refines"], "#"); refines != "" && dropped[refines] {
+			drop[i] = true
+		}
+	}
+
+	// Monta o novo <metadata>: o conteúdo original sem os elementos removidos
+	var inner bytes.Buffer
+	cursor := doc.metaStart
+	for i, element := range doc.children {
+		if !drop[i] {
+			continue
+		}
+		// Remove também a indentação antes do elemento
+		start := element.start
+		for start > cursor && (data[start-1] == ' ' || data[start-1] == '\t') {
+			start--
+		}
+		if start > cursor && data[start-1] == '\n' {
+			start--
+			if start > cursor && data[start-1] == '\r' {
+				start--
+			}
+		}
+		inner.Write(data[cursor:start])
+		cursor = element.end
+	}
+	inner.Write(data[cursor:doc.metaEnd])
+
+	kept := bytes.TrimRight(inner.Bytes(), " \t\r\n")
+	trailing := inner.Bytes()[len(kept):]
+	if len(trailing) == 0 {
+		trailing = []byte("\n  ")
+	}
+
+	generator := &opfWriter{doc: doc, v3: v3}
+	generator.write(changed, metadata, isbnID, now)
+
+	var out bytes.Buffer
+	out.Write(data[:doc.metaStart])
+	out.Write(kept)
+	for _, line := range generator.lines {
+		out.WriteString("\n    ")
+		out.WriteString(line)
+	}
+	out.Write(trailing)
+	out.Write(data[doc.metaEnd:])
+	return out.Bytes(), nil
+}
+
+// opfDocument é o resultado da leitura do OPF para a regravação
+type opfDocument struct {
+	metaStart, metaEnd int64             // Conteúdo entre <metadata ...> e </metadata>
+	children           []opfElement      // Filhos diretos de <metadata>
+	prefixes           map[string]string // Namespace -> prefixo declarado
+	ids                map[string]bool   // Todos os ids do documento
+	refines            map[string]map[string]string
+	uniqueID           string
+}
+
+// scanOPF localiza <metadata> e seus filhos diretos com as posições em bytes
+func scanOPF(data []byte) (*opfDocument, error) {
+	doc := &opfDocument{
+		metaStart: -1,
+		metaEnd:   -1,
+		prefixes:  make(map[string]string),
+		ids:       make(map[string]bool),
+		refines:   make(map[string]map[string]string),
+	}
+
+	decoder := newDecoder(bytes.NewReader(data))
+	depth := 0 // Profundidade dentro de <metadata>
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler o OPF: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "id" && attr.Name.Space == "" {
+					doc.ids[attr.Value] = true
+				}
+			}
+
+			inMetadata := doc.metaStart >= 0 && doc.metaEnd < 0
+			if !inMetadata {
+				if doc.metaStart < 0 {
+					for _, attr := range t.Attr {
+						if attr.Name.Space == "xmlns" {
+							doc.prefixes[attr.Value] = attr.Name.Local
+						}
+						if t.Name.Local == "package" && attr.Name.Local == "unique-identifier" {
+							doc.uniqueID = attr.Value
+						}
+					}
+					if t.Name.Local == "metadata" {
+						doc.metaStart = decoder.InputOffset()
+					}
+				}
+				continue
+			}
+
+			depth++
+			if depth == 1 {
+				if t.Name.Local == "dc-metadata" || t.Name.Local == "x-metadata" {
+					return nil, errors.New("OPF com dc-metadata (OEB 1.x) não suportado")
+				}
+				attrs := make(map[string]string, len(t.Attr))
+				for _, attr := range t.Attr {
+					if attr.Name.Space != "xmlns" {
+						attrs[attr.Name.Local] = attr.Value
+					}
+				}
+				doc.children = append(doc.children, opfElement{start: offset, name: t.Name, attrs: attrs})
+			}
+		case xml.CharData:
+			if depth > 0 {
+				element := &doc.children[len(doc.children)-1]
+				element.text += string(t)
+			}
+		case xml.EndElement:
+			if doc.metaStart < 0 || doc.metaEnd >= 0 {
+				continue
+			}
+			if depth == 0 {
+				doc.metaEnd = offset
+				continue
+			}
+			if depth--; depth == 0 {
+				doc.children[len(doc.children)-1].end = decoder.InputOffset()
+			}
+		}
+	}
+
+	if doc.metaStart < 0 || doc.metaEnd < 0 {
+		return nil, errors.New("OPF sem metadata")
+	}
+	if bytes.HasSuffix(data[:doc.metaStart], []byte("/>")) {
+		// <metadata/> vazio: não há onde inserir os elementos
+		return nil, errors.New("OPF com metadata vazio")
+	}
+
+	for _, element := range doc.children {
+		refines := strings.TrimPrefix(element.attrs["refines"], "#")
+		if element.name.Local != "meta" || refines == "" || element.attrs["property"] == "" {
+			continue
+		}
+		if doc.refines[refines] == nil {
+			doc.refines[refines] = make(map[string]string)
+		}
+		doc.refines[refines][element.attrs["property"]] = clean(element.text)
+	}
+	return doc, nil
+}
+
+// opfWriter gera os novos elementos de metadados
+type opfWriter struct {
+	doc   *opfDocument
+	v3    bool
+	lines []string
+}
+
+func (g *opfWriter) write(changed changedGroups, metadata *Metadata, isbnID string, now time.Time) {
+	if changed.title && metadata.Title != "" {
+		g.dc("title", nil, metadata.Title)
+	}
+
+	if changed.creators {
+		for _, creator := range metadata.Creators {
+			if !g.v3 {
+				g.dc("creator", [][2]string{{g.opf("role"), creator.Role}, {g.opf("file-as"), creator.FileAs}}, creator.Name)
+				continue
+			}
+			id := g.newID("creator")
+			g.dc("creator", [][2]string{{"id", id}}, creator.Name)
+			if creator.Role != "" {
+				g.meta([][2]string{{"refines", "#" + id}, {"property", "role"}, {"scheme", "marc:relators"}}, creator.Role)
+			}
+			if creator.FileAs != "" {
+				g.meta([][2]string{{"refines", "#" + id}, {"property", "file-as"}}, creator.FileAs)
+			}
+		}
+	}
+
+	if changed.language && metadata.Language != "" {
+		g.dc("language", nil, metadata.Language)
+	}
+	if changed.publisher && metadata.Publisher != "" {
+		g.dc("publisher", nil, metadata.Publisher)
+	}
+	if changed.date && metadata.Date != "" {
+		g.dc("date", nil, metadata.Date)
+	}
+	if changed.description && metadata.Description != "" {
+		g.dc("description", nil, metadata.Description)
+	}
+	if changed.subjects {
+		for _, subject := range metadata.Subjects {
+			g.dc("subject", nil, subject)
+		}
+	}
+
+	if isbn := metadata.ISBN(); changed.isbn && isbn != "" {
+		if g.v3 {
+			g.dc("identifier", [][2]string{{"id", isbnID}}, "urn:isbn:"+isbn)
+		} else {
+			g.dc("identifier", [][2]string{{"id", isbnID}, {g.opf("scheme"), "ISBN"}}, isbn)
+		}
+	}
+
+	if changed.series && metadata.Series != "" {
+		index := ""
+		if metadata.SeriesIndex != nil {
+			index = strconv.FormatFloat(*metadata.SeriesIndex, 'f', -1, 64)
+		}
+
+		// O Calibre e a maioria dos leitores usam a meta do Calibre; o EPUB 3
+		// tem a coleção do tipo series
+		g.empty("meta", [][2]string{{"name", "calibre:series"}, {"content", metadata.Series}})
+		if index != "" {
+			g.empty("meta", [][2]string{{"name", "calibre:series_index"}, {"content", index}})
+		}
+		if g.v3 {
+			id := g.newID("series")
+			g.meta([][2]string{{"property", "belongs-to-collection"}, {"id", id}}, metadata.Series)
+			g.meta([][2]string{{"refines", "#" + id}, {"property", "collection-type"}}, "series")
+			if index != "" {
+				g.meta([][2]string{{"refines", "#" + id}, {"property", "group-position"}}, index)
+			}
+		}
+	}
+
+	// O EPUB 3 exige a data da última modificação
+	if g.v3 {
+		g.meta([][2]string{{"property", "dcterms:modified"}}, now.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+}
+
+// dc gera um elemento Dublin Core, declarando os namespaces que o OPF não declara
+func (g *opfWriter) dc(local string, attrs [][2]string, text string) {
+	if _, declared := g.doc.prefixes[opfNamespace]; !declared {
+		for _, attr := range attrs {
+			if strings.HasPrefix(attr[0], "opf:") && attr[1] != "" {
+				attrs = append([][2]string{{"xmlns:opf", opfNamespace}}, attrs...)
+				break
+			}
+		}
+	}
+	prefix, declared := g.doc.prefixes[dcNamespace]
+	if !declared {
+		prefix = "dc"
+		attrs = append([][2]string{{"xmlns:dc", dcNamespace}}, attrs...)
+	}
+	name := local
+	if prefix != "" {
+		name = prefix + ":" + local
+	}
+	g.lines = append(g.lines, "<"+name+formatAttrs(attrs)+">"+escape(text)+"</"+name+">")
+}
+
+// meta gera um <meta> com conteúdo (EPUB 3)
+func (g *opfWriter) meta(attrs [][2]string, text string) {
+	g.lines = append(g.lines, "<meta"+formatAttrs(attrs)+">"+escape(text)+"</meta>")
+}
+
+// empty gera um elemento vazio (<meta name="..." content="..."/>)
+func (g *opfWriter) empty(name string, attrs [][2]string) {
+	g.lines = append(g.lines, "<"+name+formatAttrs(attrs)+"/>")
+}
+
+// opf retorna o nome de um atributo do namespace OPF (opf:role no EPUB 2)
+func (g *opfWriter) opf(local string) string {
+	if prefix := g.doc.prefixes[opfNamespace]; prefix != "" {
+		return prefix + ":" + local
+	}
+	return "opf:" + local
+}
+
+// newID gera um id ainda não usado no OPF
+func (g *opfWriter) newID(base string) string {
+	for i := 1; ; i++ {
+		id := base + "-" + strconv.Itoa(i)
+		if !g.doc.ids[id] {
+			g.doc.ids[id] = true
+			return id
+		}
+	}
+}
+
+// formatAttrs monta os atributos, ignorando os vazios
+func formatAttrs(attrs [][2]string) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		if attr[1] != "" {
+			b.WriteString(" " + attr[0] + `="` + escape(attr[1]) + `"`)
+		}
+	}
+	return b.String()
+}
+
+func escape(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+func equalIndex(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,576 @@
+package org
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Elementos de bloco do documento
+type node interface{}
+
+type headingNode struct {
+	level    int
+	todo     string
+	done     bool
+	priority string
+	title    []inline
+	plain    string // Título sem marcação (árvore e links internos)
+	tags     []string
+	customID string
+	id       string
+}
+
+type paragraphNode struct {
+	content []inline
+}
+
+type listKind int
+
+const (
+	unorderedList listKind = iota
+	orderedList
+	descriptionList
+)
+
+type listNode struct {
+	kind  listKind
+	start int // Primeiro número da lista ordenada ([@N]); 0 = padrão
+	items []*listItem
+}
+
+type listItem struct {
+	checkbox string // "", " ", "X" ou "-"
+	term     []inline
+	blocks   []node
+}
+
+type tableRow [][]inline
+
+type tableNode struct {
+	header []tableRow
+	body   []tableRow
+}
+
+// preNode é um bloco de texto pré-formatado (#+BEGIN_SRC, #+BEGIN_EXAMPLE, linhas ": ")
+type preNode struct {
+	language string // Apenas em blocos de código
+	text     string
+}
+
+type quoteNode struct {
+	blocks []node
+}
+
+// specialNode é um bloco #+BEGIN_NOME sem tratamento próprio (ex.: CENTER, NOTE)
+type specialNode struct {
+	class  string
+	blocks []node
+}
+
+type verseNode struct {
+	lines [][]inline
+}
+
+type ruleNode struct{}
+
+type lineKind int
+
+const (
+	paragraphLine lineKind = iota
+	blankLine
+	headingLine
+	keywordLine
+	blockLine
+	drawerLine
+	commentLine
+	fixedLine
+	ruleLine
+	tableLine
+	itemLine
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(\*+)[ \t]+(.*)$`)
+	keywordPattern  = regexp.MustCompile(`^[ \t]*#\+([^ \t:]+):[ \t]*(.*)$`)
+	beginPattern    = regexp.MustCompile(`^[ \t]*#\+(?i:begin)_([^ \t]+)(?:[ \t]+(.*))?$`)
+	endPattern      = regexp.MustCompile(`^[ \t]*#\+(?i:end)_([^ \t]+)[ \t]*$`)
+	drawerPattern   = regexp.MustCompile(`^[ \t]*:([\w-]+):[ \t]*$`)
+	drawerEnd       = regexp.MustCompile(`^[ \t]*(?i::end:)[ \t]*$`)
+	commentPattern  = regexp.MustCompile(`^[ \t]*#([ \t].*)?$`)
+	fixedPattern    = regexp.MustCompile(`^[ \t]*:([ \t].*)?$`)
+	rulePattern     = regexp.MustCompile(`^[ \t]*-{5,}[ \t]*$`)
+	itemPattern     = regexp.MustCompile(`^([ \t]*)([-+*]|\d+[.)])(?:[ \t]+(.*))?$`)
+	planningPattern = regexp.MustCompile(`^[ \t]*(SCHEDULED|DEADLINE|CLOSED):`)
+	propertyPattern = regexp.MustCompile(`^[ \t]*:([^ \t:]+):[ \t]*(.*)$`)
+	priorityCookie  = regexp.MustCompile(`^\[#([A-Za-z0-9])\][ \t]*`)
+	headingTags     = regexp.MustCompile(`[ \t]+(:[\p{L}\p{N}_@#%:]+:)[ \t]*$`)
+	counterCookie   = regexp.MustCompile(`^\[@(\d+)\][ \t]*`)
+	checkboxCookie  = regexp.MustCompile(`^\[([ X-])\](?:[ \t]+|$)`)
+	alignmentCell   = regexp.MustCompile(`^<[lrc]?\d*>$`)
+)
+
+// affiliatedKeywords se referem ao elemento seguinte (legenda, nome, atributos
+// de exportação) e não são metadados do documento
+var affiliatedKeywords = map[string]bool{
+	"CAPTION": true, "NAME": true, "RESULTS": true, "HEADER": true, "PLOT": true,
+	"TODO": true, "SEQ_TODO": true, "TYP_TODO": true,
+}
+
+type parser struct {
+	doc *Document
+}
+
+// blockParser lê os elementos de um trecho do documento (o documento inteiro,
+// um item de lista ou o conteúdo de um bloco)
+type blockParser struct {
+	*parser
+	lines []string
+	depth int
+	ends  map[string]int // Última linha de cada #+END_NOME; :END: de gavetas em ""
+}
+
+// parseBlocks lê os elementos de um trecho. Títulos só são reconhecidos no
+// nível do documento (depth 0).
+func (p *parser) parseBlocks(lines []string, depth int) []node {
+	if depth > maxDepth {
+		return []node{&paragraphNode{content: parseInline(strings.Join(trimLines(lines), "\n"))}}
+	}
+
+	b := &blockParser{parser: p, lines: lines, depth: depth, ends: make(map[string]int)}
+	for i, line := range lines {
+		if m := endPattern.FindStringSubmatch(line); m != nil {
+			b.ends[strings.ToLower(m[1])] = i
+		} else if drawerEnd.MatchString(line) {
+			b.ends[""] = i
+		}
+	}
+
+	var nodes []node
+	for i := 0; i < len(lines); {
+		var n node
+		n, i = b.parse(i)
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// classify identifica o elemento que começa na linha i
+func (b *blockParser) classify(i int) lineKind {
+	line := b.lines[i]
+	switch {
+	case strings.TrimSpace(line) == "":
+		return blankLine
+	case b.depth == 0 && headingPattern.MatchString(line):
+		return headingLine
+	}
+
+	if m := beginPattern.FindStringSubmatch(line); m != nil {
+		// Sem #+END_ correspondente, a linha é texto comum
+		if end, ok := b.ends[strings.ToLower(m[1])]; ok && end > i {
+			return blockLine
+		}
+		return paragraphLine
+	}
+	if keywordPattern.MatchString(line) {
+		return keywordLine
+	}
+	if drawerPattern.MatchString(line) && !drawerEnd.MatchString(line) {
+		if end, ok := b.ends[""]; ok && end > i {
+			return drawerLine
+		}
+	}
+
+	switch {
+	case commentPattern.MatchString(line):
+		return commentLine
+	case fixedPattern.MatchString(line):
+		return fixedLine
+	case rulePattern.MatchString(line):
+		return ruleLine
+	case strings.HasPrefix(strings.TrimLeft(line, " \t"), "|"):
+		return tableLine
+	case b.item(i) != nil:
+		return itemLine
+	}
+	return paragraphLine
+}
+
+// parse lê o elemento que começa na linha i e retorna a próxima linha.
+// Elementos que não aparecem no HTML retornam nil.
+func (b *blockParser) parse(i int) (node, int) {
+	switch b.classify(i) {
+	case blankLine, commentLine:
+		return nil, i + 1
+	case headingLine:
+		return b.parseHeading(i)
+	case keywordLine:
+		m := keywordPattern.FindStringSubmatch(b.lines[i])
+		key := strings.ToUpper(m[1])
+		if !affiliatedKeywords[key] && !strings.HasPrefix(key, "ATTR_") {
+			b.doc.addKeyword(key, m[2])
+		}
+		return nil, i + 1
+	case blockLine:
+		return b.parseBlock(i)
+	case drawerLine:
+		// Gavetas (:PROPERTIES:, :LOGBOOK:...) não são exportadas
+		for j := i + 1; j < len(b.lines); j++ {
+			if drawerEnd.MatchString(b.lines[j]) {
+				return nil, j + 1
+			}
+		}
+		return nil, len(b.lines)
+	case fixedLine:
+		var lines []string
+		j := i
+		for ; j < len(b.lines) && b.classify(j) == fixedLine; j++ {
+			line := strings.TrimLeft(b.lines[j], " \t")[1:]
+			lines = append(lines, strings.TrimPrefix(line, " "))
+		}
+		return &preNode{text: strings.Join(lines, "\n")}, j
+	case ruleLine:
+		return &ruleNode{}, i + 1
+	case tableLine:
+		return b.parseTable(i)
+	case itemLine:
+		return b.parseList(i)
+	}
+
+	j := i + 1
+	for j < len(b.lines) && b.classify(j) == paragraphLine {
+		j++
+	}
+	return &paragraphNode{content: parseInline(strings.Join(trimLines(b.lines[i:j]), "\n"))}, j
+}
+
+// parseHeading lê um título, a linha de agendamento e a gaveta de
+// propriedades. Títulos COMMENT ou com a tag :noexport: são omitidos com todo o
+// conteúdo da seção.
+func (b *blockParser) parseHeading(i int) (node, int) {
+	m := headingPattern.FindStringSubmatch(b.lines[i])
+	heading := &headingNode{level: len(m[1])}
+	rest := strings.TrimSpace(m[2])
+
+	if word, after, _ := strings.Cut(rest, " "); word != "" {
+		if done, ok := b.doc.todo[word]; ok {
+			heading.todo, heading.done = word, done
+			rest = strings.TrimSpace(after)
+		}
+	}
+	if cookie := priorityCookie.FindStringSubmatch(rest); cookie != nil {
+		heading.priority = strings.ToUpper(cookie[1])
+		rest = rest[len(cookie[0]):]
+	}
+	if tags := headingTags.FindStringSubmatchIndex(rest); tags != nil {
+		for _, tag := range strings.Split(rest[tags[2]:tags[3]], ":") {
+			if tag != "" {
+				heading.tags = append(heading.tags, tag)
+			}
+		}
+		rest = rest[:tags[0]]
+	} else if headingTags.MatchString(" " + rest) {
+		// O título é só a lista de tags
+		for _, tag := range strings.Split(strings.TrimSpace(rest), ":") {
+			if tag != "" {
+				heading.tags = append(heading.tags, tag)
+			}
+		}
+		rest = ""
+	}
+
+	skip := rest == "COMMENT" || strings.HasPrefix(rest, "COMMENT ")
+	for _, tag := range heading.tags {
+		skip = skip || tag == "noexport"
+	}
+	if skip {
+		j := i + 1
+		for j < len(b.lines) {
+			if next := headingPattern.FindStringSubmatch(b.lines[j]); next != nil && len(next[1]) <= heading.level {
+				break
+			}
+			j++
+		}
+		return nil, j
+	}
+
+	heading.title = parseInline(strings.TrimSpace(rest))
+	heading.plain = strings.TrimSpace(plainText(heading.title))
+
+	j := i + 1
+	if j < len(b.lines) && planningPattern.MatchString(b.lines[j]) {
+		j++
+	}
+	if j < len(b.lines) && strings.EqualFold(strings.TrimSpace(b.lines[j]), ":PROPERTIES:") {
+		for k := j + 1; k < len(b.lines); k++ {
+			if drawerEnd.MatchString(b.lines[k]) {
+				j = k + 1
+				break
+			}
+			if property := propertyPattern.FindStringSubmatch(b.lines[k]); property != nil && strings.EqualFold(property[1], "CUSTOM_ID") {
+				heading.customID = strings.TrimSpace(property[2])
+			}
+		}
+	}
+
+	b.doc.headings = append(b.doc.headings, heading)
+	return heading, j
+}
+
+// parseBlock lê um bloco #+BEGIN_NOME ... #+END_NOME
+func (b *blockParser) parseBlock(i int) (node, int) {
+	m := beginPattern.FindStringSubmatch(b.lines[i])
+	name := strings.ToLower(m[1])
+	end := i + 1
+	for end < len(b.lines) {
+		if e := endPattern.FindStringSubmatch(b.lines[end]); e != nil && strings.ToLower(e[1]) == name {
+			break
+		}
+		end++
+	}
+	content := dedent(b.lines[i+1 : end])
+	next := end + 1
+
+	switch name {
+	case "src", "example":
+		// Linhas que começam com * ou #+ são escapadas com vírgula no Org
+		for k, line := range content {
+			if strings.HasPrefix(line, ",*") || strings.HasPrefix(line, ",#+") {
+				content[k] = line[1:]
+			}
+		}
+		pre := &preNode{text: strings.Join(content, "\n")}
+		if name == "src" {
+			if fields := strings.Fields(m[2]); len(fields) > 0 && !strings.HasPrefix(fields[0], "-") && !strings.HasPrefix(fields[0], ":") {
+				pre.language = fields[0]
+			}
+		}
+		return pre, next
+	case "comment", "export":
+		// HTML embutido (#+BEGIN_EXPORT html) é descartado
+		return nil, next
+	case "quote":
+		return &quoteNode{blocks: b.parseBlocks(content, b.depth+1)}, next
+	case "verse":
+		verse := &verseNode{}
+		for _, line := range trimTrailingBlank(content) {
+			verse.lines = append(verse.lines, parseInline(line))
+		}
+		return verse, next
+	default:
+		return &specialNode{class: className(name), blocks: b.parseBlocks(content, b.depth+1)}, next
+	}
+}
+
+// item retorna as partes de um item de lista na linha i (indentação, marcador,
+// texto), ou nil. O marcador * só vale indentado, para não ser confundido com título.
+func (b *blockParser) item(i int) []string {
+	m := itemPattern.FindStringSubmatch(b.lines[i])
+	if m == nil || (m[2] == "*" && m[1] == "" && b.depth == 0) {
+		return nil
+	}
+	return m
+}
+
+// parseList lê uma lista. Cada item vai até a próxima linha com indentação
+// menor ou igual à do marcador; duas linhas em branco encerram a lista.
+func (b *blockParser) parseList(i int) (node, int) {
+	first := b.item(i)
+	indent := indentation(first[1])
+	list := &listNode{kind: unorderedList}
+	if isOrdered(first[2]) {
+		list.kind = orderedList
+	} else if _, _, ok := cutDescription(first[3]); ok {
+		list.kind = descriptionList
+	}
+
+	for i < len(b.lines) {
+		m := b.item(i)
+		if m == nil || indentation(m[1]) != indent {
+			break
+		}
+		// Um item de outro tipo começa outra lista
+		text := m[3]
+		if _, _, description := cutDescription(text); isOrdered(m[2]) != (list.kind == orderedList) ||
+			!isOrdered(m[2]) && description != (list.kind == descriptionList) {
+			break
+		}
+		var continuation []string
+		j, blanks := i+1, 0
+		for ; j < len(b.lines); j++ {
+			line := b.lines[j]
+			if strings.TrimSpace(line) == "" {
+				if blanks++; blanks == 2 {
+					break
+				}
+				continuation = append(continuation, "")
+				continue
+			}
+			if indentation(leadingSpace(line)) <= indent {
+				break
+			}
+			blanks = 0
+			continuation = append(continuation, line)
+		}
+
+		item := &listItem{}
+		if cookie := counterCookie.FindStringSubmatch(text); cookie != nil {
+			if len(list.items) == 0 && list.kind == orderedList {
+				list.start, _ = strconv.Atoi(cookie[1])
+			}
+			text = text[len(cookie[0]):]
+		}
+		if cookie := checkboxCookie.FindStringSubmatch(text); cookie != nil {
+			item.checkbox = cookie[1]
+			text = text[len(cookie[0]):]
+		}
+		if list.kind == descriptionList {
+			term, description, _ := cutDescription(text)
+			item.term = parseInline(term)
+			text = description
+		}
+
+		lines := append([]string{text}, dedent(continuation)...)
+		item.blocks = b.parseBlocks(trimTrailingBlank(lines), b.depth+1)
+		list.items = append(list.items, item)
+
+		i = j
+		if blanks == 2 {
+			break
+		}
+		// Uma linha em branco entre itens não encerra a lista
+		for i < len(b.lines) && strings.TrimSpace(b.lines[i]) == "" {
+			i++
+		}
+		if i < len(b.lines) && b.item(i) == nil {
+			break
+		}
+	}
+	return list, i
+}
+
+func isOrdered(bullet string) bool {
+	return bullet[0] >= '0' && bullet[0] <= '9'
+}
+
+// cutDescription separa "termo :: descrição" de um item de lista de descrição
+func cutDescription(text string) (string, string, bool) {
+	if term, description, ok := strings.Cut(text, " :: "); ok {
+		return strings.TrimSpace(term), description, true
+	}
+	if strings.HasSuffix(text, " ::") {
+		return strings.TrimSpace(strings.TrimSuffix(text, " ::")), "", true
+	}
+	return "", "", false
+}
+
+// parseTable lê uma tabela. As linhas antes do primeiro separador |---| formam
+// o cabeçalho; linhas de alinhamento (<l>, <r10>) são ignoradas.
+func (b *blockParser) parseTable(i int) (node, int) {
+	var rows []tableRow
+	headerRows := -1
+	j := i
+	for ; j < len(b.lines) && b.classify(j) == tableLine; j++ {
+		line := strings.TrimSpace(b.lines[j])
+		if strings.HasPrefix(line, "|-") {
+			if headerRows < 0 && len(rows) > 0 {
+				headerRows = len(rows)
+			}
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "|")
+		line = strings.TrimSuffix(line, "|")
+		cells := strings.Split(line, "|")
+		alignment := true
+		row := make(tableRow, len(cells))
+		for k, cell := range cells {
+			cell = strings.TrimSpace(cell)
+			alignment = alignment && (cell == "" || alignmentCell.MatchString(cell))
+			row[k] = parseInline(cell)
+		}
+		if alignment && strings.TrimSpace(strings.ReplaceAll(line, "|", "")) != "" {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	table := &tableNode{body: rows}
+	if headerRows > 0 && headerRows < len(rows) {
+		table.header, table.body = rows[:headerRows], rows[headerRows:]
+	}
+	return table, j
+}
+
+// indentation conta as colunas do espaço inicial (tab = 8 colunas, como no Org)
+func indentation(space string) int {
+	columns := 0
+	for _, c := range space {
+		if c == '\t' {
+			columns += 8 - columns%8
+		} else {
+			columns++
+		}
+	}
+	return columns
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// dedent remove a indentação comum às linhas não vazias
+func dedent(lines []string) []string {
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if indent := indentation(leadingSpace(line)); common < 0 || indent < common {
+			common = indent
+		}
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		columns, k := 0, 0
+		for k < len(line) && columns < common && (line[k] == ' ' || line[k] == '\t') {
+			if line[k] == '\t' {
+				columns += 8 - columns%8
+			} else {
+				columns++
+			}
+			k++
+		}
+		out[i] = line[k:]
+	}
+	return out
+}
+
+func trimLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimSpace(line)
+	}
+	return out
+}
+
+func trimTrailingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// className restringe o nome de um bloco a letras, dígitos e hífens
+func className(name string) string {
+	var b strings.Builder
+	for _, c := range name {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
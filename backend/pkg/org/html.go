@@ -0,0 +1,297 @@
+package org
+
+import (
+	"html"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// imageExtensions são as extensões exibidas com <img> em links sem descrição
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true,
+}
+
+// HTML gera o HTML do corpo do documento (o título fica em Title). Os títulos
+// viram <h2> a <h6> com os ids da árvore de Headings; só são gerados elementos
+// e atributos fixos, sem estilos nem scripts.
+func (d *Document) HTML() string {
+	r := &renderer{doc: d}
+	r.blocks(d.blocks)
+	return r.buf.String()
+}
+
+type renderer struct {
+	doc    *Document
+	buf    strings.Builder
+	inLink bool // Dentro da descrição de um link: links internos viram texto
+}
+
+func (r *renderer) blocks(nodes []node) {
+	for _, n := range nodes {
+		r.block(n)
+	}
+}
+
+func (r *renderer) block(n node) {
+	switch n := n.(type) {
+	case *headingNode:
+		tag := "h" + strconv.Itoa(min(n.level+1, 6))
+		r.buf.WriteString("<" + tag + ` id="` + escape(n.id) + `">`)
+		if n.todo != "" {
+			class := "todo"
+			if n.done {
+				class = "done"
+			}
+			r.buf.WriteString(`<span class="` + class + `">` + escape(n.todo) + "</span> ")
+		}
+		if n.priority != "" {
+			r.buf.WriteString(`<span class="priority">[` + escape(n.priority) + "]</span> ")
+		}
+		r.inlines(n.title)
+		if len(n.tags) > 0 {
+			r.buf.WriteString(` <span class="tags">`)
+			for _, tag := range n.tags {
+				r.buf.WriteString(`<span class="tag">` + escape(tag) + "</span>")
+			}
+			r.buf.WriteString("</span>")
+		}
+		r.buf.WriteString("</" + tag + ">\n")
+	case *paragraphNode:
+		r.buf.WriteString("<p>")
+		r.inlines(n.content)
+		r.buf.WriteString("</p>\n")
+	case *listNode:
+		r.list(n)
+	case *tableNode:
+		r.buf.WriteString("<table>\n")
+		if len(n.header) > 0 {
+			r.buf.WriteString("<thead>\n")
+			r.rows(n.header, "th")
+			r.buf.WriteString("</thead>\n")
+		}
+		r.buf.WriteString("<tbody>\n")
+		r.rows(n.body, "td")
+		r.buf.WriteString("</tbody>\n</table>\n")
+	case *preNode:
+		if language := languageClass(n.language); language != "" {
+			r.buf.WriteString(`<pre class="src src-` + language + `"><code class="language-` + language + `">`)
+			r.buf.WriteString(escape(n.text))
+			r.buf.WriteString("</code></pre>\n")
+		} else {
+			r.buf.WriteString(`<pre class="example">` + escape(n.text) + "</pre>\n")
+		}
+	case *quoteNode:
+		r.buf.WriteString("<blockquote>\n")
+		r.blocks(n.blocks)
+		r.buf.WriteString("</blockquote>\n")
+	case *specialNode:
+		if n.class != "" {
+			r.buf.WriteString(`<div class="` + n.class + `">` + "\n")
+		} else {
+			r.buf.WriteString("<div>\n")
+		}
+		r.blocks(n.blocks)
+		r.buf.WriteString("</div>\n")
+	case *verseNode:
+		r.buf.WriteString(`<p class="verse">`)
+		for i, line := range n.lines {
+			if i > 0 {
+				r.buf.WriteString("<br>\n")
+			}
+			r.inlines(line)
+		}
+		r.buf.WriteString("</p>\n")
+	case *ruleNode:
+		r.buf.WriteString("<hr>\n")
+	}
+}
+
+func (r *renderer) list(n *listNode) {
+	switch n.kind {
+	case orderedList:
+		if n.start > 0 {
+			r.buf.WriteString(`<ol start="` + strconv.Itoa(n.start) + `">` + "\n")
+		} else {
+			r.buf.WriteString("<ol>\n")
+		}
+	case descriptionList:
+		r.buf.WriteString("<dl>\n")
+	default:
+		r.buf.WriteString("<ul>\n")
+	}
+
+	for _, item := range n.items {
+		if n.kind == descriptionList {
+			r.buf.WriteString("<dt>")
+			r.inlines(item.term)
+			r.buf.WriteString("</dt><dd>")
+			r.itemContent(item)
+			r.buf.WriteString("</dd>\n")
+			continue
+		}
+
+		switch item.checkbox {
+		case " ":
+			r.buf.WriteString(`<li class="off">`)
+		case "X":
+			r.buf.WriteString(`<li class="on">`)
+		case "-":
+			r.buf.WriteString(`<li class="trans">`)
+		default:
+			r.buf.WriteString("<li>")
+		}
+		r.itemContent(item)
+		r.buf.WriteString("</li>\n")
+	}
+
+	switch n.kind {
+	case orderedList:
+		r.buf.WriteString("</ol>\n")
+	case descriptionList:
+		r.buf.WriteString("</dl>\n")
+	default:
+		r.buf.WriteString("</ul>\n")
+	}
+}
+
+// itemContent escreve o conteúdo de um item; o primeiro parágrafo fica sem <p>
+func (r *renderer) itemContent(item *listItem) {
+	if item.checkbox != "" {
+		r.buf.WriteString("<code>[" + strings.ReplaceAll(item.checkbox, " ", "&#xa0;") + "]</code> ")
+	}
+	blocks := item.blocks
+	if len(blocks) > 0 {
+		if paragraph, ok := blocks[0].(*paragraphNode); ok {
+			r.inlines(paragraph.content)
+			blocks = blocks[1:]
+		}
+	}
+	if len(blocks) > 0 {
+		r.buf.WriteString("\n")
+		r.blocks(blocks)
+	}
+}
+
+func (r *renderer) rows(rows []tableRow, cell string) {
+	for _, row := range rows {
+		r.buf.WriteString("<tr>")
+		for _, content := range row {
+			r.buf.WriteString("<" + cell + ">")
+			r.inlines(content)
+			r.buf.WriteString("</" + cell + ">")
+		}
+		r.buf.WriteString("</tr>\n")
+	}
+}
+
+func (r *renderer) inlines(elements []inline) {
+	for _, element := range elements {
+		switch element.kind {
+		case textInline:
+			r.buf.WriteString(escape(element.text))
+		case boldInline:
+			r.wrap("<b>", element.children, "</b>")
+		case italicInline:
+			r.wrap("<i>", element.children, "</i>")
+		case underlineInline:
+			r.wrap(`<span class="underline">`, element.children, "</span>")
+		case strikeInline:
+			r.wrap("<del>", element.children, "</del>")
+		case codeInline:
+			r.buf.WriteString("<code>" + escape(element.text) + "</code>")
+		case breakInline:
+			r.buf.WriteString("<br>\n")
+		case linkInline:
+			r.link(element)
+		}
+	}
+}
+
+func (r *renderer) wrap(open string, children []inline, close string) {
+	r.buf.WriteString(open)
+	r.inlines(children)
+	r.buf.WriteString(close)
+}
+
+// link escreve um link. Destinos sem equivalente no navegador (arquivos
+// locais, id:, esquemas desconhecidos) viram texto.
+func (r *renderer) link(element inline) {
+	href, label, image := r.resolve(element.text)
+	if href == "" || r.inLink {
+		if len(element.children) > 0 {
+			r.inlines(element.children)
+		} else {
+			r.buf.WriteString(escape(label))
+		}
+		return
+	}
+
+	if image && len(element.children) == 0 {
+		r.buf.WriteString(`<img src="` + escape(href) + `" alt="` + escape(path.Base(href)) + `">`)
+		return
+	}
+
+	if strings.HasPrefix(href, "#") {
+		r.buf.WriteString(`<a href="` + escape(href) + `">`)
+	} else {
+		r.buf.WriteString(`<a href="` + escape(href) + `" rel="noopener noreferrer">`)
+	}
+	if len(element.children) > 0 {
+		r.inLink = true
+		r.inlines(element.children)
+		r.inLink = false
+	} else {
+		r.buf.WriteString(escape(label))
+	}
+	r.buf.WriteString("</a>")
+}
+
+// resolve converte o destino de um link no href (vazio se não houver), no
+// texto exibido sem descrição e se o destino é uma imagem
+func (r *renderer) resolve(target string) (string, string, bool) {
+	lower := strings.ToLower(target)
+	for _, scheme := range []string{"https://", "http://", "ftp://", "mailto:"} {
+		if strings.HasPrefix(lower, scheme) && len(target) > len(scheme) {
+			href := strings.ReplaceAll(target, " ", "%20")
+			extension := strings.ToLower(path.Ext(strings.SplitN(href, "?", 2)[0]))
+			return href, target, scheme != "mailto:" && imageExtensions[extension]
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(target, "#"):
+		if id, ok := r.doc.customIDs[target[1:]]; ok {
+			return "#" + id, target[1:], false
+		}
+		return "", target[1:], false
+	case strings.HasPrefix(target, "*"):
+		title := strings.TrimSpace(target[1:])
+		if id, ok := r.doc.titleIDs[title]; ok {
+			return "#" + id, title, false
+		}
+		return "", title, false
+	}
+
+	// Link "difuso": o texto de um título do documento
+	if id, ok := r.doc.titleIDs[target]; ok {
+		return "#" + id, target, false
+	}
+	return "", target, false
+}
+
+// languageClass restringe a linguagem de um bloco de código a caracteres
+// usados em nomes de linguagens (c++, emacs-lisp, f#)
+func languageClass(language string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(language) {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.ContainsRune("+-_#.", c) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,280 @@
+package org
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// maxLinkLength limita o tamanho de um link entre colchetes
+	maxLinkLength = 4096
+
+	// maxInlineDepth limita o aninhamento de ênfases
+	maxInlineDepth = 8
+)
+
+// Elementos inline
+type inlineKind int
+
+const (
+	textInline inlineKind = iota
+	boldInline
+	italicInline
+	underlineInline
+	strikeInline
+	codeInline // ~código~ e =literal=
+	linkInline
+	breakInline
+)
+
+type inline struct {
+	kind     inlineKind
+	text     string   // Texto, código ou destino do link
+	children []inline // Conteúdo da ênfase ou descrição do link
+}
+
+var emphasisKinds = map[byte]inlineKind{
+	'*': boldInline,
+	'/': italicInline,
+	'_': underlineInline,
+	'+': strikeInline,
+	'=': codeInline,
+	'~': codeInline,
+}
+
+// parseInline lê a marcação inline de um trecho de texto
+func parseInline(text string) []inline {
+	return parseInlineDepth(text, 0)
+}
+
+func parseInlineDepth(text string, depth int) []inline {
+	var out []inline
+	var plain strings.Builder
+	var closers *emphasisClosers
+	if depth < maxInlineDepth {
+		closers = findClosers(text)
+	}
+	flush := func() {
+		if plain.Len() > 0 {
+			out = append(out, inline{kind: textInline, text: plain.String()})
+			plain.Reset()
+		}
+	}
+	emit := func(element inline) {
+		flush()
+		out = append(out, element)
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '[' && strings.HasPrefix(text[i:], "[["):
+			if link, n := parseLink(text[i:], depth); n > 0 {
+				emit(link)
+				i += n
+				continue
+			}
+		case c == '\\' && strings.HasPrefix(text[i:], `\\`) && (i+2 == len(text) || text[i+2] == '\n'):
+			emit(inline{kind: breakInline})
+			i += 3 // A quebra de linha do texto vai junto
+			continue
+		case c == '@' && strings.HasPrefix(text[i:], "@@"):
+			// Trechos de exportação (@@html:...@@) não são exibidos
+			if n := exportSnippet(text[i:]); n > 0 {
+				i += n
+				continue
+			}
+		case c == 'h' || c == 'f':
+			if i == 0 || !isWordByte(text[i-1]) {
+				if n := plainURL(text[i:]); n > 0 {
+					emit(inline{kind: linkInline, text: text[i : i+n]})
+					i += n
+					continue
+				}
+			}
+		default:
+			if kind, ok := emphasisKinds[c]; ok && closers != nil {
+				if end := closers.end(text, i); end > 0 {
+					content := text[i+1 : end]
+					if kind == codeInline {
+						emit(inline{kind: kind, text: content})
+					} else {
+						emit(inline{kind: kind, children: parseInlineDepth(content, depth+1)})
+					}
+					i = end + 1
+					continue
+				}
+			}
+		}
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+	return out
+}
+
+// emphasisClosers guarda as posições que podem fechar cada marcador de ênfase
+// e as quebras de linha, para que cada abertura seja resolvida sem percorrer o
+// texto de novo
+type emphasisClosers struct {
+	positions map[byte][]int
+	newlines  []int
+}
+
+// findClosers localiza os marcadores de fechamento: sem espaço antes e seguidos
+// de espaço, pontuação ou fim do texto
+func findClosers(text string) *emphasisClosers {
+	closers := &emphasisClosers{positions: make(map[byte][]int)}
+	for j := 0; j < len(text); j++ {
+		c := text[j]
+		if c == '\n' {
+			closers.newlines = append(closers.newlines, j)
+			continue
+		}
+		if _, ok := emphasisKinds[c]; !ok || j == 0 || isSpace(text[j-1]) {
+			continue
+		}
+		if j+1 == len(text) || strings.ContainsRune(" \t\n-.,;:!?')}[\"\\", rune(text[j+1])) {
+			closers.positions[c] = append(closers.positions[c], j)
+		}
+	}
+	return closers
+}
+
+// end retorna a posição do marcador que fecha a ênfase aberta em i, ou -1.
+// Como no Org, o marcador de abertura vem depois de espaço ou pontuação, o
+// conteúdo não começa nem termina com espaço e ocupa no máximo duas linhas.
+func (e *emphasisClosers) end(text string, i int) int {
+	if i > 0 && !strings.ContainsRune(" \t\n-({'\"", rune(text[i-1])) {
+		return -1
+	}
+	if i+1 >= len(text) || isSpace(text[i+1]) {
+		return -1
+	}
+
+	positions := e.positions[text[i]]
+	k := sort.SearchInts(positions, i+2)
+	if k == len(positions) {
+		return -1
+	}
+	j := positions[k]
+	if sort.SearchInts(e.newlines, j)-sort.SearchInts(e.newlines, i) > 1 {
+		return -1
+	}
+	return j
+}
+
+// parseLink lê [[destino]] ou [[destino][descrição]] e retorna o tamanho lido
+func parseLink(s string, depth int) (inline, int) {
+	limit := min(len(s), maxLinkLength)
+	end := strings.IndexAny(s[2:limit], "[]")
+	if end < 0 || s[2+end] != ']' || end == 0 {
+		return inline{}, 0
+	}
+	target := strings.Join(strings.Fields(s[2:2+end]), " ")
+	rest := s[2+end+1 : limit]
+
+	switch {
+	case strings.HasPrefix(rest, "]"):
+		return inline{kind: linkInline, text: target}, 2 + end + 2
+	case strings.HasPrefix(rest, "["):
+		// A descrição não pode conter outro link
+		close := -1
+		for k := 1; k+1 < len(rest); k++ {
+			if rest[k] == '[' && rest[k+1] == '[' {
+				return inline{}, 0
+			}
+			if rest[k] == ']' && rest[k+1] == ']' {
+				close = k
+				break
+			}
+		}
+		if close < 0 {
+			return inline{}, 0
+		}
+		description := rest[1:close]
+		return inline{
+			kind:     linkInline,
+			text:     target,
+			children: parseInlineDepth(description, depth+1),
+		}, 2 + end + 1 + close + 2
+	}
+	return inline{}, 0
+}
+
+// plainURL retorna o tamanho de um endereço http, https ou ftp no início de s
+func plainURL(s string) int {
+	scheme := ""
+	for _, prefix := range []string{"https://", "http://", "ftp://"} {
+		if strings.HasPrefix(s, prefix) {
+			scheme = prefix
+			break
+		}
+	}
+	if scheme == "" {
+		return 0
+	}
+
+	n := len(scheme)
+	for n < len(s) && n < maxLinkLength && !isSpace(s[n]) && !strings.ContainsRune("<>[]\"'", rune(s[n])) {
+		n++
+	}
+	// Pontuação no fim pertence à frase
+	for n > len(scheme) && strings.ContainsRune(".,;:!?)", rune(s[n-1])) {
+		n--
+	}
+	if n == len(scheme) {
+		return 0
+	}
+	return n
+}
+
+// exportSnippet retorna o tamanho de um trecho @@backend:valor@@
+func exportSnippet(s string) int {
+	n := 2
+	for n < len(s) && (s[n] >= 'a' && s[n] <= 'z' || s[n] >= 'A' && s[n] <= 'Z' || s[n] >= '0' && s[n] <= '9' || s[n] == '-') {
+		n++
+	}
+	if n == 2 || n == len(s) || s[n] != ':' {
+		return 0
+	}
+	limit := min(len(s), maxLinkLength)
+	if n+1 > limit {
+		return 0
+	}
+	end := strings.Index(s[n+1:limit], "@@")
+	if end < 0 {
+		return 0
+	}
+	return n + 1 + end + 2
+}
+
+// plainText retorna o texto sem marcação
+func plainText(elements []inline) string {
+	var b strings.Builder
+	for _, element := range elements {
+		switch element.kind {
+		case textInline, codeInline:
+			b.WriteString(element.text)
+		case breakInline:
+			b.WriteByte(' ')
+		case linkInline:
+			if len(element.children) > 0 {
+				b.WriteString(plainText(element.children))
+			} else {
+				b.WriteString(element.text)
+			}
+		default:
+			b.WriteString(plainText(element.children))
+		}
+	}
+	return b.String()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,185 @@
+// Package org lê documentos Org-mode (títulos, listas, tabelas, blocos, links e
+// marcação inline) e gera HTML seguro: todo o texto é escapado, os links só
+// aceitam http, https, mailto e ftp, e HTML embutido no documento é descartado.
+package org
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxDepth limita o aninhamento de listas e blocos
+	maxDepth = 32
+
+	// idPrefix evita que os ids gerados colidam com os da página que exibe o HTML
+	idPrefix = "org-"
+)
+
+// Heading é uma entrada da árvore de títulos, para navegação
+type Heading struct {
+	ID       string    `json:"id"` // id do elemento no HTML (sem #)
+	Level    int       `json:"level"`
+	Title    string    `json:"title"` // Texto sem marcação
+	Children []Heading `json:"children,omitempty"`
+}
+
+// Document é o resultado da leitura de um documento Org
+type Document struct {
+	Title    string            // #+TITLE sem marcação
+	Author   string            // #+AUTHOR sem marcação
+	Keywords map[string]string // Demais #+CHAVE: valor, com a chave em maiúsculas
+	Headings []Heading
+
+	blocks    []node
+	headings  []*headingNode
+	todo      map[string]bool // Palavras TODO do documento (true = estado concluído)
+	titleIDs  map[string]string
+	customIDs map[string]string
+}
+
+var todoKeywordLine = regexp.MustCompile(`(?im)^[ \t]*#\+(?:seq_|typ_)?todo:[ \t]*(.*)$`)
+
+// Parse lê um documento Org
+func Parse(src string) *Document {
+	src = strings.TrimPrefix(src, "\ufeff")
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+
+	doc := &Document{
+		Keywords:  make(map[string]string),
+		todo:      map[string]bool{"TODO": false, "DONE": true},
+		titleIDs:  make(map[string]string),
+		customIDs: make(map[string]string),
+	}
+
+	// As palavras TODO valem para todo o documento, mesmo declaradas no fim
+	if matches := todoKeywordLine.FindAllStringSubmatch(src, -1); matches != nil {
+		doc.todo = make(map[string]bool)
+		for _, match := range matches {
+			parseTodoKeywords(doc.todo, match[1])
+		}
+	}
+
+	p := &parser{doc: doc}
+	doc.blocks = p.parseBlocks(strings.Split(src, "\n"), 0)
+	doc.Title = plainText(parseInline(doc.Title))
+	doc.Author = plainText(parseInline(doc.Author))
+	doc.assignIDs()
+	doc.Headings = buildTree(doc.headings)
+	return doc
+}
+
+// parseTodoKeywords lê "TODO NEXT | DONE CANCELED"; sem "|", a última palavra
+// é o estado concluído. Atalhos como "TODO(t)" são ignorados.
+func parseTodoKeywords(todo map[string]bool, value string) {
+	done := false
+	words := strings.Fields(value)
+	hasBar := false
+	for _, word := range words {
+		hasBar = hasBar || word == "|"
+	}
+	for i, word := range words {
+		if word == "|" {
+			done = true
+			continue
+		}
+		if index := strings.IndexByte(word, '('); index > 0 {
+			word = word[:index]
+		}
+		todo[word] = done || (!hasBar && i == len(words)-1)
+	}
+}
+
+// addKeyword guarda um #+CHAVE: valor. Chaves repetidas são concatenadas,
+// como o Org faz com #+TITLE em várias linhas.
+func (d *Document) addKeyword(key, value string) {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "TITLE":
+		d.Title = joinValue(d.Title, value)
+	case "AUTHOR":
+		d.Author = joinValue(d.Author, value)
+	default:
+		d.Keywords[key] = joinValue(d.Keywords[key], value)
+	}
+}
+
+func joinValue(current, value string) string {
+	if current == "" || value == "" {
+		return current + value
+	}
+	return current + " " + value
+}
+
+// assignIDs define os ids dos títulos: o CUSTOM_ID, se houver, ou o texto do
+// título; repetidos recebem um sufixo numérico
+func (d *Document) assignIDs() {
+	used := make(map[string]bool)
+	next := make(map[string]int) // Último sufixo usado em cada id repetido
+	for _, heading := range d.headings {
+		base := slug(heading.plain)
+		if heading.customID != "" {
+			base = slug(heading.customID)
+		}
+		id := idPrefix + base
+		for used[id] {
+			next[base] = max(next[base], 1) + 1
+			id = idPrefix + base + "-" + strconv.Itoa(next[base])
+		}
+		used[id] = true
+		heading.id = id
+
+		if heading.customID != "" {
+			d.customIDs[heading.customID] = id
+		}
+		if _, ok := d.titleIDs[heading.plain]; !ok {
+			d.titleIDs[heading.plain] = id
+		}
+	}
+}
+
+// buildTree monta a árvore de títulos a partir da lista em ordem
+func buildTree(headings []*headingNode) []Heading {
+	tree := []Heading{}
+	for len(headings) > 0 {
+		heading := headings[0]
+		end := 1
+		for end < len(headings) && headings[end].level > heading.level {
+			end++
+		}
+		tree = append(tree, Heading{
+			ID:       heading.id,
+			Level:    heading.level,
+			Title:    heading.plain,
+			Children: buildTree(headings[1:end]),
+		})
+		headings = headings[end:]
+	}
+	if len(tree) == 0 {
+		return nil
+	}
+	return tree
+}
+
+// slug gera um id com letras e dígitos em minúsculas separados por hífens
+func slug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "secao"
+	}
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package org
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := "\ufeff#+TITLE: *Meu* livro\r\n#+AUTHOR: Ana\n#+LANGUAGE: pt\n#+TODO: TODO NEXT | FEITO\n\n" +
+		"* NEXT [#A] Introdução :tag1:tag2:\nTexto.\n" +
+		"** Sub\n:PROPERTIES:\n:CUSTOM_ID: detalhes\n:END:\n" +
+		"* COMMENT Oculto\n** Filho oculto\n" +
+		"* Introdução\n"
+	doc := Parse(src)
+
+	if doc.Title != "Meu livro" || doc.Author != "Ana" {
+		t.Errorf("Title = %q, Author = %q", doc.Title, doc.Author)
+	}
+	if doc.Keywords["LANGUAGE"] != "pt" {
+		t.Errorf("Keywords = %v", doc.Keywords)
+	}
+
+	want := []Heading{
+		{ID: "org-introdução", Level: 1, Title: "Introdução", Children: []Heading{
+			{ID: "org-detalhes", Level: 2, Title: "Sub"},
+		}},
+		{ID: "org-introdução-2", Level: 1, Title: "Introdução"},
+	}
+	if !reflect.DeepEqual(doc.Headings, want) {
+		t.Errorf("Headings = %+v\nesperado   %+v", doc.Headings, want)
+	}
+
+	html := doc.HTML()
+	for _, fragment := range []string{
+		`<h2 id="org-introdução"><span class="todo">NEXT</span> <span class="priority">[A]</span> Introdução`,
+		`<span class="tag">tag1</span>`,
+		`<h3 id="org-detalhes">Sub</h3>`,
+	} {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("HTML sem %q:\n%s", fragment, html)
+		}
+	}
+	if strings.Contains(html, "Oculto") {
+		t.Errorf("subárvore COMMENT exportada:\n%s", html)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "ênfase",
+			src:  "*negrito* /itálico/ _sublinhado_ +riscado+ =literal= ~código~",
+			want: `<p><b>negrito</b> <i>itálico</i> <span class="underline">sublinhado</span> <del>riscado</del> <code>literal</code> <code>código</code></p>` + "\n",
+		},
+		{
+			name: "ênfase sem fechamento",
+			src:  "a *b c",
+			want: "<p>a *b c</p>\n",
+		},
+		{
+			name: "listas de tipos diferentes",
+			src:  "- a\n- [X] b\n\n1. um\n2. dois\n\n- termo :: definição",
+			want: "<ul>\n<li>a</li>\n<li class=\"on\"><code>[X]</code> b</li>\n</ul>\n" +
+				"<ol>\n<li>um</li>\n<li>dois</li>\n</ol>\n" +
+				"<dl>\n<dt>termo</dt><dd>definição</dd>\n</dl>\n",
+		},
+		{
+			name: "tabela",
+			src:  "| a | b |\n|---+---|\n| 1 | 2 |",
+			want: "<table>\n<thead>\n<tr><th>a</th><th>b</th></tr>\n</thead>\n<tbody>\n<tr><td>1</td><td>2</td></tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name: "bloco de código",
+			src:  "#+BEGIN_SRC go\nfunc main() {}\n,* escapado\n#+END_SRC",
+			want: "<pre class=\"src src-go\"><code class=\"language-go\">func main() {}\n* escapado</code></pre>\n",
+		},
+		{
+			name: "quebra de linha e régua",
+			src:  "linha\\\\\nnova\n-----",
+			want: "<p>linha<br>\nnova</p>\n<hr>\n",
+		},
+		{
+			name: "links",
+			src:  "[[https://example.com][site]] [[file:a.org]] [[https://x.org/a.png]]",
+			want: `<p><a href="https://example.com" rel="noopener noreferrer">site</a> file:a.org <img src="https://x.org/a.png" alt="a.png"></p>` + "\n",
+		},
+		{
+			name: "links internos",
+			src:  "* Capítulo\n:PROPERTIES:\n:CUSTOM_ID: cap\n:END:\n[[#cap][ir]] [[*Capítulo]] [[#inexistente][nada]]",
+			want: "<h2 id=\"org-cap\">Capítulo</h2>\n<p><a href=\"#org-cap\">ir</a> <a href=\"#org-cap\">Capítulo</a> nada</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.src).HTML(); got != tt.want {
+				t.Errorf("HTML =\n%s\nesperado\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLSanitized(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "HTML no texto", src: "<script>alert(1)</script> <img src=x onerror=alert(1)>"},
+		{name: "bloco de exportação", src: "#+BEGIN_EXPORT html\n<script>alert(1)</script>\n#+END_EXPORT"},
+		{name: "HTML de uma linha", src: "#+HTML: <script>alert(1)</script>"},
+		{name: "trecho de exportação", src: "@@html:<script>alert(1)</script>@@"},
+		{name: "link javascript", src: "[[javascript:alert(1)][clique]] [[JavaScript:alert(1)]]"},
+		{name: "link data", src: "[[data:text/html,<script>alert(1)</script>][x]]"},
+		{name: "aspas no link", src: `[[https://x.org/"onmouseover="alert(1)][x]]`},
+		{name: "aspas na linguagem", src: "#+BEGIN_SRC \"><script>alert(1)</script>\nx\n#+END_SRC"},
+		{name: "aspas na tag", src: `* Título :"><script>:`},
+		{name: "aspas no CUSTOM_ID", src: "* T\n:PROPERTIES:\n:CUSTOM_ID: \"><script>\n:END:"},
+		{name: "bloco especial", src: "#+BEGIN_\"><script>\nx\n#+END_\"><script>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := Parse(tt.src).HTML()
+			assertSafe(t, html)
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "vazio", src: ""},
+		{name: "só quebras de linha", src: "\n\n\r\n"},
+		{name: "bloco sem fim", src: "#+BEGIN_QUOTE\nsem fim\n* Título"},
+		{name: "código sem fim", src: "#+BEGIN_SRC go\nfunc"},
+		{name: "gaveta sem fim", src: "* T\n:PROPERTIES:\n:CUSTOM_ID: x"},
+		{name: "fim sem início", src: "#+END_SRC\n:END:"},
+		{name: "título só com asteriscos", src: "*\n**\n*** "},
+		{name: "prioridade e tags vazias", src: "* [#] ::\n* TODO"},
+		{name: "link sem fechamento", src: "[[https://x.org][desc"},
+		{name: "link aninhado", src: "[[a][[[b][c]]]]"},
+		{name: "link vazio", src: "[[]] [[][x]]"},
+		{name: "trecho de exportação sem fim", src: "@@html:<b>"},
+		{name: "tabela irregular", src: "|a\n|-\n||||\n|"},
+		{name: "lista com contador inválido", src: "1. [@abc] a\n2. [@99999999999999999999] b"},
+		{name: "aninhamento profundo de listas", src: strings.Repeat("- a\n  ", 200)},
+		{name: "aninhamento profundo de blocos", src: strings.Repeat("#+BEGIN_QUOTE\n", 200) + strings.Repeat("#+END_QUOTE\n", 200)},
+		{name: "ênfase aninhada", src: strings.Repeat("*/_+", 100) + "x" + strings.Repeat("+_/*", 100)},
+		{name: "UTF-8 inválido", src: "* \xff\xfe título\n\xc3"},
+		{name: "TODO declarado vazio", src: "#+TODO:\n* TODO x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSafe(t, Parse(tt.src).HTML())
+		})
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	src := "#+TITLE: Livro\n* TODO [#A] Capítulo :tag:\n:PROPERTIES:\n:CUSTOM_ID: c1\n:END:\n" +
+		"Texto *forte* e [[https://x.org][link]].\n- [X] item\n  1. sub\n| a | b |\n|---|\n" +
+		"#+BEGIN_SRC go\nx\n#+END_SRC\n#+BEGIN_QUOTE\ncitação\n#+END_QUOTE\n"
+	for size := 0; size <= len(src); size++ {
+		assertSafe(t, Parse(src[:size]).HTML())
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("#+TITLE: Livro\n* TODO [#A] Capítulo :tag:\nTexto *forte* e [[https://x.org][link]].")
+	f.Add("- [X] item\n  1. sub\n     - termo :: def\n| a | b |\n|---+---|\n| 1 | 2 |")
+	f.Add("#+BEGIN_SRC go\nx\n#+END_SRC\n#+BEGIN_QUOTE\n#+BEGIN_VERSE\nv\n#+END_VERSE\n#+END_QUOTE")
+	f.Add("[[#id][a]] [[*T]] @@html:<b>@@ https://x.org/a.png linha\\\\\n-----")
+
+	f.Fuzz(func(t *testing.T, src string) {
+		doc := Parse(src)
+		assertSafe(t, doc.HTML())
+	})
+}
+
+var (
+	htmlTag  = regexp.MustCompile(`<[^>]*>`)
+	tagParts = regexp.MustCompile(`^</?([a-z0-9]+)((?: [a-z]+="[^"<>]*")*)>$`)
+	tagAttr  = regexp.MustCompile(`([a-z]+)="([^"]*)"`)
+
+	safeTags  = map[string]bool{"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "p": true, "b": true, "i": true, "del": true, "span": true, "code": true, "pre": true, "a": true, "img": true, "br": true, "hr": true, "ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true, "table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true, "blockquote": true, "div": true}
+	safeAttrs = map[string]bool{"id": true, "class": true, "href": true, "rel": true, "src": true, "alt": true, "start": true}
+)
+
+// assertSafe confere que o HTML gerado só tem as tags e os atributos do
+// renderizador e que os links só usam os esquemas permitidos
+func assertSafe(t *testing.T, html string) {
+	t.Helper()
+	for _, tag := range htmlTag.FindAllString(html, -1) {
+		parts := tagParts.FindStringSubmatch(tag)
+		if parts == nil || !safeTags[parts[1]] {
+			t.Fatalf("tag inesperada %q em:\n%s", tag, html)
+		}
+		for _, attr := range tagAttr.FindAllStringSubmatch(parts[2], -1) {
+			if !safeAttrs[attr[1]] {
+				t.Fatalf("atributo inesperado %q em:\n%s", tag, html)
+			}
+			if attr[1] == "href" || attr[1] == "src" {
+				value := strings.ToLower(attr[2])
+				if !strings.HasPrefix(value, "#") && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") &&
+					!strings.HasPrefix(value, "ftp://") && !strings.HasPrefix(value, "mailto:") {
+					t.Fatalf("link inseguro %q em:\n%s", tag, html)
+				}
+			}
+		}
+	}
+	if strings.Count(html, "<") != len(htmlTag.FindAllString(html, -1)) {
+		t.Fatalf("< sem escape em:\n%s", html)
+	}
+}
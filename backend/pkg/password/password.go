@@ -4,6 +4,10 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyHash é gerado na inicialização para que a primeira chamada de VerifyDummy
+// tenha o mesmo custo das demais
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("cloud-reader-dummy-password"), bcrypt.DefaultCost)
+
 // Hash gera um hash bcrypt da senha fornecida
 func Hash(password string) (string, error) {
 	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -19,3 +23,10 @@ func Verify(password, hashedPassword string) bool {
 	return err == nil
 }
 
+// VerifyDummy executa uma comparação bcrypt com o mesmo custo de Verify e sempre
+// retorna false. Deve ser usada quando o usuário não existe, para que o tempo de
+// resposta não revele se o email está cadastrado.
+func VerifyDummy(password string) bool {
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+	return false
+}
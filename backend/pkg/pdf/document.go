@@ -0,0 +1,369 @@
+// Package pdf lê a estrutura de um PDF (xref, trailer, catálogo) para extrair
+// o número de páginas, os metadados (dicionário Info e XMP) e o sumário.
+// Não renderiza nem extrai o texto das páginas.
+package pdf
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPages limita o percurso da árvore de páginas
+	maxPages = 200000
+
+	// maxOutlineItems e maxOutlineDepth limitam a leitura do sumário
+	maxOutlineItems = 10000
+	maxOutlineDepth = 16
+
+	// maxNameTreeDepth limita a busca de destinos nomeados
+	maxNameTreeDepth = 32
+)
+
+// Metadata são os metadados do documento (Info e XMP, com prioridade para o Info)
+type Metadata struct {
+	Title        string
+	Authors      []string
+	Subject      string
+	Keywords     []string
+	Creator      string // Aplicativo que criou o documento original
+	Producer     string
+	Publisher    string
+	Language     string
+	CreationDate *time.Time
+}
+
+// OutlineItem é uma entrada do sumário. Page começa em 1 (0 = destino desconhecido).
+type OutlineItem struct {
+	Title    string        `json:"title"`
+	Page     int           `json:"page,omitempty"`
+	Children []OutlineItem `json:"children,omitempty"`
+}
+
+// Document é o resultado da leitura de um PDF
+type Document struct {
+	Version   string
+	PageCount int
+	Metadata  Metadata
+	Outline   []OutlineItem
+
+	// Encrypted indica um PDF cifrado: as strings (título, sumário) não podem
+	// ser lidas, apenas a estrutura e o número de páginas
+	Encrypted bool
+}
+
+// Read lê a estrutura de um PDF
+func Read(r io.ReaderAt, size int64) (*Document, error) {
+	header := make([]byte, 1024)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	start := strings.Index(string(header[:n]), "%PDF-")
+	if start < 0 {
+		return nil, errors.New("pdf: cabeçalho %PDF- não encontrado")
+	}
+
+	rd, err := newReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Encrypted: rd.trailer[name("Encrypt")] != nil}
+	if fields := strings.Fields(string(header[start+5 : n])); len(fields) > 0 {
+		doc.Version = fields[0]
+	}
+
+	catalog, ok := rd.resolve(rd.trailer[name("Root")]).(dict)
+	if !ok {
+		return nil, errors.New("pdf: catálogo não encontrado")
+	}
+	if version, ok := rd.resolve(catalog[name("Version")]).(name); ok && string(version) > doc.Version {
+		doc.Version = string(version)
+	}
+
+	pages := rd.pageIndex(catalog)
+	doc.PageCount = len(pages)
+	if doc.PageCount == 0 {
+		// Árvore de páginas ilegível: usa a contagem declarada na raiz
+		if root, ok := rd.resolve(catalog[name("Pages")]).(dict); ok {
+			if count, ok := rd.resolve(root[name("Count")]).(int64); ok && count > 0 && count <= maxPages {
+				doc.PageCount = int(count)
+			}
+		}
+	}
+
+	if !doc.Encrypted {
+		doc.Metadata = rd.metadata(catalog)
+		doc.Outline = rd.outline(catalog, pages)
+	}
+
+	return doc, nil
+}
+
+// pageIndex percorre a árvore de páginas e mapeia o número de cada objeto de
+// página para sua posição (a partir de 1)
+func (rd *reader) pageIndex(catalog dict) map[int]int {
+	pages := make(map[int]int)
+	visited := make(map[int]bool)
+
+	var walk func(node object, depth int)
+	walk = func(node object, depth int) {
+		if depth > maxNesting || len(pages) >= maxPages {
+			return
+		}
+		num := -1
+		if r, ok := node.(ref); ok {
+			if visited[r.num] {
+				return
+			}
+			visited[r.num] = true
+			num = r.num
+		}
+
+		d, ok := rd.resolve(node).(dict)
+		if !ok {
+			return
+		}
+		kids, hasKids := rd.resolve(d[name("Kids")]).(array)
+		if d[name("Type")] == name("Page") || (!hasKids && d[name("Type")] != name("Pages")) {
+			if num >= 0 {
+				pages[num] = len(pages) + 1
+			}
+			return
+		}
+		for _, kid := range kids {
+			walk(kid, depth+1)
+		}
+	}
+	walk(catalog[name("Pages")], 0)
+	return pages
+}
+
+// metadata combina o dicionário Info com o XMP do catálogo
+func (rd *reader) metadata(catalog dict) Metadata {
+	var meta Metadata
+
+	info, _ := rd.resolve(rd.trailer[name("Info")]).(dict)
+	text := func(key string) string {
+		value, _ := rd.resolve(info[name(key)]).(string)
+		return decodeText(value)
+	}
+
+	meta.Title = text("Title")
+	meta.Authors = splitList(text("Author"), ";")
+	meta.Subject = text("Subject")
+	meta.Keywords = splitList(text("Keywords"), ",;")
+	meta.Creator = text("Creator")
+	meta.Producer = text("Producer")
+	if raw, ok := rd.resolve(info[name("CreationDate")]).(string); ok {
+		meta.CreationDate = parseDate(raw)
+	}
+	if lang, ok := rd.resolve(catalog[name("Lang")]).(string); ok {
+		meta.Language = decodeText(lang)
+	}
+
+	// O XMP completa o que falta no Info (ou o substitui em arquivos PDF 2.0,
+	// em que o Info é opcional)
+	if s, ok := rd.resolve(catalog[name("Metadata")]).(stream); ok {
+		if data, err := rd.streamData(s); err == nil {
+			xmp := parseXMP(data)
+			if meta.Title == "" {
+				meta.Title = clean(xmp.Title)
+			}
+			if len(meta.Authors) == 0 {
+				for _, creator := range xmp.Creators {
+					if creator = clean(creator); creator != "" {
+						meta.Authors = append(meta.Authors, creator)
+					}
+				}
+			}
+			if meta.Subject == "" {
+				meta.Subject = clean(xmp.Description)
+			}
+			if len(meta.Keywords) == 0 {
+				meta.Keywords = splitList(clean(xmp.Keywords), ",;")
+				for _, subject := range xmp.Subjects {
+					if subject = clean(subject); subject != "" {
+						meta.Keywords = append(meta.Keywords, subject)
+					}
+				}
+			}
+			if meta.CreationDate == nil {
+				meta.CreationDate = xmp.CreationDate
+			}
+			meta.Publisher = clean(xmp.Publisher)
+			if meta.Language == "" {
+				meta.Language = clean(xmp.Language)
+			}
+		}
+	}
+
+	return meta
+}
+
+// outline lê o sumário (/Outlines) resolvendo o destino de cada entrada
+func (rd *reader) outline(catalog dict, pages map[int]int) []OutlineItem {
+	root, ok := rd.resolve(catalog[name("Outlines")]).(dict)
+	if !ok {
+		return nil
+	}
+
+	dests := rd.namedDests(catalog)
+	visited := make(map[int]bool)
+	count := 0
+
+	var walk func(node object, depth int) []OutlineItem
+	walk = func(node object, depth int) []OutlineItem {
+		if depth > maxOutlineDepth {
+			return nil
+		}
+		var items []OutlineItem
+		for node != nil && count < maxOutlineItems {
+			r, ok := node.(ref)
+			if !ok || visited[r.num] {
+				break
+			}
+			visited[r.num] = true
+			count++
+
+			d, ok := rd.resolve(r).(dict)
+			if !ok {
+				break
+			}
+
+			title, _ := rd.resolve(d[name("Title")]).(string)
+			item := OutlineItem{
+				Title: decodeText(title),
+				Page:  rd.destPage(rd.itemDest(d), dests, pages),
+			}
+			item.Children = walk(d[name("First")], depth+1)
+			if item.Title != "" || len(item.Children) > 0 {
+				items = append(items, item)
+			}
+
+			node = d[name("Next")]
+		}
+		return items
+	}
+	return walk(root[name("First")], 0)
+}
+
+// itemDest retorna o destino de uma entrada: /Dest ou a ação /A do tipo GoTo
+func (rd *reader) itemDest(item dict) object {
+	if dest := rd.resolve(item[name("Dest")]); dest != nil {
+		return dest
+	}
+	action, ok := rd.resolve(item[name("A")]).(dict)
+	if !ok || rd.resolve(action[name("S")]) != name("GoTo") {
+		return nil
+	}
+	return rd.resolve(action[name("D")])
+}
+
+// destPage converte um destino (array explícito ou nome) no número da página
+func (rd *reader) destPage(dest object, dests func(string) object, pages map[int]int) int {
+	for i := 0; i < 4; i++ {
+		switch value := dest.(type) {
+		case string:
+			dest = dests(value)
+		case name:
+			dest = dests(string(value))
+		case dict:
+			// Destinos nomeados podem ser dicionários com a chave /D
+			dest = rd.resolve(value[name("D")])
+		case array:
+			if len(value) == 0 {
+				return 0
+			}
+			switch page := value[0].(type) {
+			case ref:
+				return pages[page.num]
+			case int64:
+				// Destinos remotos usam o índice da página (a partir de 0)
+				if page >= 0 && int(page) < len(pages) {
+					return int(page) + 1
+				}
+			}
+			return 0
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// namedDests retorna a busca de destinos nomeados: o dicionário /Dests do
+// catálogo (PDF 1.1) e a árvore de nomes /Names /Dests
+func (rd *reader) namedDests(catalog dict) func(string) object {
+	oldDests, _ := rd.resolve(catalog[name("Dests")]).(dict)
+	var tree dict
+	if names, ok := rd.resolve(catalog[name("Names")]).(dict); ok {
+		tree, _ = rd.resolve(names[name("Dests")]).(dict)
+	}
+
+	return func(key string) object {
+		if dest, ok := oldDests[name(key)]; ok {
+			return rd.resolve(dest)
+		}
+		if tree != nil {
+			return rd.resolve(rd.lookupName(tree, key, 0))
+		}
+		return nil
+	}
+}
+
+// lookupName busca uma chave em uma árvore de nomes, usando os /Limits de
+// cada nó intermediário para descer só pelo ramo certo
+func (rd *reader) lookupName(node dict, key string, depth int) object {
+	if depth > maxNameTreeDepth {
+		return nil
+	}
+
+	if names, ok := rd.resolve(node[name("Names")]).(array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			if k, _ := rd.resolve(names[i]).(string); k == key {
+				return names[i+1]
+			}
+		}
+	}
+
+	kids, _ := rd.resolve(node[name("Kids")]).(array)
+	for _, kid := range kids {
+		child, ok := rd.resolve(kid).(dict)
+		if !ok {
+			continue
+		}
+		if limits, ok := rd.resolve(child[name("Limits")]).(array); ok && len(limits) == 2 {
+			low, _ := rd.resolve(limits[0]).(string)
+			high, _ := rd.resolve(limits[1]).(string)
+			if key < low || key > high {
+				continue
+			}
+		}
+		if dest := rd.lookupName(child, key, depth+1); dest != nil {
+			return dest
+		}
+	}
+	return nil
+}
+
+// splitList separa uma lista de autores ou palavras-chave
+func splitList(value string, separators string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	}) {
+		if item = clean(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// clean remove espaços extras e quebras de linha
+func clean(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,338 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"sort"
+)
+
+const (
+	// minImageSide descarta ícones e logotipos ao procurar a imagem da capa
+	minImageSide = 100
+
+	// maxImagePixels limita a decodificação de imagens grandes demais
+	maxImagePixels = 40 << 20
+)
+
+// ErrNoImage indica que a primeira página não tem uma imagem utilizável
+var ErrNoImage = errors.New("pdf: primeira página sem imagem")
+
+// FirstPageImage retorna a maior imagem da primeira página. Digitalizações e
+// a maioria dos livros com capa ilustrada trazem a capa como uma imagem que
+// ocupa a página; páginas só com texto e vetores retornam ErrNoImage.
+// São suportadas imagens JPEG (DCTDecode) e imagens sem compressão ou com
+// FlateDecode em tons de cinza, RGB, CMYK ou com paleta.
+func FirstPageImage(r io.ReaderAt, size int64) (image.Image, error) {
+	rd, err := newReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if rd.trailer[name("Encrypt")] != nil {
+		return nil, ErrEncrypted
+	}
+
+	catalog, ok := rd.resolve(rd.trailer[name("Root")]).(dict)
+	if !ok {
+		return nil, errors.New("pdf: catálogo não encontrado")
+	}
+	resources := rd.firstPageResources(catalog)
+	if resources == nil {
+		return nil, ErrNoImage
+	}
+
+	images := rd.images(resources, 0, make(map[int]bool))
+	sort.SliceStable(images, func(i, j int) bool {
+		return imageArea(images[i]) > imageArea(images[j])
+	})
+
+	for _, s := range images {
+		img, err := rd.decodeImage(s)
+		if err == nil {
+			return img, nil
+		}
+	}
+	return nil, ErrNoImage
+}
+
+// firstPageResources desce pela árvore até a primeira página e retorna seus
+// recursos, herdados dos nós intermediários quando a página não os define
+func (rd *reader) firstPageResources(catalog dict) dict {
+	var resources dict
+	node := catalog[name("Pages")]
+	for depth := 0; depth <= maxNesting; depth++ {
+		d, ok := rd.resolve(node).(dict)
+		if !ok {
+			return nil
+		}
+		if res, ok := rd.resolve(d[name("Resources")]).(dict); ok {
+			resources = res
+		}
+
+		kids, hasKids := rd.resolve(d[name("Kids")]).(array)
+		if d[name("Type")] == name("Page") || !hasKids {
+			return resources
+		}
+		if len(kids) == 0 {
+			return nil
+		}
+		node = kids[0]
+	}
+	return nil
+}
+
+// images lista as imagens dos recursos, incluindo as de formulários (Form
+// XObjects) usados para posicionar a imagem na página
+func (rd *reader) images(resources dict, depth int, visited map[int]bool) []stream {
+	xobjects, ok := rd.resolve(resources[name("XObject")]).(dict)
+	if !ok {
+		return nil
+	}
+
+	var images []stream
+	for _, value := range xobjects {
+		if r, ok := value.(ref); ok {
+			if visited[r.num] {
+				continue
+			}
+			visited[r.num] = true
+		}
+		s, ok := rd.resolve(value).(stream)
+		if !ok {
+			continue
+		}
+
+		switch rd.resolve(s.dict[name("Subtype")]) {
+		case name("Image"):
+			if mask, _ := rd.resolve(s.dict[name("ImageMask")]).(bool); !mask {
+				images = append(images, s)
+			}
+		case name("Form"):
+			if res, ok := rd.resolve(s.dict[name("Resources")]).(dict); ok && depth < 3 {
+				images = append(images, rd.images(res, depth+1, visited)...)
+			}
+		}
+	}
+	return images
+}
+
+// imageArea retorna a área da imagem para ordenar as candidatas; dimensões
+// acima do limite contam como maxImagePixels+1 e são rejeitadas depois
+func imageArea(s stream) int64 {
+	width, _ := s.dict[name("Width")].(int64)
+	height, _ := s.dict[name("Height")].(int64)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	if !fitsPixels(width, height) {
+		return maxImagePixels + 1
+	}
+	return width * height
+}
+
+// fitsPixels informa se width*height não passa de maxImagePixels, sem
+// calcular o produto (que pode estourar com dimensões forjadas)
+func fitsPixels(width, height int64) bool {
+	return width <= maxImagePixels/height
+}
+
+// decodeImage decodifica um XObject de imagem
+func (rd *reader) decodeImage(s stream) (image.Image, error) {
+	width, _ := rd.resolve(s.dict[name("Width")]).(int64)
+	height, _ := rd.resolve(s.dict[name("Height")]).(int64)
+	if width < minImageSide || height < minImageSide || !fitsPixels(width, height) {
+		return nil, errors.New("pdf: dimensões de imagem inválidas")
+	}
+
+	data, final, err := rd.decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch final {
+	case name("DCTDecode"), name("DCT"):
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if config.Width <= 0 || config.Height <= 0 || !fitsPixels(int64(config.Width), int64(config.Height)) {
+			return nil, errors.New("pdf: imagem grande demais")
+		}
+		return jpeg.Decode(bytes.NewReader(data))
+	case "":
+		return rd.decodeRaw(s, data, int(width), int(height))
+	default:
+		return nil, fmt.Errorf("pdf: filtro de imagem não suportado: %s", final)
+	}
+}
+
+// colorSpace descreve como converter as amostras de uma imagem em cores
+type colorSpace struct {
+	components int
+	palette    []color.RGBA // Espaço /Indexed
+}
+
+// colorSpace interpreta o /ColorSpace de uma imagem
+func (rd *reader) colorSpace(value object, depth int) (*colorSpace, error) {
+	if depth > 2 {
+		return nil, errSyntax
+	}
+
+	switch cs := rd.resolve(value).(type) {
+	case name:
+		switch cs {
+		case "DeviceGray", "G", "CalGray":
+			return &colorSpace{components: 1}, nil
+		case "DeviceRGB", "RGB", "CalRGB":
+			return &colorSpace{components: 3}, nil
+		case "DeviceCMYK", "CMYK":
+			return &colorSpace{components: 4}, nil
+		}
+	case array:
+		if len(cs) == 0 {
+			break
+		}
+		switch rd.resolve(cs[0]) {
+		case name("ICCBased"):
+			if len(cs) < 2 {
+				break
+			}
+			profile, ok := rd.resolve(cs[1]).(stream)
+			if !ok {
+				break
+			}
+			if n, ok := rd.resolve(profile.dict[name("N")]).(int64); ok && (n == 1 || n == 3 || n == 4) {
+				return &colorSpace{components: int(n)}, nil
+			}
+		case name("CalGray"):
+			return &colorSpace{components: 1}, nil
+		case name("CalRGB"):
+			return &colorSpace{components: 3}, nil
+		case name("Indexed"), name("I"):
+			if len(cs) < 4 {
+				break
+			}
+			return rd.indexedColorSpace(cs, depth)
+		}
+	}
+	return nil, fmt.Errorf("pdf: espaço de cores não suportado: %v", value)
+}
+
+// indexedColorSpace lê a paleta de um espaço [/Indexed base hival lookup]
+func (rd *reader) indexedColorSpace(cs array, depth int) (*colorSpace, error) {
+	base, err := rd.colorSpace(cs[1], depth+1)
+	if err != nil || base.palette != nil {
+		return nil, errors.New("pdf: paleta não suportada")
+	}
+	hival, _ := rd.resolve(cs[2]).(int64)
+	if hival < 0 || hival > 255 {
+		return nil, errSyntax
+	}
+
+	var lookup []byte
+	switch value := rd.resolve(cs[3]).(type) {
+	case string:
+		lookup = []byte(value)
+	case stream:
+		if lookup, err = rd.streamData(value); err != nil {
+			return nil, err
+		}
+	}
+
+	palette := make([]color.RGBA, hival+1)
+	for i := range palette {
+		offset := i * base.components
+		if offset+base.components > len(lookup) {
+			break
+		}
+		palette[i] = toRGBA(lookup[offset:offset+base.components], base.components)
+	}
+	return &colorSpace{components: 1, palette: palette}, nil
+}
+
+// decodeRaw monta a imagem a partir das amostras descompactadas
+func (rd *reader) decodeRaw(s stream, data []byte, width, height int) (image.Image, error) {
+	cs, err := rd.colorSpace(s.dict[name("ColorSpace")], 0)
+	if err != nil {
+		return nil, err
+	}
+
+	bits, _ := rd.resolve(s.dict[name("BitsPerComponent")]).(int64)
+	switch {
+	case bits == 8:
+	case (bits == 1 || bits == 2 || bits == 4) && cs.components == 1:
+	default:
+		return nil, fmt.Errorf("pdf: %d bits por componente não suportado", bits)
+	}
+
+	// width e height já foram limitados por decodeImage; os fatores são
+	// conferidos separadamente para que rowSize*height não estoure
+	rowSize := (width*cs.components*int(bits) + 7) / 8
+	if rowSize <= 0 || rowSize > len(data) || height > len(data)/rowSize {
+		return nil, errors.New("pdf: dados da imagem incompletos")
+	}
+
+	// /Decode [1 0] inverte os tons de cinza (comum em digitalizações de 1 bit)
+	invert := false
+	if decode, ok := rd.resolve(s.dict[name("Decode")]).(array); ok && len(decode) >= 2 && cs.palette == nil {
+		low, _ := decode[0].(int64)
+		high, _ := decode[1].(int64)
+		invert = low == 1 && high == 0
+	}
+
+	maxValue := 1<<bits - 1
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := data[y*rowSize : (y+1)*rowSize]
+		for x := 0; x < width; x++ {
+			var c color.RGBA
+			switch {
+			case cs.palette != nil:
+				c = paletteColor(cs.palette, sampleAt(row, x, int(bits)))
+			case bits == 8:
+				c = toRGBA(row[x*cs.components:(x+1)*cs.components], cs.components)
+			default:
+				gray := byte(sampleAt(row, x, int(bits)) * 255 / maxValue)
+				c = color.RGBA{gray, gray, gray, 255}
+			}
+			if invert {
+				c = color.RGBA{255 - c.R, 255 - c.G, 255 - c.B, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img, nil
+}
+
+// sampleAt lê a amostra x de uma linha com um componente por pixel
+func sampleAt(row []byte, x, bits int) int {
+	if bits == 8 {
+		return int(row[x])
+	}
+	bit := x * bits
+	return int(row[bit/8]>>(8-bits-bit%8)) & (1<<bits - 1)
+}
+
+func paletteColor(palette []color.RGBA, index int) color.RGBA {
+	if index >= len(palette) {
+		return color.RGBA{A: 255}
+	}
+	return palette[index]
+}
+
+// toRGBA converte uma amostra em cinza, RGB ou CMYK
+func toRGBA(sample []byte, components int) color.RGBA {
+	switch components {
+	case 1:
+		return color.RGBA{sample[0], sample[0], sample[0], 255}
+	case 3:
+		return color.RGBA{sample[0], sample[1], sample[2], 255}
+	default:
+		r, g, b := color.CMYKToRGB(sample[0], sample[1], sample[2], sample[3])
+		return color.RGBA{r, g, b, 255}
+	}
+}
@@ -0,0 +1,457 @@
+package pdf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Tipos dos objetos PDF. Strings são mantidas como bytes crus (string Go) e
+// decodificadas como texto só quando usadas.
+type (
+	name  string
+	dict  map[name]object
+	array []object
+	ref   struct{ num, gen int }
+
+	// stream guarda o dicionário e a posição dos dados no arquivo
+	stream struct {
+		dict   dict
+		offset int64
+	}
+
+	object interface{}
+)
+
+// keyword é uma palavra reservada (obj, endobj, stream, R, xref, trailer...)
+type keyword string
+
+// Delimitadores de dicionário e array
+type delimiter byte
+
+// maxNesting limita a profundidade de arrays e dicionários aninhados
+const maxNesting = 64
+
+var errSyntax = errors.New("pdf: erro de sintaxe")
+
+// lexer lê tokens e objetos a partir de uma posição do arquivo
+type lexer struct {
+	r      *bufio.Reader
+	pos    int64         // Posição absoluta do próximo byte
+	tokens []interface{} // Tokens devolvidos (pilha)
+}
+
+func newLexer(r io.Reader, offset int64) *lexer {
+	return &lexer{r: bufio.NewReader(r), pos: offset}
+}
+
+func (l *lexer) readByte() (byte, error) {
+	b, err := l.r.ReadByte()
+	if err == nil {
+		l.pos++
+	}
+	return b, err
+}
+
+func (l *lexer) unreadByte() {
+	if l.r.UnreadByte() == nil {
+		l.pos--
+	}
+}
+
+func isSpace(b byte) bool {
+	switch b {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(b byte) bool {
+	switch b {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace pula espaços e comentários
+func (l *lexer) skipSpace() error {
+	for {
+		b, err := l.readByte()
+		if err != nil {
+			return err
+		}
+		if b == '%' {
+			for b != '\r' && b != '\n' {
+				if b, err = l.readByte(); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !isSpace(b) {
+			l.unreadByte()
+			return nil
+		}
+	}
+}
+
+// unread devolve um token para ser lido de novo
+func (l *lexer) unread(token interface{}) {
+	l.tokens = append(l.tokens, token)
+}
+
+// token lê o próximo token: número (int64 ou float64), name, string, keyword ou delimiter
+func (l *lexer) token() (interface{}, error) {
+	if n := len(l.tokens); n > 0 {
+		token := l.tokens[n-1]
+		l.tokens = l.tokens[:n-1]
+		return token, nil
+	}
+
+	if err := l.skipSpace(); err != nil {
+		return nil, err
+	}
+	b, err := l.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch b {
+	case '/':
+		return l.readName()
+	case '(':
+		return l.readLiteralString()
+	case '<':
+		next, err := l.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if next == '<' {
+			return delimiter('{'), nil
+		}
+		l.unreadByte()
+		return l.readHexString()
+	case '>':
+		if next, err := l.readByte(); err != nil || next != '>' {
+			return nil, errSyntax
+		}
+		return delimiter('}'), nil
+	case '[', ']':
+		return delimiter(b), nil
+	case ')', '{', '}':
+		return nil, errSyntax
+	}
+
+	l.unreadByte()
+	word, err := l.readWord()
+	if err != nil {
+		return nil, err
+	}
+	if (word[0] >= '0' && word[0] <= '9') || word[0] == '-' || word[0] == '+' || word[0] == '.' {
+		if n, err := strconv.ParseInt(word, 10, 64); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(word, 64); err == nil {
+			return f, nil
+		}
+		return nil, errSyntax
+	}
+	return keyword(word), nil
+}
+
+// readWord lê bytes até um espaço ou delimitador
+func (l *lexer) readWord() (string, error) {
+	var word []byte
+	for {
+		b, err := l.readByte()
+		if err == io.EOF && len(word) > 0 {
+			return string(word), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if isSpace(b) || isDelimiter(b) {
+			l.unreadByte()
+			if len(word) == 0 {
+				return "", errSyntax
+			}
+			return string(word), nil
+		}
+		if len(word) > 256 {
+			return "", errSyntax
+		}
+		word = append(word, b)
+	}
+}
+
+// readName lê um nome, decodificando os escapes #xx
+func (l *lexer) readName() (name, error) {
+	var value []byte
+	for {
+		b, err := l.readByte()
+		if err == io.EOF {
+			return name(value), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if isSpace(b) || isDelimiter(b) {
+			l.unreadByte()
+			return name(value), nil
+		}
+		if b == '#' {
+			hex := make([]byte, 2)
+			if _, err := io.ReadFull(l.r, hex); err == nil {
+				if decoded, err := strconv.ParseUint(string(hex), 16, 8); err == nil {
+					l.pos += 2
+					value = append(value, byte(decoded))
+					continue
+				}
+				return "", errSyntax
+			}
+		}
+		value = append(value, b)
+	}
+}
+
+// readLiteralString lê uma string entre parênteses com escapes e parênteses aninhados
+func (l *lexer) readLiteralString() (string, error) {
+	var value []byte
+	depth := 1
+	for {
+		b, err := l.readByte()
+		if err != nil {
+			return "", err
+		}
+		switch b {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return string(value), nil
+			}
+		case '\\':
+			if b, err = l.readByte(); err != nil {
+				return "", err
+			}
+			switch b {
+			case 'n':
+				b = '\n'
+			case 'r':
+				b = '\r'
+			case 't':
+				b = '\t'
+			case 'b':
+				b = '\b'
+			case 'f':
+				b = '\f'
+			case '\r':
+				// Continuação de linha
+				if next, err := l.readByte(); err == nil && next != '\n' {
+					l.unreadByte()
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if b >= '0' && b <= '7' {
+					octal := int(b - '0')
+					for i := 0; i < 2; i++ {
+						next, err := l.readByte()
+						if err != nil {
+							return "", err
+						}
+						if next < '0' || next > '7' {
+							l.unreadByte()
+							break
+						}
+						octal = octal*8 + int(next-'0')
+					}
+					b = byte(octal)
+				}
+			}
+		}
+		value = append(value, b)
+	}
+}
+
+// readHexString lê uma string hexadecimal (<48656C6C6F>)
+func (l *lexer) readHexString() (string, error) {
+	var value []byte
+	digit := -1
+	for {
+		b, err := l.readByte()
+		if err != nil {
+			return "", err
+		}
+		if b == '>' {
+			if digit >= 0 {
+				value = append(value, byte(digit<<4))
+			}
+			return string(value), nil
+		}
+		if isSpace(b) {
+			continue
+		}
+		n, err := strconv.ParseUint(string(b), 16, 8)
+		if err != nil {
+			return "", errSyntax
+		}
+		if digit < 0 {
+			digit = int(n)
+		} else {
+			value = append(value, byte(digit<<4|int(n)))
+			digit = -1
+		}
+	}
+}
+
+// object lê um objeto completo, resolvendo a sequência "num gen R" em referência
+func (l *lexer) object() (object, error) {
+	return l.nestedObject(0)
+}
+
+func (l *lexer) nestedObject(depth int) (object, error) {
+	if depth > maxNesting {
+		return nil, errSyntax
+	}
+
+	token, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case delimiter:
+		switch value {
+		case '{':
+			return l.readDict(depth)
+		case '[':
+			return l.readArray(depth)
+		}
+		return nil, errSyntax
+	case keyword:
+		switch value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return value, nil
+	case int64:
+		// Pode ser o início de uma referência "num gen R"
+		gen, err := l.token()
+		if err != nil {
+			return value, nil
+		}
+		if genValue, ok := gen.(int64); ok {
+			r, err := l.token()
+			if err == nil && r == keyword("R") {
+				return ref{num: int(value), gen: int(genValue)}, nil
+			}
+			if err == nil {
+				l.unread(r)
+			}
+		}
+		l.unread(gen)
+		return value, nil
+	}
+	return token, nil
+}
+
+func (l *lexer) readDict(depth int) (dict, error) {
+	result := make(dict)
+	for {
+		token, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		if token == delimiter('}') {
+			return result, nil
+		}
+		key, ok := token.(name)
+		if !ok {
+			return nil, errSyntax
+		}
+		value, err := l.nestedObject(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, isKeyword := value.(keyword); isKeyword {
+			return nil, errSyntax
+		}
+		result[key] = value
+	}
+}
+
+func (l *lexer) readArray(depth int) (array, error) {
+	var result array
+	for {
+		token, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		if token == delimiter(']') {
+			return result, nil
+		}
+		l.unread(token)
+		value, err := l.nestedObject(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, isKeyword := value.(keyword); isKeyword {
+			return nil, errSyntax
+		}
+		result = append(result, value)
+	}
+}
+
+// indirectObject lê "num gen obj ... endobj" e retorna o objeto (ou o stream)
+func (l *lexer) indirectObject(num int) (object, error) {
+	for _, expected := range []interface{}{int64(num), nil, keyword("obj")} {
+		token, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		if expected == nil {
+			if _, ok := token.(int64); !ok {
+				return nil, errSyntax
+			}
+			continue
+		}
+		if token != expected {
+			return nil, fmt.Errorf("pdf: objeto %d não encontrado na posição indicada", num)
+		}
+	}
+
+	value, err := l.object()
+	if err != nil {
+		return nil, err
+	}
+
+	if d, ok := value.(dict); ok {
+		token, err := l.token()
+		if err == nil && token == keyword("stream") {
+			// Após "stream" vem um fim de linha (\r\n ou \n) e os dados
+			b, err := l.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if b == '\r' {
+				if next, err := l.readByte(); err == nil && next != '\n' {
+					l.unreadByte()
+				}
+			} else if b != '\n' {
+				l.unreadByte()
+			}
+			return stream{dict: d, offset: l.pos}, nil
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,329 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildPDF monta um PDF com os objetos informados (numerados a partir de 1),
+// a tabela xref com as posições corretas e o trailer
+func buildPDF(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n%s\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+	return buf.Bytes()
+}
+
+// sampleBook é um PDF de duas páginas com Info e sumário
+func sampleBook() []byte {
+	return buildPDF("<< /Size 8 /Root 1 0 R /Info 7 0 R >>",
+		"<< /Type /Catalog /Pages 2 0 R /Outlines 5 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"<< /Type /Outlines /First 6 0 R /Last 6 0 R /Count 1 >>",
+		"<< /Title (Capítulo 1) /Parent 5 0 R /Dest [4 0 R /Fit] >>",
+		"<< /Title (Livro de Teste) /Author (Ana; Bruno) >>",
+	)
+}
+
+// imagePDF é um PDF cuja primeira página usa o XObject de imagem informado
+func imagePDF(image string) []byte {
+	return buildPDF("<< /Size 5 /Root 1 0 R >>",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im1 4 0 R >> >> >>",
+		image,
+	)
+}
+
+// objStmPDF é um PDF 1.5 com o catálogo (objeto 4) dentro de um object
+// stream com o /N informado, referenciado por um xref stream
+func objStmPDF(count string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	objects := "4 0 << /Type /Catalog /Pages 2 0 R >>"
+	offsets := []int{buf.Len()}
+	fmt.Fprintf(&buf, "1 0 obj\n<< /Type /ObjStm /N %s /First 4 /Length %d >>\nstream\n%s\nendstream\nendobj\n", count, len(objects), objects)
+	offsets = append(offsets, buf.Len())
+	buf.WriteString("2 0 obj\n<< /Type /Pages /Kids [] /Count 0 >>\nendobj\n")
+	offsets = append(offsets, buf.Len())
+
+	// Entradas com W [1 4 2]: livre, três objetos no arquivo e o catálogo
+	// no object stream 1
+	xref := []byte{0, 0, 0, 0, 0, 0xff, 0xff}
+	for _, offset := range offsets {
+		xref = append(xref, 1, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset), 0, 0)
+	}
+	xref = append(xref, 2, 0, 0, 0, 1, 0, 0)
+	fmt.Fprintf(&buf, "3 0 obj\n<< /Type /XRef /W [1 4 2] /Size 5 /Root 4 0 R /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(xref), xref)
+	fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF\n", offsets[2])
+	return buf.Bytes()
+}
+
+func rawImage(dict string, data []byte) string {
+	return fmt.Sprintf("<< /Type /XObject /Subtype /Image %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func TestRead(t *testing.T) {
+	data := sampleBook()
+	doc, err := Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if doc.Version != "1.7" {
+		t.Errorf("Version = %q, esperado 1.7", doc.Version)
+	}
+	if doc.PageCount != 2 {
+		t.Errorf("PageCount = %d, esperado 2", doc.PageCount)
+	}
+	if doc.Metadata.Title != "Livro de Teste" {
+		t.Errorf("Title = %q", doc.Metadata.Title)
+	}
+	if !reflect.DeepEqual(doc.Metadata.Authors, []string{"Ana", "Bruno"}) {
+		t.Errorf("Authors = %q", doc.Metadata.Authors)
+	}
+	if len(doc.Outline) != 1 || doc.Outline[0].Page != 2 {
+		t.Errorf("Outline = %+v, esperado um item na página 2", doc.Outline)
+	}
+}
+
+func TestReadObjectStream(t *testing.T) {
+	data := objStmPDF("1")
+	doc, err := Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if doc.Version != "1.7" || doc.PageCount != 0 {
+		t.Errorf("Version = %q, PageCount = %d", doc.Version, doc.PageCount)
+	}
+}
+
+func TestReadMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "vazio", data: "", wantErr: true},
+		{name: "sem cabeçalho", data: "isto não é um pdf", wantErr: true},
+		{name: "só o cabeçalho", data: "%PDF-1.4\n", wantErr: true},
+		{name: "startxref fora do arquivo", data: "%PDF-1.4\nstartxref\n999999\n%%EOF", wantErr: true},
+		{name: "xref inválida", data: "%PDF-1.4\nxref\n0 -5\ntrailer << /Root 1 0 R >>\nstartxref\n9\n%%EOF", wantErr: true},
+		{
+			name: "sem xref, reconstruída pela varredura",
+			data: "%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n2 0 obj << /Type /Pages /Kids [3 0 R] >> endobj\n3 0 obj << /Type /Page >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF",
+		},
+		{
+			name: "árvore de páginas circular",
+			data: string(buildPDF("<< /Root 1 0 R >>",
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [2 0 R 3 0 R] /Count 1 >>",
+				"<< /Type /Pages /Kids [2 0 R] >>",
+			)),
+		},
+		{
+			name: "sumário circular",
+			data: string(buildPDF("<< /Root 1 0 R >>",
+				"<< /Type /Catalog /Pages 2 0 R /Outlines 3 0 R >>",
+				"<< /Type /Pages /Kids [] /Count 1 >>",
+				"<< /First 4 0 R >>",
+				"<< /Title (a) /Next 4 0 R /First 4 0 R >>",
+			)),
+		},
+		{
+			name:    "referência para si mesmo",
+			data:    string(buildPDF("<< /Root 1 0 R >>", "1 0 R")),
+			wantErr: true,
+		},
+		{
+			name: "Count absurdo",
+			data: string(buildPDF("<< /Root 1 0 R >>",
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Count 99999999999999999999 >>",
+			)),
+		},
+		{
+			name: "arrays aninhados demais",
+			data: string(buildPDF("<< /Root 1 0 R >>",
+				"<< /Type /Catalog /Pages "+strings.Repeat("[", 10000)+" >>",
+			)),
+		},
+		{
+			name:    "object stream com /N absurdo",
+			data:    string(objStmPDF("268435456")),
+			wantErr: true,
+		},
+		{
+			name:    "object stream com /N negativo",
+			data:    string(objStmPDF("-1")),
+			wantErr: true,
+		},
+		{
+			name: "string sem fechamento",
+			data: string(buildPDF("<< /Root 1 0 R /Info 2 0 R >>",
+				"<< /Type /Catalog >>",
+				"<< /Title (sem fim",
+			)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr && err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	data := sampleBook()
+	for size := 0; size < len(data); size++ {
+		// Não deve entrar em pânico; a varredura recupera parte dos arquivos
+		Read(bytes.NewReader(data[:size]), int64(size))
+	}
+}
+
+func TestFirstPageImage(t *testing.T) {
+	gray := bytes.Repeat([]byte{0x80}, 100*100)
+
+	tests := []struct {
+		name    string
+		image   string
+		wantErr error
+	}{
+		{
+			name:  "cinza sem compressão",
+			image: rawImage("/Width 100 /Height 100 /ColorSpace /DeviceGray /BitsPerComponent 8", gray),
+		},
+		{
+			name:    "dimensões que estouram o produto",
+			image:   rawImage("/Width 4294967296 /Height 4294967296 /ColorSpace /DeviceGray /BitsPerComponent 8", gray),
+			wantErr: ErrNoImage,
+		},
+		{
+			name:    "largura negativa",
+			image:   rawImage("/Width -100 /Height 100 /ColorSpace /DeviceGray /BitsPerComponent 8", gray),
+			wantErr: ErrNoImage,
+		},
+		{
+			name:    "dados incompletos",
+			image:   rawImage("/Width 100 /Height 100 /ColorSpace /DeviceRGB /BitsPerComponent 8", gray),
+			wantErr: ErrNoImage,
+		},
+		{
+			name:    "pequena demais para capa",
+			image:   rawImage("/Width 10 /Height 10 /ColorSpace /DeviceGray /BitsPerComponent 8", gray[:100]),
+			wantErr: ErrNoImage,
+		},
+		{
+			name:    "bits por componente inválido",
+			image:   rawImage("/Width 100 /Height 100 /ColorSpace /DeviceGray /BitsPerComponent 7", gray),
+			wantErr: ErrNoImage,
+		},
+		{
+			name:    "paleta sem tabela",
+			image:   rawImage("/Width 100 /Height 100 /ColorSpace [/Indexed /DeviceRGB 255 ()] /BitsPerComponent 8", gray),
+			wantErr: nil,
+		},
+		{
+			name:    "JPEG inválido",
+			image:   rawImage("/Width 100 /Height 100 /Filter /DCTDecode", []byte("\xff\xd8\xff\xc0\x00\x11\x08\xff\xff\xff\xff")),
+			wantErr: ErrNoImage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := imagePDF(tt.image)
+			img, err := FirstPageImage(bytes.NewReader(data), int64(len(data)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if err == nil && img.Bounds().Dx() != 100 {
+				t.Errorf("largura = %d, esperado 100", img.Bounds().Dx())
+			}
+		})
+	}
+}
+
+func TestUnpredict(t *testing.T) {
+	params := func(columns, colors, bits int64) dict {
+		return dict{
+			name("Predictor"):        int64(12),
+			name("Columns"):          columns,
+			name("Colors"):           colors,
+			name("BitsPerComponent"): bits,
+		}
+	}
+	// Duas linhas de dois bytes: a primeira sem filtro, a segunda com Up
+	rows := []byte{0, 1, 2, 2, 10, 20}
+
+	tests := []struct {
+		name    string
+		param   dict
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{name: "filtro Up", param: params(2, 1, 8), data: rows, want: []byte{1, 2, 11, 22}},
+		{name: "sem preditor", param: dict{}, data: rows, want: rows},
+		{name: "preditor TIFF", param: dict{name("Predictor"): int64(2)}, data: rows, wantErr: true},
+		{name: "colunas que estouram o produto", param: params(1<<60, 1, 8), data: rows, wantErr: true},
+		{name: "colunas maiores que os dados", param: params(1<<20, 1, 8), data: rows, wantErr: true},
+		{name: "colunas negativas", param: params(-1, 1, 8), data: rows, wantErr: true},
+		{name: "cores zero", param: params(2, 0, 8), data: rows, wantErr: true},
+		{name: "cores demais", param: params(2, 1<<40, 8), data: rows, wantErr: true},
+		{name: "bits inválidos", param: params(2, 1, 3), data: rows, wantErr: true},
+		{name: "linha maior que os dados", param: params(6, 1, 8), data: rows, wantErr: true},
+		{name: "dados vazios", param: params(1, 1, 8), data: nil, wantErr: true},
+		{name: "filtro inválido", param: params(2, 1, 8), data: []byte{9, 1, 2}, wantErr: true},
+	}
+
+	rd := &reader{objects: make(map[int]object)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rd.unpredict(tt.data, tt.param)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("esperado erro, obtido %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unpredict: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("unpredict = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzRead(f *testing.F) {
+	f.Add(sampleBook())
+	f.Add(imagePDF(rawImage("/Width 100 /Height 100 /ColorSpace /DeviceGray /BitsPerComponent 8", bytes.Repeat([]byte{1}, 10000))))
+	f.Add([]byte("%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 1 0 R >> endobj\ntrailer << /Root 1 0 R >>"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Só não pode entrar em pânico
+		Read(bytes.NewReader(data), int64(len(data)))
+		FirstPageImage(bytes.NewReader(data), int64(len(data)))
+	})
+}
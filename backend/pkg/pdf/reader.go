@@ -0,0 +1,657 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// maxStreamSize limita o tamanho decodificado de um stream (xref, object stream, XMP)
+	maxStreamSize = 64 << 20
+
+	// maxResolves limita quantos objetos são lidos em um documento
+	maxResolves = 500000
+
+	// maxScanSize limita a reconstrução da tabela xref por varredura do arquivo
+	maxScanSize = 512 << 20
+
+	// maxPredictorColors limita os componentes por amostra dos preditores PNG
+	maxPredictorColors = 32
+)
+
+// ErrEncrypted indica um PDF cifrado, cujas strings não podem ser lidas
+var ErrEncrypted = errors.New("pdf cifrado")
+
+// xrefEntry é a posição de um objeto: no arquivo ou dentro de um object stream
+type xrefEntry struct {
+	offset int64
+	objStm int // Número do object stream (0 = objeto direto)
+	index  int
+}
+
+// reader resolve objetos de um PDF a partir da tabela xref
+type reader struct {
+	r       io.ReaderAt
+	size    int64
+	xref    map[int]xrefEntry
+	trailer dict
+
+	objects  map[int]object
+	objStms  map[int]*objStm
+	resolves int
+	scanned  bool
+}
+
+// objStm é um object stream decodificado (PDF 1.5+)
+type objStm struct {
+	data    []byte
+	first   int64
+	offsets map[int]int64
+}
+
+// newReader lê a tabela xref (com as atualizações incrementais em /Prev) e o trailer.
+// Se a tabela estiver corrompida, reconstrói a posição dos objetos varrendo o arquivo.
+func newReader(r io.ReaderAt, size int64) (*reader, error) {
+	rd := &reader{
+		r:       r,
+		size:    size,
+		xref:    make(map[int]xrefEntry),
+		objects: make(map[int]object),
+		objStms: make(map[int]*objStm),
+	}
+
+	offset, err := rd.startXref()
+	if err == nil {
+		err = rd.readXrefChain(offset)
+	}
+	if err != nil || rd.trailer[name("Root")] == nil {
+		if scanErr := rd.scan(); scanErr != nil {
+			if err == nil {
+				err = scanErr
+			}
+			return nil, err
+		}
+	}
+	return rd, nil
+}
+
+// startXref lê a posição da última tabela xref no fim do arquivo
+func (rd *reader) startXref() (int64, error) {
+	tailSize := min(rd.size, 2048)
+	tail := make([]byte, tailSize)
+	if _, err := rd.r.ReadAt(tail, rd.size-tailSize); err != nil && err != io.EOF {
+		return 0, err
+	}
+	index := bytes.LastIndex(tail, []byte("startxref"))
+	if index < 0 {
+		return 0, errors.New("pdf: startxref não encontrado")
+	}
+	fields := bytes.Fields(tail[index+len("startxref"):])
+	if len(fields) == 0 {
+		return 0, errSyntax
+	}
+	return strconv.ParseInt(string(fields[0]), 10, 64)
+}
+
+// readXrefChain lê as seções xref da mais recente para a mais antiga. Entradas
+// já conhecidas não são sobrescritas, pois as mais recentes prevalecem.
+func (rd *reader) readXrefChain(offset int64) error {
+	visited := make(map[int64]bool)
+	for offset > 0 {
+		if visited[offset] || offset >= rd.size {
+			return errors.New("pdf: cadeia xref inválida")
+		}
+		visited[offset] = true
+
+		trailer, err := rd.readXref(offset)
+		if err != nil {
+			return err
+		}
+
+		if rd.trailer == nil {
+			rd.trailer = trailer
+		} else {
+			for key, value := range trailer {
+				if _, ok := rd.trailer[key]; !ok {
+					rd.trailer[key] = value
+				}
+			}
+		}
+
+		// Arquivos híbridos: os objetos comprimidos ficam em um xref stream à parte
+		if xrefStm, ok := trailer[name("XRefStm")].(int64); ok && !visited[xrefStm] {
+			visited[xrefStm] = true
+			if _, err := rd.readXref(xrefStm); err != nil {
+				return err
+			}
+		}
+
+		prev, _ := trailer[name("Prev")].(int64)
+		offset = prev
+	}
+	return nil
+}
+
+// readXref lê uma tabela xref clássica ou um xref stream e retorna o trailer
+func (rd *reader) readXref(offset int64) (dict, error) {
+	l := newLexer(io.NewSectionReader(rd.r, offset, rd.size-offset), offset)
+	token, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token != keyword("xref") {
+		l.unread(token)
+		return rd.readXrefStream(l)
+	}
+
+	for {
+		token, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		if token == keyword("trailer") {
+			break
+		}
+		start, ok := token.(int64)
+		if !ok {
+			return nil, errSyntax
+		}
+		countToken, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		count, ok := countToken.(int64)
+		if !ok || count < 0 || count > 10000000 {
+			return nil, errSyntax
+		}
+
+		for i := int64(0); i < count; i++ {
+			var fields [3]interface{}
+			for j := range fields {
+				if fields[j], err = l.token(); err != nil {
+					return nil, err
+				}
+			}
+			entryOffset, ok := fields[0].(int64)
+			if !ok {
+				return nil, errSyntax
+			}
+			num := int(start + i)
+			if _, known := rd.xref[num]; !known && fields[2] == keyword("n") && entryOffset > 0 {
+				rd.xref[num] = xrefEntry{offset: entryOffset}
+			}
+		}
+	}
+
+	trailer, err := l.object()
+	if err != nil {
+		return nil, err
+	}
+	d, ok := trailer.(dict)
+	if !ok {
+		return nil, errSyntax
+	}
+	return d, nil
+}
+
+// readXrefStream lê um xref stream (PDF 1.5+); o dicionário do stream é o trailer
+func (rd *reader) readXrefStream(l *lexer) (dict, error) {
+	token, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+	num, ok := token.(int64)
+	if !ok {
+		return nil, errSyntax
+	}
+	l.unread(token)
+
+	value, err := l.indirectObject(int(num))
+	if err != nil {
+		return nil, err
+	}
+	s, ok := value.(stream)
+	if !ok || s.dict[name("Type")] != name("XRef") {
+		return nil, errors.New("pdf: xref stream inválido")
+	}
+
+	data, err := rd.streamData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var widths [3]int
+	w, _ := s.dict[name("W")].(array)
+	if len(w) != 3 {
+		return nil, errSyntax
+	}
+	entrySize := 0
+	for i := range widths {
+		width, ok := w[i].(int64)
+		if !ok || width < 0 || width > 8 {
+			return nil, errSyntax
+		}
+		widths[i] = int(width)
+		entrySize += int(width)
+	}
+	if entrySize == 0 {
+		return nil, errSyntax
+	}
+
+	index, _ := s.dict[name("Index")].(array)
+	if index == nil {
+		size, _ := s.dict[name("Size")].(int64)
+		index = array{int64(0), size}
+	}
+
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := index[i].(int64)
+		count, _ := index[i+1].(int64)
+		for j := int64(0); j < count; j++ {
+			if len(data) < entrySize {
+				return s.dict, nil
+			}
+			entry := data[:entrySize]
+			data = data[entrySize:]
+
+			kind := int64(1)
+			if widths[0] > 0 {
+				kind = readUint(entry[:widths[0]])
+			}
+			field2 := readUint(entry[widths[0] : widths[0]+widths[1]])
+			field3 := readUint(entry[widths[0]+widths[1]:])
+
+			num := int(start + j)
+			if _, known := rd.xref[num]; known {
+				continue
+			}
+			switch kind {
+			case 1:
+				rd.xref[num] = xrefEntry{offset: field2}
+			case 2:
+				rd.xref[num] = xrefEntry{objStm: int(field2), index: int(field3)}
+			}
+		}
+	}
+	return s.dict, nil
+}
+
+// objectHeader encontra "num gen obj" no início de uma linha
+var objectHeader = regexp.MustCompile(`(?m)(?:^|[\r\n])[ \t]*(\d+)[ \t\r\n]+\d+[ \t\r\n]+obj\b`)
+
+// scan reconstrói a xref varrendo o arquivo em busca de "num gen obj" e "trailer".
+// Objetos dentro de object streams não são recuperados.
+func (rd *reader) scan() error {
+	if rd.scanned || rd.size > maxScanSize {
+		return errors.New("pdf: tabela xref corrompida")
+	}
+	rd.scanned = true
+
+	const chunkSize = 1 << 20
+	const overlap = 64
+	buf := make([]byte, chunkSize+overlap)
+	offsets := make(map[int]int64)
+	var trailerOffset int64 = -1
+
+	for base := int64(0); base < rd.size; base += chunkSize {
+		n, err := rd.r.ReadAt(buf[:min(int64(len(buf)), rd.size-base)], base)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		chunk := buf[:n]
+
+		for _, match := range objectHeader.FindAllSubmatchIndex(chunk, -1) {
+			// Evita contar duas vezes o que aparece na sobreposição entre blocos
+			if int64(match[2]) >= chunkSize && base+chunkSize < rd.size {
+				continue
+			}
+			num, err := strconv.Atoi(string(chunk[match[2]:match[3]]))
+			if err == nil {
+				offsets[num] = base + int64(match[2])
+			}
+		}
+		for index := 0; ; {
+			found := bytes.Index(chunk[index:], []byte("trailer"))
+			if found < 0 {
+				break
+			}
+			trailerOffset = base + int64(index+found)
+			index += found + 1
+		}
+	}
+
+	if len(offsets) == 0 {
+		return errors.New("pdf: nenhum objeto encontrado")
+	}
+	rd.xref = make(map[int]xrefEntry, len(offsets))
+	for num, offset := range offsets {
+		rd.xref[num] = xrefEntry{offset: offset}
+	}
+	rd.objects = make(map[int]object)
+	rd.objStms = make(map[int]*objStm)
+
+	rd.trailer = dict{}
+	if trailerOffset >= 0 {
+		l := newLexer(io.NewSectionReader(rd.r, trailerOffset, rd.size-trailerOffset), trailerOffset)
+		if token, err := l.token(); err == nil && token == keyword("trailer") {
+			if trailer, err := l.object(); err == nil {
+				if d, ok := trailer.(dict); ok {
+					rd.trailer = d
+				}
+			}
+		}
+	}
+
+	// Sem trailer, procura o catálogo entre os objetos
+	if rd.trailer[name("Root")] == nil {
+		for num := range offsets {
+			if d, ok := rd.resolve(ref{num: num}).(dict); ok && d[name("Type")] == name("Catalog") {
+				rd.trailer[name("Root")] = ref{num: num}
+				break
+			}
+		}
+	}
+	if rd.trailer[name("Root")] == nil {
+		return errors.New("pdf: catálogo não encontrado")
+	}
+	return nil
+}
+
+// resolve retorna o objeto apontado por uma referência (ou o próprio valor)
+func (rd *reader) resolve(value object) object {
+	for depth := 0; depth < 32; depth++ {
+		r, ok := value.(ref)
+		if !ok {
+			return value
+		}
+		value = rd.object(r.num)
+	}
+	return nil
+}
+
+// object lê um objeto indireto pelo número
+func (rd *reader) object(num int) object {
+	if value, ok := rd.objects[num]; ok {
+		return value
+	}
+	if rd.resolves >= maxResolves {
+		return nil
+	}
+	rd.resolves++
+
+	// Marca antes de ler para interromper referências circulares
+	rd.objects[num] = nil
+
+	value, err := rd.readObject(num)
+	if err != nil && !rd.scanned && rd.size <= maxScanSize {
+		// Posição errada na xref: reconstrói a tabela e tenta de novo
+		if rd.scan() == nil {
+			rd.objects[num] = nil
+			value, err = rd.readObject(num)
+		}
+	}
+	if err != nil {
+		return nil
+	}
+	rd.objects[num] = value
+	return value
+}
+
+func (rd *reader) readObject(num int) (object, error) {
+	entry, ok := rd.xref[num]
+	if !ok {
+		return nil, nil
+	}
+
+	if entry.objStm > 0 {
+		stm, err := rd.objectStream(entry.objStm)
+		if err != nil {
+			return nil, err
+		}
+		offset, ok := stm.offsets[num]
+		if !ok {
+			return nil, fmt.Errorf("pdf: objeto %d fora do object stream", num)
+		}
+		start := stm.first + offset
+		if start < 0 || start >= int64(len(stm.data)) {
+			return nil, errSyntax
+		}
+		return newLexer(bytes.NewReader(stm.data[start:]), 0).object()
+	}
+
+	if entry.offset <= 0 || entry.offset >= rd.size {
+		return nil, errSyntax
+	}
+	l := newLexer(io.NewSectionReader(rd.r, entry.offset, rd.size-entry.offset), entry.offset)
+	return l.indirectObject(num)
+}
+
+// objectStream decodifica um object stream e o guarda em cache
+func (rd *reader) objectStream(num int) (*objStm, error) {
+	if stm, ok := rd.objStms[num]; ok {
+		if stm == nil {
+			return nil, errSyntax
+		}
+		return stm, nil
+	}
+	rd.objStms[num] = nil
+
+	s, ok := rd.resolve(ref{num: num}).(stream)
+	if !ok {
+		return nil, errSyntax
+	}
+	data, err := rd.streamData(s)
+	if err != nil {
+		return nil, err
+	}
+	count, _ := rd.resolve(s.dict[name("N")]).(int64)
+	first, _ := rd.resolve(s.dict[name("First")]).(int64)
+	// Cada par "número offset" ocupa ao menos 4 bytes; /N maior que isso é
+	// forjado e não pode dimensionar a alocação
+	if count < 0 || count > int64(len(data))/4 {
+		return nil, errors.New("pdf: número de objetos do object stream inválido")
+	}
+
+	stm := &objStm{data: data, first: first, offsets: make(map[int]int64, count)}
+	l := newLexer(bytes.NewReader(data), 0)
+	for i := int64(0); i < count; i++ {
+		objNum, err1 := l.token()
+		offset, err2 := l.token()
+		n, ok1 := objNum.(int64)
+		o, ok2 := offset.(int64)
+		if err1 != nil || err2 != nil || !ok1 || !ok2 {
+			break
+		}
+		stm.offsets[int(n)] = o
+	}
+
+	rd.objStms[num] = stm
+	return stm, nil
+}
+
+// streamData lê e decodifica os dados de um stream (sem filtro ou FlateDecode)
+func (rd *reader) streamData(s stream) ([]byte, error) {
+	data, final, err := rd.decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+	if final != "" {
+		return nil, fmt.Errorf("pdf: filtro não suportado: %s", final)
+	}
+	return data, nil
+}
+
+// decodeStream aplica os filtros do stream. Um filtro de imagem no fim da
+// cadeia (DCTDecode, JPXDecode) não é aplicado: os dados voltam codificados,
+// com o nome do filtro em final.
+func (rd *reader) decodeStream(s stream) (data []byte, final name, err error) {
+	length, ok := rd.resolve(s.dict[name("Length")]).(int64)
+	if !ok || length < 0 || s.offset+length > rd.size || length > maxStreamSize {
+		return nil, "", errors.New("pdf: tamanho de stream inválido")
+	}
+	data = make([]byte, length)
+	if _, err := rd.r.ReadAt(data, s.offset); err != nil && err != io.EOF {
+		return nil, "", err
+	}
+
+	filters := rd.resolve(s.dict[name("Filter")])
+	params := rd.resolve(s.dict[name("DecodeParms")])
+	if f, ok := filters.(name); ok {
+		filters = array{f}
+		params = array{params}
+	}
+	filterList, _ := filters.(array)
+	paramList, _ := params.(array)
+
+	for i, filter := range filterList {
+		var param dict
+		if i < len(paramList) {
+			param, _ = rd.resolve(paramList[i]).(dict)
+		}
+
+		switch filter := rd.resolve(filter); filter {
+		case name("FlateDecode"), name("Fl"):
+			decoded, err := inflate(data)
+			if err != nil {
+				return nil, "", err
+			}
+			if data, err = rd.unpredict(decoded, param); err != nil {
+				return nil, "", err
+			}
+		case name("DCTDecode"), name("DCT"), name("JPXDecode"):
+			if i != len(filterList)-1 {
+				return nil, "", fmt.Errorf("pdf: filtro não suportado: %v", filter)
+			}
+			return data, filter.(name), nil
+		default:
+			return nil, "", fmt.Errorf("pdf: filtro não suportado: %v", filter)
+		}
+	}
+	return data, "", nil
+}
+
+// inflate descompacta dados zlib, limitado a maxStreamSize
+func inflate(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(zr, maxStreamSize+1))
+	// Streams truncados são comuns; aproveita o que foi descompactado
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	if len(decoded) > maxStreamSize {
+		return nil, errors.New("pdf: stream grande demais")
+	}
+	return decoded, nil
+}
+
+// unpredict desfaz os preditores PNG (usados nos xref streams)
+func (rd *reader) unpredict(data []byte, param dict) ([]byte, error) {
+	predictor, _ := rd.resolve(param[name("Predictor")]).(int64)
+	if predictor < 10 {
+		if predictor > 1 {
+			return nil, errors.New("pdf: preditor TIFF não suportado")
+		}
+		return data, nil
+	}
+
+	columns, ok := rd.resolve(param[name("Columns")]).(int64)
+	if !ok {
+		columns = 1
+	}
+	colors, ok := rd.resolve(param[name("Colors")]).(int64)
+	if !ok {
+		colors = 1
+	}
+	bits, ok := rd.resolve(param[name("BitsPerComponent")]).(int64)
+	if !ok {
+		bits = 8
+	}
+	if colors < 1 || colors > maxPredictorColors {
+		return nil, errors.New("pdf: número de cores do preditor inválido")
+	}
+	if bits != 1 && bits != 2 && bits != 4 && bits != 8 && bits != 16 {
+		return nil, errors.New("pdf: bits por componente do preditor inválido")
+	}
+	// Cada linha tem pelo menos um byte de filtro, então uma linha maior que
+	// os dados é inválida; a comparação por divisão evita estourar o produto
+	if columns < 1 || columns > int64(len(data))*8/(colors*bits) {
+		return nil, errors.New("pdf: colunas do preditor inválidas")
+	}
+	bpp := int(max(1, colors*bits/8))
+	rowSize := int((columns*colors*bits + 7) / 8)
+	if rowSize+1 > len(data) {
+		return nil, errors.New("pdf: dados do preditor incompletos")
+	}
+
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowSize)
+	for len(data) > rowSize {
+		filter, row := data[0], data[1:rowSize+1]
+		data = data[rowSize+1:]
+
+		current := make([]byte, rowSize)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = current[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 0:
+				current[i] = row[i]
+			case 1:
+				current[i] = row[i] + left
+			case 2:
+				current[i] = row[i] + up
+			case 3:
+				current[i] = row[i] + byte((int(left)+int(up))/2)
+			case 4:
+				current[i] = row[i] + paeth(left, up, upLeft)
+			default:
+				return nil, errors.New("pdf: preditor PNG inválido")
+			}
+		}
+		out = append(out, current...)
+		prev = current
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// readUint lê um inteiro big-endian de tamanho variável
+func readUint(b []byte) int64 {
+	var n int64
+	for _, c := range b {
+		n = n<<8 | int64(c)
+	}
+	return n
+}
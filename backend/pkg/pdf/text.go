@@ -0,0 +1,119 @@
+package pdf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// pdfDocEncoding mapeia os bytes 0x80-0x9F e 0xAD do PDFDocEncoding que
+// diferem do Latin-1 (os demais coincidem com o Unicode)
+var pdfDocEncoding = map[byte]rune{
+	0x80: '•', 0x81: '†', 0x82: '‡', 0x83: '…', 0x84: '—', 0x85: '–', 0x86: 'ƒ', 0x87: '⁄',
+	0x88: '‹', 0x89: '›', 0x8A: '−', 0x8B: '‰', 0x8C: '„', 0x8D: '“', 0x8E: '”', 0x8F: '‘',
+	0x90: '’', 0x91: '‚', 0x92: '™', 0x93: 'ﬁ', 0x94: 'ﬂ', 0x95: 'Ł', 0x96: 'Œ', 0x97: 'Š',
+	0x98: 'Ÿ', 0x99: 'Ž', 0x9A: 'ı', 0x9B: 'ł', 0x9C: 'œ', 0x9D: 'š', 0x9E: 'ž', 0xA0: '€',
+	0x18: '˘', 0x19: 'ˇ', 0x1A: 'ˆ', 0x1B: '˙', 0x1C: '˝', 0x1D: '˛', 0x1E: '˚', 0x1F: '˜',
+}
+
+// decodeText converte uma text string do PDF (UTF-16BE ou UTF-8 com BOM, ou
+// PDFDocEncoding) em UTF-8, sem espaços extras
+func decodeText(raw string) string {
+	var text string
+	switch {
+	case strings.HasPrefix(raw, "\xFE\xFF"):
+		text = decodeUTF16(raw[2:])
+	case strings.HasPrefix(raw, "\xEF\xBB\xBF"):
+		text = strings.ToValidUTF8(raw[3:], "")
+	default:
+		var b strings.Builder
+		for i := 0; i < len(raw); i++ {
+			if r, ok := pdfDocEncoding[raw[i]]; ok {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune(rune(raw[i]))
+			}
+		}
+		text = b.String()
+	}
+
+	text = strings.Map(func(r rune) rune {
+		if r < ' ' || r == utf8.RuneError {
+			return ' '
+		}
+		return r
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func decodeUTF16(raw string) string {
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+	}
+	// Remove o código de idioma embutido (U+001B xx xx U+001B)
+	runes := utf16.Decode(units)
+	var out []rune
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == 0x1B {
+			for i++; i < len(runes) && runes[i] != 0x1B; i++ {
+			}
+			continue
+		}
+		out = append(out, runes[i])
+	}
+	return string(out)
+}
+
+var datePattern = regexp.MustCompile(`^(?:D:)?(\d{4})(\d{2})?(\d{2})?(\d{2})?(\d{2})?(\d{2})?([Zz+\-])?(\d{2})?'?(\d{2})?'?`)
+
+// parseDate interpreta uma data do PDF (D:AAAAMMDDHHmmSSOHH'mm'); os campos
+// após o ano são opcionais e sem fuso a data é tratada como UTC
+func parseDate(raw string) *time.Time {
+	match := datePattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if match == nil {
+		return nil
+	}
+
+	field := func(index, fallback int) int {
+		if match[index] == "" {
+			return fallback
+		}
+		value, _ := strconv.Atoi(match[index])
+		return value
+	}
+
+	year := field(1, 0)
+	month, day := field(2, 1), field(3, 1)
+	hour, minute, second := field(4, 0), field(5, 0), field(6, 0)
+	if year < 1000 || month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 60 {
+		return nil
+	}
+
+	location := time.UTC
+	if sign := match[7]; sign == "+" || sign == "-" {
+		offset := field(8, 0)*3600 + field(9, 0)*60
+		if sign == "-" {
+			offset = -offset
+		}
+		location = time.FixedZone("", offset)
+	}
+
+	date := time.Date(year, time.Month(month), day, hour, minute, second, 0, location).UTC()
+	return &date
+}
+
+// parseXMPDate interpreta uma data ISO 8601 do XMP (AAAA, AAAA-MM-DD, AAAA-MM-DDThh:mm:ss±hh:mm...)
+func parseXMPDate(raw string) *time.Time {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04Z07:00", "2006-01-02T15:04", "2006-01-02", "2006-01", "2006"} {
+		if date, err := time.Parse(layout, raw); err == nil {
+			date = date.UTC()
+			return &date
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// xmpMetadata são os campos lidos do pacote XMP (Dublin Core, PDF e XMP basic)
+type xmpMetadata struct {
+	Title        string
+	Creators     []string
+	Description  string
+	Keywords     string
+	Subjects     []string
+	Publisher    string
+	Language     string
+	CreationDate *time.Time
+}
+
+// parseXMP lê o RDF do XMP. Cada propriedade pode vir como elemento simples,
+// como lista rdf:Alt/Seq/Bag ou como atributo de rdf:Description.
+func parseXMP(data []byte) *xmpMetadata {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	values := make(map[string][]string)
+	var stack []string
+	var text strings.Builder
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "Description" {
+				for _, attr := range t.Attr {
+					if property := xmpProperty(attr.Name); property != "" {
+						values[property] = append(values[property], attr.Value)
+					}
+				}
+			}
+			stack = append(stack, xmpProperty(t.Name))
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				break
+			}
+			stack = stack[:len(stack)-1]
+
+			// O valor pertence à propriedade mais próxima na pilha (o elemento
+			// em si ou o dc:title acima de rdf:Alt/rdf:li)
+			value := strings.TrimSpace(text.String())
+			text.Reset()
+			if value == "" {
+				break
+			}
+			property := xmpProperty(t.Name)
+			for i := len(stack) - 1; property == "" && i >= 0; i-- {
+				property = stack[i]
+			}
+			if property != "" {
+				values[property] = append(values[property], value)
+			}
+		}
+	}
+
+	first := func(key string) string {
+		if list := values[key]; len(list) > 0 {
+			return list[0]
+		}
+		return ""
+	}
+
+	meta := &xmpMetadata{
+		Title:       first("title"),
+		Creators:    values["creator"],
+		Description: first("description"),
+		Keywords:    first("Keywords"),
+		Subjects:    values["subject"],
+		Publisher:   first("publisher"),
+		Language:    first("language"),
+	}
+	for _, key := range []string{"CreateDate", "date"} {
+		if date := parseXMPDate(first(key)); date != nil {
+			meta.CreationDate = date
+			break
+		}
+	}
+	return meta
+}
+
+// xmpNamespaces são os namespaces cujas propriedades interessam
+var xmpNamespaces = map[string]bool{
+	"http://purl.org/dc/elements/1.1/": true,
+	"http://ns.adobe.com/pdf/1.3/":     true,
+	"http://ns.adobe.com/xap/1.0/":     true,
+}
+
+// xmpProperty retorna o nome local de uma propriedade conhecida ou vazio
+func xmpProperty(n xml.Name) string {
+	if xmpNamespaces[n.Space] {
+		return n.Local
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+// Package thumbnail decodifica imagens com limite de tamanho e gera miniaturas
+// JPEG reduzidas por média de área (sem dependências fora da biblioteca padrão).
+package thumbnail
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+
+	// Formatos aceitos em Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Quality é a qualidade JPEG das miniaturas
+const Quality = 85
+
+// ErrTooLarge indica uma imagem com mais pixels que o limite
+var ErrTooLarge = errors.New("imagem grande demais")
+
+// Decode decodifica uma imagem JPEG, PNG ou GIF, recusando pelo cabeçalho
+// imagens com mais de maxPixels pixels (que ocupariam memória demais)
+func Decode(r io.ReadSeeker, maxPixels int64) (image.Image, error) {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, ErrTooLarge
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+// Fit reduz a imagem para caber em width x height mantendo a proporção. A
+// transparência é composta sobre fundo branco. Imagens menores não são ampliadas.
+// Uma *image.RGBA opaca (como as retornadas por Fit) é lida sem cópia, então
+// várias miniaturas podem ser reduzidas em cadeia a partir da maior.
+func Fit(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := srcWidth, srcHeight
+	if dstWidth > width {
+		dstWidth, dstHeight = width, max(1, srcHeight*width/srcWidth)
+	}
+	if dstHeight > height {
+		dstWidth, dstHeight = max(1, srcWidth*height/srcHeight), height
+	}
+
+	flat, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) || !flat.Opaque() {
+		flat = flatten(src)
+	}
+	if dstWidth == srcWidth && dstHeight == srcHeight {
+		return flat
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*srcHeight/dstHeight, max((y+1)*srcHeight/dstHeight, y*srcHeight/dstHeight+1)
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*srcWidth/dstWidth, max((x+1)*srcWidth/dstWidth, x*srcWidth/dstWidth+1)
+
+			// Média dos pixels de origem cobertos pelo pixel de destino
+			var r, g, b, count int
+			for sy := y0; sy < y1; sy++ {
+				row := flat.Pix[sy*flat.Stride+x0*4 : sy*flat.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += int(row[i])
+					g += int(row[i+1])
+					b += int(row[i+2])
+					count++
+				}
+			}
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = 255
+		}
+	}
+	return dst
+}
+
+// flatten converte a imagem para RGBA sobre branco (draw tem caminhos rápidos
+// para YCbCr e paletas)
+func flatten(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+	return flat
+}
+
+// Encode grava a miniatura em JPEG
+func Encode(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: Quality})
+}
@@ -0,0 +1,69 @@
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		maxWidth, maxHeight   int
+		wantWidth, wantHeight int
+	}{
+		{name: "limitada pela largura", width: 1000, height: 500, maxWidth: 100, maxHeight: 150, wantWidth: 100, wantHeight: 50},
+		{name: "limitada pela altura", width: 500, height: 1000, maxWidth: 320, maxHeight: 480, wantWidth: 240, wantHeight: 480},
+		{name: "menor que o limite", width: 50, height: 60, maxWidth: 160, maxHeight: 240, wantWidth: 50, wantHeight: 60},
+		{name: "faixa fina", width: 10000, height: 1, maxWidth: 160, maxHeight: 240, wantWidth: 160, wantHeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewGray(image.Rect(0, 0, tt.width, tt.height))
+			got := Fit(src, tt.maxWidth, tt.maxHeight).Bounds()
+			if got.Dx() != tt.wantWidth || got.Dy() != tt.wantHeight {
+				t.Errorf("Fit = %dx%d, esperado %dx%d", got.Dx(), got.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestFitTransparency(t *testing.T) {
+	// Pixels transparentes viram branco; a média é feita depois da composição
+	src := image.NewNRGBA(image.Rect(10, 10, 14, 12))
+	for x := 10; x < 14; x += 2 {
+		src.Set(x, 10, color.NRGBA{A: 255})
+		src.Set(x, 11, color.NRGBA{A: 255})
+	}
+
+	dst := Fit(src, 2, 2)
+	want := color.RGBA{R: 127, G: 127, B: 127, A: 255}
+	for x := 0; x < 2; x++ {
+		if got := dst.RGBAAt(x, 0); got != want {
+			t.Errorf("pixel (%d, 0) = %v, esperado %v", x, got, want)
+		}
+	}
+}
+
+func TestFitChained(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1280, 1920))
+	large := Fit(src, 640, 960)
+	if !large.Opaque() {
+		t.Fatal("miniatura com transparência")
+	}
+
+	// Uma miniatura que já cabe é devolvida sem cópia
+	if same := Fit(large, 640, 960); same != large {
+		t.Error("RGBA opaca copiada")
+	}
+
+	small := Fit(large, 160, 240)
+	if got := small.Bounds(); got.Dx() != 160 || got.Dy() != 240 {
+		t.Errorf("Fit encadeado = %dx%d, esperado 160x240", got.Dx(), got.Dy())
+	}
+	if got := small.RGBAAt(80, 120); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("pixel = %v, esperado branco", got)
+	}
+}
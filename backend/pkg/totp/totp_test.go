@@ -0,0 +1,132 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret é o segredo ASCII "12345678901234567890" dos vetores da RFC 6238
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// Vetores SHA1 da RFC 6238 (apêndice B), com os 6 últimos dígitos
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, esperado %s", tt.unix, got, tt.want)
+		}
+	}
+
+	// Minúsculas e espaços nas pontas são aceitos
+	if got, err := Code(" "+strings.ToLower(rfcSecret)+"\n", 1); err != nil || got != "287082" {
+		t.Errorf("Code com segredo em minúsculas = %s, %v", got, err)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	for _, secret := range []string{"não é base32", "GEZDGNBV1", "GEZDGNBVGY3TQOJQ===="} {
+		if _, err := Code(secret, 1); err == nil {
+			t.Errorf("Code(%q) sem erro", secret)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	code := func(step int64) string {
+		value, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		skew     int
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "janela atual", secret: rfcSecret, code: code(current), skew: 1, wantStep: current, wantOK: true},
+		{name: "com espaços", secret: rfcSecret, code: " 050 471 ", skew: 0, wantStep: current, wantOK: true},
+		{name: "janela anterior", secret: rfcSecret, code: code(current - 1), skew: 1, wantStep: current - 1, wantOK: true},
+		{name: "janela seguinte", secret: rfcSecret, code: code(current + 1), skew: 1, wantStep: current + 1, wantOK: true},
+		{name: "fora da tolerância", secret: rfcSecret, code: code(current - 2), skew: 1},
+		{name: "sem tolerância", secret: rfcSecret, code: code(current + 1), skew: 0},
+		{name: "código errado", secret: rfcSecret, code: "000000", skew: 1},
+		{name: "código curto", secret: rfcSecret, code: "50471", skew: 1},
+		{name: "código longo", secret: rfcSecret, code: "0504710", skew: 1},
+		{name: "código vazio", secret: rfcSecret, code: "", skew: 1},
+		{name: "código não numérico", secret: rfcSecret, code: "abcdef", skew: 1},
+		{name: "segredo inválido", secret: "não é base32", code: code(current), skew: 1},
+		{name: "outro segredo", secret: "JBSWY3DPEHPK3PXP", code: code(current), skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate = %d, %v; esperado %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Error("dois segredos iguais")
+	}
+
+	key, err := encoding.DecodeString(first)
+	if err != nil || len(key) != secretSize {
+		t.Errorf("segredo %q: %d bytes, %v", first, len(key), err)
+	}
+	if _, err := Code(first, 1); err != nil {
+		t.Errorf("Code com segredo gerado: %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Cloud Reader", "ana@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Cloud Reader:ana@example.com" {
+		t.Errorf("URI = %s", uri)
+	}
+
+	query := uri.Query()
+	for key, want := range map[string]string{"secret": rfcSecret, "issuer": "Cloud Reader", "algorithm": "SHA1", "digits": "6", "period": "30"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, esperado %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+'use client'
+
+import { useEffect, useRef, useState } from 'react'
+import { useRouter, useSearchParams } from 'next/navigation'
+import Link from 'next/link'
+import { exchangeOIDCCode } from '@/lib/api'
+import { useAuth } from '@/hooks/useAuth'
+
+// Recebe o código de uso único do backend após o login no provedor OIDC
+export default function OIDCCallbackPage() {
+  const router = useRouter()
+  const searchParams = useSearchParams()
+  const { login: saveSession } = useAuth()
+  const [error, setError] = useState<string | null>(null)
+  // O código é de uso único: evita a segunda chamada do StrictMode
+  const exchanged = useRef(false)
+
+  useEffect(() => {
+    if (exchanged.current) return
+    exchanged.current = true
+
+    const code = searchParams.get('code')
+    if (!code) {
+      setError('Código de login ausente')
+      return
+    }
+
+    exchangeOIDCCode(code)
+      .then((response) => {
+        if (response.two_factor_required) {
+          setError('Esta conta usa autenticação em dois fatores. Entre com email e senha.')
+          return
+        }
+
+        saveSession({
+          user: response.user,
+          token: response.token,
+          refreshToken: response.refresh_token,
+        })
+
+        const redirect = searchParams.get('redirect') || '/'
+        setTimeout(() => {
+          router.replace(redirect)
+        }, 100)
+      })
+      .catch((err) => {
+        setError(err instanceof Error ? err.message : 'Erro ao fazer login')
+      })
+  }, [searchParams, saveSession, router])
+
+  return (
+    <div className="min-h-screen flex items-center justify-center p-4">
+      <div className="card shadow-2xl border-0 px-6 sm:px-8 py-8 w-full max-w-md text-center">
+        {error ? (
+          <>
+            <p className="text-sm text-red-600 mb-6">{error}</p>
+            <Link href="/login" className="text-blue-600 hover:text-blue-700 font-semibold hover:underline">
+              Voltar para o login
+            </Link>
+          </>
+        ) : (
+          <div className="flex items-center justify-center gap-2 text-gray-600">
+            <div className="w-5 h-5 border-2 border-blue-600 border-t-transparent rounded-full animate-spin"></div>
+            Entrando...
+          </div>
+        )}
+      </div>
+    </div>
+  )
+}
@@ -3,7 +3,7 @@
 import { useState, useEffect } from 'react'
 import { useRouter, useSearchParams } from 'next/navigation'
 import Link from 'next/link'
-import { loginUser, type LoginRequest } from '@/lib/api'
+import { loginUser, getOIDCConfig, getOIDCLoginUrl, type LoginRequest, type OIDCConfig } from '@/lib/api'
 import { useAuth } from '@/hooks/useAuth'
 
 export default function LoginPage() {
@@ -18,6 +18,20 @@ export default function LoginPage() {
     general?: string
   }>({})
   const [isLoading, setIsLoading] = useState(false)
+  const [oidcConfig, setOidcConfig] = useState<OIDCConfig | null>(null)
+
+  // Verifica se o login via SSO está disponível
+  useEffect(() => {
+    getOIDCConfig().then(setOidcConfig)
+  }, [])
+
+  // Exibe o erro retornado pelo fluxo OIDC
+  useEffect(() => {
+    const oidcError = searchParams.get('oidc_error')
+    if (oidcError) {
+      setErrors({ general: oidcError })
+    }
+  }, [searchParams])
 
   // Se já estiver autenticado, redireciona para home ou página de destino
   // Lê searchParams.get('redirect') dentro do effect para evitar re-renders infinitos
@@ -65,6 +79,7 @@ export default function LoginPage() {
         saveSession({
           user: response.user,
           token: response.token,
+          refreshToken: response.refresh_token,
         })
         
         // Reseta o estado de loading antes do redirect
@@ -219,6 +234,16 @@ export default function LoginPage() {
               <div className="flex-1 border-t border-gray-200"></div>
             </div>
 
+            {/* SSO login */}
+            {oidcConfig?.enabled && (
+              <a
+                href={getOIDCLoginUrl(searchParams.get('redirect') || undefined)}
+                className="w-full btn btn-secondary py-3 mb-6 text-base font-semibold flex items-center justify-center"
+              >
+                Entrar com {oidcConfig.provider_name}
+              </a>
+            )}
+
             {/* Register link */}
             <div className="text-center">
               <p className="text-sm text-gray-600">
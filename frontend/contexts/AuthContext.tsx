@@ -7,6 +7,7 @@ import {
   removeSessionCookie,
   type SessionData,
 } from '@/lib/auth/cookies'
+import { logoutUser } from '@/lib/api'
 
 interface AuthContextType {
   user: SessionData['user'] | null
@@ -41,6 +42,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   }
 
   const logout = () => {
+    void logoutUser()
     removeSessionCookie()
     setUser(null)
     setToken(null)
@@ -1,5 +1,7 @@
 // Utilitário para chamadas à API do backend
 
+import { getSessionCookie, setSessionCookie } from './auth/cookies'
+
 // Usa variável de ambiente, com fallback apenas para desenvolvimento local
 const API_URL = process.env.NEXT_PUBLIC_API_URL || 
   (typeof window !== 'undefined' && window.location.hostname === 'localhost' 
@@ -34,6 +36,14 @@ export interface LoginResponse {
     email: string
   }
   token?: string
+  token_type?: string
+  expires_at?: string
+  refresh_token?: string
+  refresh_expires_at?: string
+  session_id?: string
+  two_factor_required?: boolean
+  challenge_token?: string
+  challenge_expires_at?: string
 }
 
 export interface ApiError {
@@ -41,6 +51,93 @@ export interface ApiError {
   details?: string
 }
 
+// Cabeçalhos de autenticação das rotas protegidas
+export function getAuthHeaders(userId?: number): Record<string, string> {
+  const headers: Record<string, string> = {}
+  const token = getSessionCookie()?.token
+  if (token) {
+    headers['Authorization'] = `Bearer ${token}`
+  } else if (userId) {
+    // Fallback aceito apenas com AUTH_ALLOW_DEV_USER_HEADER no backend
+    headers['X-User-ID'] = userId.toString()
+  }
+  return headers
+}
+
+// Renova o token de acesso usando o refresh token salvo na sessão
+let refreshInFlight: Promise<boolean> | null = null
+
+async function refreshSession(): Promise<boolean> {
+  const session = getSessionCookie()
+  if (!session?.refreshToken) return false
+
+  // Evita renovações concorrentes (o backend revoga a sessão em caso de reuso)
+  if (!refreshInFlight) {
+    refreshInFlight = (async () => {
+      try {
+        const response = await fetch(`${API_URL}/api/v1/auth/refresh`, {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ refresh_token: session.refreshToken }),
+        })
+        if (!response.ok) return false
+
+        const data = (await response.json()) as LoginResponse
+        setSessionCookie({
+          user: data.user,
+          token: data.token,
+          refreshToken: data.refresh_token,
+        })
+        return true
+      } catch {
+        return false
+      } finally {
+        refreshInFlight = null
+      }
+    })()
+  }
+
+  return refreshInFlight
+}
+
+// fetch autenticado: renova o token e repete a requisição uma vez em caso de 401
+export async function authFetch(
+  url: string,
+  init: RequestInit = {},
+  userId?: number
+): Promise<Response> {
+  const doFetch = () =>
+    fetch(url, {
+      ...init,
+      headers: {
+        ...(init.headers as Record<string, string> | undefined),
+        ...getAuthHeaders(userId),
+      },
+    })
+
+  const response = await doFetch()
+  if (response.status === 401 && (await refreshSession())) {
+    return doFetch()
+  }
+  return response
+}
+
+// Logout: revoga a sessão no backend (melhor esforço)
+export async function logoutUser(): Promise<void> {
+  const refreshToken = getSessionCookie()?.refreshToken
+  if (!refreshToken) return
+
+  try {
+    await fetch(`${API_URL}/api/v1/auth/logout`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refresh_token: refreshToken }),
+    })
+  } catch {
+    // Ignora falhas de rede: a sessão local é removida de qualquer forma
+  }
+}
+
 // Função auxiliar para fazer requisições
 async function fetchApi<T>(
   endpoint: string,
@@ -92,6 +189,36 @@ export async function loginUser(data: LoginRequest): Promise<LoginResponse> {
 }
 
 // Interfaces para livros
+export interface OIDCConfig {
+  enabled: boolean
+  provider_name: string
+}
+
+// Retorna a configuração do login via OpenID Connect (null se desabilitado)
+export async function getOIDCConfig(): Promise<OIDCConfig | null> {
+  try {
+    const response = await fetch(`${API_URL}/api/v1/auth/oidc`)
+    if (!response.ok) return null
+    return await response.json()
+  } catch {
+    return null
+  }
+}
+
+// URL que inicia o login no provedor OpenID Connect
+export function getOIDCLoginUrl(redirect?: string): string {
+  const query = redirect ? `?redirect=${encodeURIComponent(redirect)}` : ''
+  return `${API_URL}/api/v1/auth/oidc/login${query}`
+}
+
+// Troca o código recebido no callback OIDC pelos tokens de acesso
+export async function exchangeOIDCCode(code: string): Promise<LoginResponse> {
+  return fetchApi<LoginResponse>('/api/v1/auth/oidc/exchange', {
+    method: 'POST',
+    body: JSON.stringify({ code }),
+  })
+}
+
 export interface BookResponse {
   id: number
   user_id: number
@@ -126,13 +253,14 @@ async function uploadFile(
     const formData = new FormData()
     formData.append('file', file)
 
-    const response = await fetch(`${API_URL}${endpoint}`, {
-      method: 'POST',
-      headers: {
-        'X-User-ID': userId.toString(),
+    const response = await authFetch(
+      `${API_URL}${endpoint}`,
+      {
+        method: 'POST',
+        body: formData,
       },
-      body: formData,
-    })
+      userId
+    )
 
     const data = await response.json()
 
@@ -157,14 +285,17 @@ async function fetchApiWithUser<T>(
   options?: RequestInit
 ): Promise<T> {
   try {
-    const response = await fetch(`${API_URL}${endpoint}`, {
-      headers: {
-        'Content-Type': 'application/json',
-        'X-User-ID': userId.toString(),
-        ...options?.headers,
+    const response = await authFetch(
+      `${API_URL}${endpoint}`,
+      {
+        ...options,
+        headers: {
+          'Content-Type': 'application/json',
+          ...(options?.headers as Record<string, string> | undefined),
+        },
       },
-      ...options,
-    })
+      userId
+    )
 
     const data = await response.json()
 
@@ -220,14 +351,10 @@ export async function downloadBook(
   userId: number
 ): Promise<Blob> {
   try {
-    const response = await fetch(
+    const response = await authFetch(
       `${API_URL}/api/v1/books/${id}/download`,
-      {
-        method: 'GET',
-        headers: {
-          'X-User-ID': userId.toString(),
-        },
-      }
+      { method: 'GET' },
+      userId
     )
 
     if (!response.ok) {
@@ -7,6 +7,7 @@ export interface SessionData {
     email: string
   }
   token?: string
+  refreshToken?: string
 }
 
 const SESSION_COOKIE_NAME = 'cloud-reader-session'
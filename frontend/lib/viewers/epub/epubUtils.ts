@@ -3,6 +3,7 @@
  */
 
 import { loadScript } from '../utils'
+import { authFetch } from '@/lib/api'
 
 export interface Chapter {
   id: string
@@ -122,9 +123,7 @@ export function createBookOptions(fileUrl: string, userId?: number): any {
         }
       }
       
-      return fetch(finalUrl, {
-        headers: { 'X-User-ID': userId.toString() },
-      }).then(response => {
+      return authFetch(finalUrl, {}, userId).then(response => {
         if (!response.ok) {
           throw new Error(`HTTP error! status: ${response.status}`)
         }
@@ -3,6 +3,7 @@
  */
 
 import { loadScript } from '../utils'
+import { authFetch } from '@/lib/api'
 
 export interface OutlineItem {
   title: string
@@ -66,11 +67,7 @@ export async function loadPDFFile(
   let url = fileUrl
   
   if (userId) {
-    const response = await fetch(fileUrl, {
-      headers: {
-        'X-User-ID': userId.toString(),
-      },
-    })
+    const response = await authFetch(fileUrl, {}, userId)
     
     if (!response.ok) {
       throw new Error('Erro ao carregar PDF')
@@ -4,7 +4,7 @@ import type { NextRequest } from 'next/server'
 const SESSION_COOKIE_NAME = 'cloud-reader-session'
 
 // Rotas públicas que não precisam de autenticação
-const publicRoutes = ['/login', '/register']
+const publicRoutes = ['/login', '/register', '/auth/oidc/callback']
 
 export function middleware(request: NextRequest) {
   const { pathname } = request.nextUrl